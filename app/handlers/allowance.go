@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AllowanceHandler struct {
+	service services.AllowanceService
+}
+
+func NewAllowanceHandler(service services.AllowanceService) *AllowanceHandler {
+	return &AllowanceHandler{
+		service: service,
+	}
+}
+
+// GetAllowanceList godoc
+// @Summary      Get allowance assignments
+// @Description  Retrieves a paginated list of recurring allowance assignments, latest start date first, optionally for a single user. Admin only.
+// @Tags         allowance
+// @Accept       json
+// @Produce      json
+// @Param        user_id  query     int  false  "User ID"
+// @Param        page     query     int  false  "Page number"  default(1)
+// @Param        limit    query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /allowances [get]
+func (h *AllowanceHandler) GetAllowanceList(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.DefaultQuery("user_id", "0"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		return
+	}
+	pagination := utils.GetPagination(ctx)
+
+	assignments, total, err := h.service.GetAssignmentList(uint(userID), pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve allowances"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      assignments,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// CreateAllowance godoc
+// @Summary      Assign an allowance
+// @Description  Assigns a recurring allowance, such as transport, meal or position, to a user. It is added as a separate line to every payslip whose period overlaps its effective dates; leave end_date empty to keep it running. Admin only.
+// @Tags         allowance
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.AllowanceAssignmentRequest  true  "Allowance payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /allowances [post]
+func (h *AllowanceHandler) CreateAllowance(ctx *gin.Context) {
+	var req models.AllowanceAssignmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	assignment, err := h.service.CreateAssignment(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create allowance", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": assignment})
+}
+
+// UpdateAllowance godoc
+// @Summary      Update an allowance
+// @Description  Updates a recurring allowance assignment. Payslips already generated keep their amounts. Admin only.
+// @Tags         allowance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Allowance assignment ID"
+// @Param        body   body      models.AllowanceAssignmentRequest  true  "Allowance payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /allowances/{id} [put]
+func (h *AllowanceHandler) UpdateAllowance(ctx *gin.Context) {
+	var req models.AllowanceAssignmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	assignment, err := h.service.UpdateAssignment(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Allowance not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update allowance", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": assignment})
+}
+
+// DeleteAllowance godoc
+// @Summary      Delete an allowance
+// @Description  Removes a recurring allowance assignment. Payslips already generated keep their amounts. Admin only.
+// @Tags         allowance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Allowance assignment ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /allowances/{id} [delete]
+func (h *AllowanceHandler) DeleteAllowance(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteAssignment(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Allowance not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete allowance"})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// GetAdjustmentList godoc
+// @Summary      Get payroll adjustments
+// @Description  Retrieves the one-time adjustments of a payroll period, optionally for a single user. Admin only.
+// @Tags         allowance
+// @Accept       json
+// @Produce      json
+// @Param        payroll_period_id  query     int  true   "Payroll Period ID"
+// @Param        user_id            query     int  false  "User ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payroll-adjustments [get]
+func (h *AllowanceHandler) GetAdjustmentList(ctx *gin.Context) {
+	periodID, err := strconv.ParseUint(ctx.DefaultQuery("payroll_period_id", "0"), 10, 64)
+	if err != nil || periodID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "payroll_period_id is required"})
+		return
+	}
+	userID, err := strconv.ParseUint(ctx.DefaultQuery("user_id", "0"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		return
+	}
+
+	adjustments, err := h.service.GetAdjustmentList(uint(periodID), uint(userID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payroll adjustments"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": adjustments})
+}
+
+// CreateAdjustment godoc
+// @Summary      Add a payroll adjustment
+// @Description  Adds a one-time amount, such as a back-pay correction, to a user's payslip of a payroll period that has not been processed yet. A negative amount is deducted. Admin only.
+// @Tags         allowance
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.PayrollAdjustmentRequest  true  "Adjustment payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      409    {object}  map[string]interface{}  "Payroll period already processed"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payroll-adjustments [post]
+func (h *AllowanceHandler) CreateAdjustment(ctx *gin.Context) {
+	var req models.PayrollAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	adjustment, err := h.service.CreateAdjustment(ctx, &req)
+	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create payroll adjustment", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": adjustment})
+}
+
+// DeleteAdjustment godoc
+// @Summary      Delete a payroll adjustment
+// @Description  Removes a one-time adjustment while its payroll period has not been processed. Admin only.
+// @Tags         allowance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Adjustment ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Failure      409    {object}  map[string]interface{}  "Payroll period already processed"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payroll-adjustments/{id} [delete]
+func (h *AllowanceHandler) DeleteAdjustment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteAdjustment(uint(id)); err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Payroll adjustment not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payroll adjustment"})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
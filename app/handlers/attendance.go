@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
+	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/services"
 	"github.com/gin-gonic/gin"
 )
@@ -24,7 +27,7 @@ func NewAttendanceHandler(service services.AttendanceService, userService servic
 
 // GetAttendanceList godoc
 // @Summary      Get attendance list
-// @Description  Retrieves a list of attendance records for a specific user or all users if admin. Supports filtering by date range.
+// @Description  Retrieves a list of attendance records for a specific user or all users if admin. Supports filtering by date range. Each day includes its check-in/check-out sessions, and the response carries the worked hour totals.
 // @Tags         attendance
 // @Accept       json
 // @Produce      json
@@ -74,12 +77,12 @@ func (h *AttendanceHandler) GetAttendanceList(ctx *gin.Context) {
 		return
 	}
 
-	attendances, err := h.service.GetAttendanceList(uint(userID), startDate, endDate)
+	attendances, totals, err := h.service.GetAttendanceList(uint(userID), startDate, endDate)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get attendance list"})
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"data": attendances})
+	ctx.JSON(http.StatusOK, gin.H{"data": attendances, "totals": totals})
 }
 
 // RetrieveAttendance godoc
@@ -91,6 +94,7 @@ func (h *AttendanceHandler) GetAttendanceList(ctx *gin.Context) {
 // @Param        id     path      int  true  "Attendance ID"
 // @Success      200    {object}  map[string]interface{}  "Attendance record"
 // @Failure      400    {object}  map[string]string        "Invalid ID"
+// @Failure      403    {object}  map[string]string        "Forbidden access"
 // @Failure      404    {object}  map[string]string        "Attendance not found"
 // @Security     CookieAuth
 // @Security     BearerAuth
@@ -104,8 +108,11 @@ func (h *AttendanceHandler) RetrieveAttendance(ctx *gin.Context) {
 		return
 	}
 
-	attendance, err := h.service.GetAttendanceByID(uint(attendanceID))
+	attendance, err := h.service.GetAttendanceByID(ctx.GetUint("user_id"), uint(attendanceID))
 	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
 		return
 	}
@@ -114,13 +121,15 @@ func (h *AttendanceHandler) RetrieveAttendance(ctx *gin.Context) {
 
 // CheckIn godoc
 // @Summary      Check in attendance
-// @Description  Records a check-in for the current user. The user ID is obtained from the JWT token.
+// @Description  Records a check-in for the current user. The user ID is obtained from the JWT token. When office sites are configured the location is checked against their geofences.
 // @Tags         attendance
 // @Accept       json
 // @Produce      json
+// @Param        body   body      models.AttendanceRequest  false  "Device location"
 // @Success      200    {object}  map[string]interface{}  "Attendance record"
 // @Failure      400    {object}  map[string]string        "Invalid input"
 // @Failure      401    {object}  map[string]string        "Unauthorized"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
 // @Security     CookieAuth
 // @Security     BearerAuth
 // @Router       /attendances/check-in [post]
@@ -141,8 +150,17 @@ func (h *AttendanceHandler) CheckIn(ctx *gin.Context) {
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
 
-	attendance, err := h.service.CheckIn(ctx, currentUserIDUint)
+	var location models.AttendanceRequest
+	if err := ctx.ShouldBindJSON(&location); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	attendance, err := h.service.CheckIn(ctx, currentUserIDUint, &location)
 	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -151,14 +169,16 @@ func (h *AttendanceHandler) CheckIn(ctx *gin.Context) {
 
 // CheckOut godoc
 // @Summary      Check out attendance
-// @Description  Records a check-out for a specific user. The user ID is provided as a query parameter.
+// @Description  Records a check-out for the current user. The user ID is obtained from the JWT token. When office sites are configured the location is checked against their geofences.
 // @Tags         attendance
 // @Accept       json
 // @Produce      json
+// @Param        body      body      models.AttendanceRequest  false  "Device location"
 // @Success      200       {object}  map[string]interface{}  "Attendance record"
 // @Failure      400       {object}  map[string]string        "Invalid input"
 // @Failure      401       {object}  map[string]string        "Unauthorized"
 // @Failure      403       {object}  map[string]string        "Forbidden access"
+// @Failure      409       {object}  map[string]string  "Payroll period already processed"
 // @Security     CookieAuth
 // @Security     BearerAuth
 // @Router       /attendances/check-out [post]
@@ -178,8 +198,17 @@ func (h *AttendanceHandler) CheckOut(ctx *gin.Context) {
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
 
-	attendance, err := h.service.CheckOut(ctx, uint(userID))
+	var location models.AttendanceRequest
+	if err := ctx.ShouldBindJSON(&location); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	attendance, err := h.service.CheckOut(ctx, uint(userID), &location)
 	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AttendanceCorrectionHandler struct {
+	service           services.AttendanceCorrectionService
+	attendanceService services.AttendanceService
+}
+
+func NewAttendanceCorrectionHandler(service services.AttendanceCorrectionService, attendanceService services.AttendanceService) *AttendanceCorrectionHandler {
+	return &AttendanceCorrectionHandler{
+		service:           service,
+		attendanceService: attendanceService,
+	}
+}
+
+// RequestCorrection godoc
+// @Summary      Request attendance correction
+// @Description  Proposes a corrected check-in and/or check-out time for one of the authenticated user's attendances, to be approved by their manager or an admin.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Attendance ID"
+// @Param        body   body      models.AttendanceCorrectionRequest  true  "Correction payload"
+// @Success      201    {object}  map[string]interface{}  "Created correction request"
+// @Failure      400    {object}  map[string]string        "Invalid input"
+// @Failure      403    {object}  map[string]string        "Forbidden access"
+// @Failure      404    {object}  map[string]string        "Attendance not found"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendances/{id}/corrections [post]
+func (h *AttendanceCorrectionHandler) RequestCorrection(ctx *gin.Context) {
+	var req models.AttendanceCorrectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	currentUserID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized user access"})
+		return
+	}
+	currentUserIDUint, ok := currentUserID.(uint)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id type"})
+		return
+	}
+
+	attendanceID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance ID"})
+		return
+	}
+
+	attendance, err := h.attendanceService.GetAttendanceByID(currentUserIDUint, uint(attendanceID))
+	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
+		return
+	}
+	if attendance.UserID != currentUserIDUint {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to correct this attendance"})
+		return
+	}
+
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", currentUserIDUint))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	correction, err := h.service.RequestCorrection(ctx, attendance, &req)
+	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": correction})
+}
+
+// GetMyCorrectionList godoc
+// @Summary      Get my attendance corrections
+// @Description  Retrieves the correction requests submitted by the authenticated user, optionally filtered by status.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        status  query     string  false  "Correction status (pending, approved, rejected)"
+// @Param        page    query     int     false  "Page number"  default(1)
+// @Param        limit   query     int     false  "Number of items per page"  default(10)
+// @Success      200     {object}  map[string]interface{}
+// @Failure      500     {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendances/corrections [get]
+func (h *AttendanceCorrectionHandler) GetMyCorrectionList(ctx *gin.Context) {
+	filter := models.CorrectionFilter{
+		Status: ctx.DefaultQuery("status", ""),
+		UserID: ctx.GetUint("user_id"),
+	}
+	pagination := utils.GetPagination(ctx)
+
+	corrections, total, err := h.service.GetCorrectionList(filter, pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get attendance corrections"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      corrections,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// GetCorrectionList godoc
+// @Summary      Get attendance corrections
+// @Description  Retrieves attendance correction requests, optionally filtered by status. Admins see every request, managers only those of their direct reports.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        status  query     string  false  "Correction status (pending, approved, rejected)"
+// @Param        page    query     int     false  "Page number"  default(1)
+// @Param        limit   query     int     false  "Number of items per page"  default(10)
+// @Success      200     {object}  map[string]interface{}
+// @Failure      403     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendance-corrections [get]
+func (h *AttendanceCorrectionHandler) GetCorrectionList(ctx *gin.Context) {
+	status := ctx.DefaultQuery("status", "")
+	pagination := utils.GetPagination(ctx)
+
+	corrections, total, err := h.service.GetReviewableCorrectionList(ctx.GetUint("user_id"), status, pagination)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get attendance corrections"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      corrections,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// ApproveCorrection godoc
+// @Summary      Approve attendance correction
+// @Description  Approves a pending correction and applies the proposed times to the attendance. Admins may review any request, managers only those of their direct reports.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Correction ID"
+// @Param        body   body      models.CorrectionReviewRequest  false  "Review note"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      409    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendance-corrections/{id}/approve [post]
+func (h *AttendanceCorrectionHandler) ApproveCorrection(ctx *gin.Context) {
+	h.reviewCorrection(ctx, h.service.ApproveCorrection)
+}
+
+// RejectCorrection godoc
+// @Summary      Reject attendance correction
+// @Description  Rejects a pending correction without touching the attendance. Admins may review any request, managers only those of their direct reports.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Correction ID"
+// @Param        body   body      models.CorrectionReviewRequest  false  "Review note"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendance-corrections/{id}/reject [post]
+func (h *AttendanceCorrectionHandler) RejectCorrection(ctx *gin.Context) {
+	h.reviewCorrection(ctx, h.service.RejectCorrection)
+}
+
+func (h *AttendanceCorrectionHandler) reviewCorrection(ctx *gin.Context, review func(context.Context, *models.AttendanceCorrection, uint, *string) (*models.AttendanceCorrection, error)) {
+	var req models.CorrectionReviewRequest
+	_ = ctx.ShouldBindJSON(&req) // the review note is optional
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid correction ID"})
+		return
+	}
+
+	correction, err := h.service.GetCorrectionByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "correction not found"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	reviewed, err := review(ctx, correction, userID, req.Note)
+	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
+		if errors.Is(err, services.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": reviewed})
+}
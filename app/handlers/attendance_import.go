@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/gin-gonic/gin"
+)
+
+type AttendanceImportHandler struct {
+	service services.AttendanceImportService
+}
+
+func NewAttendanceImportHandler(service services.AttendanceImportService) *AttendanceImportHandler {
+	return &AttendanceImportHandler{
+		service: service,
+	}
+}
+
+// UpsertAttendance godoc
+// @Summary      Record attendance for a user
+// @Description  Creates the attendance of any user on a given date, or replaces the provided check-in/check-out times of an existing one. Weekends need a rostered shift or allow_weekend, and locked payroll periods are rejected. Admin only.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.AttendanceEntryRequest  true  "Attendance entry"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      409    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendances [put]
+func (h *AttendanceImportHandler) UpsertAttendance(ctx *gin.Context) {
+	var req models.AttendanceEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	attendance, err := h.service.UpsertAttendance(ctx, &req)
+	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": attendance})
+}
+
+// ImportAttendances godoc
+// @Summary      Import attendance from CSV
+// @Description  Imports attendance exported from fingerprint machines. The CSV header must contain user_id, date (YYYY-MM-DD), check_in and check_out (HH:MM or HH:MM:SS, local time). Invalid rows are skipped and reported; with dry_run nothing is written. With report=csv only the failed rows are returned as a CSV file. Admin only.
+// @Tags         attendance
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file            formData  file    true   "CSV file"
+// @Param        dry_run         query     bool    false  "Validate without writing"  default(false)
+// @Param        allow_weekends  query     bool    false  "Accept weekend rows without a rostered shift"  default(false)
+// @Param        report          query     string  false  "Set to csv to download the error report"
+// @Success      200    {object}  models.AttendanceImportResult
+// @Failure      400    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendances/import [post]
+func (h *AttendanceImportHandler) ImportAttendances(ctx *gin.Context) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	allowWeekends, _ := strconv.ParseBool(ctx.DefaultQuery("allow_weekends", "false"))
+
+	userID := ctx.GetUint("user_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+
+	result, err := h.service.ImportAttendances(ctx.Request.Context(), file, dryRun, allowWeekends)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if ctx.Query("report") == "csv" {
+		writeImportErrorReport(ctx, result)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// writeImportErrorReport sends the failed rows back as CSV so they can be fixed and re-imported.
+func writeImportErrorReport(ctx *gin.Context, result *models.AttendanceImportResult) {
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Header("Content-Disposition", `attachment; filename="attendance-import-errors.csv"`)
+	ctx.Status(http.StatusOK)
+
+	writer := csv.NewWriter(ctx.Writer)
+	_ = writer.Write([]string{"line", "user_id", "date", "error"})
+	for _, row := range result.Rows {
+		if row.Error == "" {
+			continue
+		}
+		_ = writer.Write([]string{strconv.Itoa(row.Line), strconv.FormatUint(uint64(row.UserID), 10), row.Date, row.Error})
+	}
+	writer.Flush()
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type DashboardHandler struct {
+	service services.DashboardService
+}
+
+func NewDashboardHandler(service services.DashboardService) *DashboardHandler {
+	return &DashboardHandler{
+		service: service,
+	}
+}
+
+// GetDailyBoard godoc
+// @Summary      Get daily team board
+// @Description  Shows whether each team member is present, late, absent, on leave, off or not yet checked in on a date. Admins see every employee, or the reports of manager_id; managers see their direct reports.
+// @Tags         dashboard
+// @Accept       json
+// @Produce      json
+// @Param        date        query     string  false  "Date (YYYY-MM-DD), defaults to today"
+// @Param        manager_id  query     int     false  "Limit the team to a manager's reports (admin only)"
+// @Success      200    {object}  models.DailyBoard
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /dashboard/daily [get]
+func (h *DashboardHandler) GetDailyBoard(ctx *gin.Context) {
+	date := utils.DateIn(time.Now(), utils.CompanyLocation())
+	if value := ctx.Query("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+			return
+		}
+		date = parsed
+	}
+	managerID, err := strconv.ParseUint(ctx.DefaultQuery("manager_id", "0"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid manager_id"})
+		return
+	}
+
+	board, err := h.service.GetDailyBoard(ctx.GetUint("user_id"), uint(managerID), date)
+	if err != nil {
+		respondDashboardError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": board})
+}
+
+// GetMonthlyMatrix godoc
+// @Summary      Get monthly team matrix
+// @Description  Returns a users × days grid with one status per day of the month, for heat-map style reports. Team scoping follows the daily board.
+// @Tags         dashboard
+// @Accept       json
+// @Produce      json
+// @Param        month       query     string  false  "Month (YYYY-MM), defaults to the current month"
+// @Param        manager_id  query     int     false  "Limit the team to a manager's reports (admin only)"
+// @Success      200    {object}  models.MonthlyMatrix
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /dashboard/monthly [get]
+func (h *DashboardHandler) GetMonthlyMatrix(ctx *gin.Context) {
+	month := utils.DateIn(time.Now(), utils.CompanyLocation())
+	if value := ctx.Query("month"); value != "" {
+		parsed, err := time.Parse("2006-01", value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "month must be in YYYY-MM format"})
+			return
+		}
+		month = parsed
+	}
+	managerID, err := strconv.ParseUint(ctx.DefaultQuery("manager_id", "0"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid manager_id"})
+		return
+	}
+
+	matrix, err := h.service.GetMonthlyMatrix(ctx.GetUint("user_id"), uint(managerID), month)
+	if err != nil {
+		respondDashboardError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": matrix})
+}
+
+func respondDashboardError(ctx *gin.Context, err error) {
+	if errors.Is(err, services.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "manager not found"})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build attendance dashboard"})
+}
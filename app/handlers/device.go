@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type DeviceHandler struct {
+	service      services.DeviceService
+	punchService services.PunchService
+}
+
+func NewDeviceHandler(service services.DeviceService, punchService services.PunchService) *DeviceHandler {
+	return &DeviceHandler{
+		service:      service,
+		punchService: punchService,
+	}
+}
+
+// GetDeviceList godoc
+// @Summary      Get list of attendance devices
+// @Description  Retrieves a paginated list of registered attendance terminals. Admin only.
+// @Tags         device
+// @Accept       json
+// @Produce      json
+// @Param        page   query     int  false  "Page number"  default(1)
+// @Param        limit  query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /devices [get]
+func (h *DeviceHandler) GetDeviceList(ctx *gin.Context) {
+	pagination := utils.GetPagination(ctx)
+
+	devices, total, err := h.service.GetDeviceList(pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve devices"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      devices,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// GetDeviceByID godoc
+// @Summary      Get attendance device by ID
+// @Description  Retrieves a specific attendance terminal by its ID. Admin only.
+// @Tags         device
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Device ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /devices/{id} [get]
+func (h *DeviceHandler) GetDeviceByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	device, err := h.service.GetDeviceByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": device})
+}
+
+// CreateDevice godoc
+// @Summary      Register an attendance device
+// @Description  Registers a terminal and issues its API key. The key is only returned in this response. Admin only.
+// @Tags         device
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.DeviceRequest  true  "Device payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /devices [post]
+func (h *DeviceHandler) CreateDevice(ctx *gin.Context) {
+	var req models.DeviceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	device, err := h.service.CreateDevice(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create device", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": device})
+}
+
+// UpdateDevice godoc
+// @Summary      Update an attendance device
+// @Description  Updates an existing attendance terminal. Deactivated devices can no longer push punches. Admin only.
+// @Tags         device
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Device ID"
+// @Param        body   body      models.DeviceRequest  true  "Device payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /devices/{id} [put]
+func (h *DeviceHandler) UpdateDevice(ctx *gin.Context) {
+	var req models.DeviceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	device, err := h.service.UpdateDevice(ctx, uint(id), &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update device", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": device})
+}
+
+// RotateDeviceKey godoc
+// @Summary      Rotate a device API key
+// @Description  Issues a new API key for a terminal; the previous key stops working. The key is only returned in this response. Admin only.
+// @Tags         device
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Device ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /devices/{id}/rotate-key [post]
+func (h *DeviceHandler) RotateDeviceKey(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	device, err := h.service.RotateAPIKey(ctx, uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to rotate device key", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": device})
+}
+
+// DeleteDevice godoc
+// @Summary      Delete an attendance device
+// @Description  Deletes an attendance terminal by its ID. Admin only.
+// @Tags         device
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Device ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /devices/{id} [delete]
+func (h *DeviceHandler) DeleteDevice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteDevice(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete device"})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// IngestPunches godoc
+// @Summary      Push punches from a device
+// @Description  Accepts a batch of punches from an attendance terminal authenticated by its API key. Resending punches is safe: duplicates are ignored, and every affected attendance day is re-paired from all of its punches, so late or out of order punches are handled.
+// @Tags         device
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.PunchBatchRequest  true  "Punch batch"
+// @Success      200    {object}  models.PunchIngestResult
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      401    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     DeviceKey
+// @Router       /punches [post]
+func (h *DeviceHandler) IngestPunches(ctx *gin.Context) {
+	var req models.PunchBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	device, ok := ctx.MustGet("device").(*models.Device)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "invalid device in context"})
+		return
+	}
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	result, err := h.punchService.IngestPunches(ctx.Request.Context(), device, req.Punches)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to ingest punches", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/gin-gonic/gin"
+)
+
+// respondPeriodLocked answers 409 Conflict when a change was refused because its date is in a
+// processed payroll period, and reports whether it did.
+func respondPeriodLocked(ctx *gin.Context, err error) bool {
+	if !errors.Is(err, services.ErrPeriodLocked) {
+		return false
+	}
+	ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	return true
+}
+
+// respondForbidden answers 403 Forbidden when the caller may not act on the record, and reports
+// whether it did.
+func respondForbidden(ctx *gin.Context, err error) bool {
+	if !errors.Is(err, services.ErrForbidden) {
+		return false
+	}
+	ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	return true
+}
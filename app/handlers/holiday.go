@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/gin-gonic/gin"
+)
+
+type HolidayHandler struct {
+	service services.HolidayService
+}
+
+func NewHolidayHandler(service services.HolidayService) *HolidayHandler {
+	return &HolidayHandler{
+		service: service,
+	}
+}
+
+// GetHolidayList godoc
+// @Summary      Get holidays
+// @Description  Retrieves the public holidays of a year. Admin only.
+// @Tags         holiday
+// @Accept       json
+// @Produce      json
+// @Param        year   query     int  false  "Year, defaults to the current year"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /holidays [get]
+func (h *HolidayHandler) GetHolidayList(ctx *gin.Context) {
+	year, err := strconv.Atoi(ctx.DefaultQuery("year", strconv.Itoa(time.Now().Year())))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return
+	}
+
+	holidays, err := h.service.GetHolidayList(year)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve holidays"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": holidays})
+}
+
+// CreateHoliday godoc
+// @Summary      Declare a holiday
+// @Description  Declares a public holiday. Overtime worked on it is paid at the holiday overtime rates. Admin only.
+// @Tags         holiday
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.HolidayRequest  true  "Holiday payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /holidays [post]
+func (h *HolidayHandler) CreateHoliday(ctx *gin.Context) {
+	var req models.HolidayRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+
+	holiday, err := h.service.CreateHoliday(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to declare holiday", "details": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": holiday})
+}
+
+// DeleteHoliday godoc
+// @Summary      Delete a holiday
+// @Description  Removes a declared holiday. Admin only.
+// @Tags         holiday
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Holiday ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /holidays/{id} [delete]
+func (h *HolidayHandler) DeleteHoliday(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteHoliday(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete holiday"})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
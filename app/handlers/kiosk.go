@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/gin-gonic/gin"
+)
+
+type KioskHandler struct {
+	service services.KioskService
+}
+
+func NewKioskHandler(service services.KioskService) *KioskHandler {
+	return &KioskHandler{
+		service: service,
+	}
+}
+
+// GetKioskToken godoc
+// @Summary      Get kiosk QR token
+// @Description  Issues a short-lived QR token for a kiosk assigned to a site. The kiosk should request a new token before expires_at and display it as a QR code.
+// @Tags         kiosk
+// @Accept       json
+// @Produce      json
+// @Success      200    {object}  models.KioskToken
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      401    {object}  map[string]interface{}
+// @Security     DeviceKey
+// @Router       /kiosk/token [get]
+func (h *KioskHandler) GetKioskToken(ctx *gin.Context) {
+	device, ok := ctx.MustGet("device").(*models.Device)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "invalid device in context"})
+		return
+	}
+
+	token, err := h.service.IssueToken(device)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": token})
+}
+
+// KioskCheckIn godoc
+// @Summary      Check in at a kiosk
+// @Description  Checks the current user in with the QR token scanned from a kiosk. The token must still be fresh and the kiosk must still be assigned to the site it was issued for.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.KioskCheckInRequest  true  "Scanned kiosk token"
+// @Success      200    {object}  map[string]interface{}  "Attendance record"
+// @Failure      400    {object}  map[string]string        "Invalid input"
+// @Failure      401    {object}  map[string]string        "Unauthorized"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /attendances/kiosk-check-in [post]
+func (h *KioskHandler) KioskCheckIn(ctx *gin.Context) {
+	var req models.KioskCheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	attendance, err := h.service.CheckIn(ctx, userID, req.Token)
+	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": attendance})
+}
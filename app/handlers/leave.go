@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type LeaveHandler struct {
+	service services.LeaveService
+}
+
+func NewLeaveHandler(service services.LeaveService) *LeaveHandler {
+	return &LeaveHandler{
+		service: service,
+	}
+}
+
+// GetLeaveList godoc
+// @Summary      Get leaves
+// @Description  Retrieves the leaves overlapping a date range, optionally for a single user. Admin only.
+// @Tags         leave
+// @Accept       json
+// @Produce      json
+// @Param        user_id    query     int     false  "User ID"
+// @Param        start_date query     string  true   "Start date (YYYY-MM-DD)"
+// @Param        end_date   query     string  true   "End date (YYYY-MM-DD)"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /leaves [get]
+func (h *LeaveHandler) GetLeaveList(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.DefaultQuery("user_id", "0"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		return
+	}
+	startDate := ctx.DefaultQuery("start_date", "")
+	endDate := ctx.DefaultQuery("end_date", "")
+	if startDate == "" || endDate == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required"})
+		return
+	}
+
+	leaves, err := h.service.GetLeaveList(uint(userID), startDate, endDate)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve leaves"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": leaves})
+}
+
+// CreateLeave godoc
+// @Summary      Record a leave
+// @Description  Records an approved leave of a user over an inclusive date range. The days show as on leave on the team dashboard. Admin only.
+// @Tags         leave
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.LeaveRequest  true  "Leave payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /leaves [post]
+func (h *LeaveHandler) CreateLeave(ctx *gin.Context) {
+	var req models.LeaveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+
+	leave, err := h.service.CreateLeave(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to record leave", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": leave})
+}
+
+// DeleteLeave godoc
+// @Summary      Delete a leave
+// @Description  Removes a recorded leave. Admin only.
+// @Tags         leave
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Leave ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /leaves/{id} [delete]
+func (h *LeaveHandler) DeleteLeave(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteLeave(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Leave not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete leave"})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
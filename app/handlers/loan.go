@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type LoanHandler struct {
+	service services.LoanService
+}
+
+func NewLoanHandler(service services.LoanService) *LoanHandler {
+	return &LoanHandler{
+		service: service,
+	}
+}
+
+// GetLoanList godoc
+// @Summary      Get loans
+// @Description  Retrieves a paginated list of employee loans and salary advances, latest first, optionally for a single user. Admin only.
+// @Tags         loan
+// @Accept       json
+// @Produce      json
+// @Param        user_id  query     int  false  "User ID"
+// @Param        page     query     int  false  "Page number"  default(1)
+// @Param        limit    query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /loans [get]
+func (h *LoanHandler) GetLoanList(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.DefaultQuery("user_id", "0"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		return
+	}
+	h.respondLoanList(ctx, uint(userID))
+}
+
+// GetMyLoanList godoc
+// @Summary      Get my loans
+// @Description  Retrieves the authenticated user's loans and salary advances with their remaining balance, latest first.
+// @Tags         loan
+// @Accept       json
+// @Produce      json
+// @Param        page   query     int  false  "Page number"  default(1)
+// @Param        limit  query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /loans/me [get]
+func (h *LoanHandler) GetMyLoanList(ctx *gin.Context) {
+	h.respondLoanList(ctx, ctx.GetUint("user_id"))
+}
+
+func (h *LoanHandler) respondLoanList(ctx *gin.Context, userID uint) {
+	pagination := utils.GetPagination(ctx)
+
+	loans, total, err := h.service.GetLoanList(userID, pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve loans"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      loans,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// GetLoanByID godoc
+// @Summary      Get loan by ID
+// @Description  Retrieves a loan with its installment schedule. Employees can only view their own loans; admins can view any.
+// @Tags         loan
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Loan ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      403    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /loans/{id} [get]
+func (h *LoanHandler) GetLoanByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	loan, err := h.service.GetLoanByID(ctx.GetUint("user_id"), uint(id))
+	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": loan})
+}
+
+// CreateLoan godoc
+// @Summary      Grant a loan
+// @Description  Records a company loan or salary advance repaid in monthly installments, the first due on first_due_date. Each payroll run deducts the installments due by the end of its period and marks them paid. Admin only.
+// @Tags         loan
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.LoanRequest  true  "Loan payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /loans [post]
+func (h *LoanHandler) CreateLoan(ctx *gin.Context) {
+	var req models.LoanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	loan, err := h.service.CreateLoan(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create loan", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": loan})
+}
+
+// DeleteLoan godoc
+// @Summary      Delete a loan
+// @Description  Deletes a loan and its installment schedule. Loans with paid installments cannot be deleted. Admin only.
+// @Tags         loan
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Loan ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Failure      409    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /loans/{id} [delete]
+func (h *LoanHandler) DeleteLoan(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteLoan(uint(id)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+		case errors.Is(err, services.ErrLoanRepaymentStarted):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete loan"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	service services.NotificationService
+}
+
+func NewNotificationHandler(service services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+	}
+}
+
+// GetNotificationList godoc
+// @Summary      Get my notifications
+// @Description  Retrieves the authenticated user's notifications, newest first.
+// @Tags         notification
+// @Accept       json
+// @Produce      json
+// @Param        page   query     int  false  "Page number"  default(1)
+// @Param        limit  query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      401    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /notifications [get]
+func (h *NotificationHandler) GetNotificationList(ctx *gin.Context) {
+	currentUserID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized user access"})
+		return
+	}
+	currentUserIDUint, ok := currentUserID.(uint)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id type"})
+		return
+	}
+
+	pagination := utils.GetPagination(ctx)
+
+	notifications, total, err := h.service.GetNotificationList(currentUserIDUint, pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notifications"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      notifications,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// MarkNotificationAsRead godoc
+// @Summary      Mark notification as read
+// @Description  Marks one of the authenticated user's notifications as read.
+// @Tags         notification
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Notification ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationAsRead(ctx *gin.Context) {
+	currentUserID := ctx.GetUint("user_id")
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return
+	}
+
+	notification, err := h.service.GetNotificationByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		return
+	}
+	if notification.UserID != currentUserID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to access this notification"})
+		return
+	}
+
+	if err := h.service.MarkAsRead(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mark notification as read"})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
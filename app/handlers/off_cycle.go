@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type OffCycleHandler struct {
+	service services.OffCycleService
+}
+
+func NewOffCycleHandler(service services.OffCycleService) *OffCycleHandler {
+	return &OffCycleHandler{
+		service: service,
+	}
+}
+
+// GetOffCycleRunList godoc
+// @Summary      Get off-cycle payroll runs
+// @Description  Retrieves a paginated list of off-cycle payroll runs (bonus, THR, final settlement), latest pay date first. Admin only.
+// @Tags         off-cycle
+// @Accept       json
+// @Produce      json
+// @Param        page   query     int  false  "Page number"  default(1)
+// @Param        limit  query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /off-cycle-runs [get]
+func (h *OffCycleHandler) GetOffCycleRunList(ctx *gin.Context) {
+	pagination := utils.GetPagination(ctx)
+
+	runs, total, err := h.service.GetRunList(pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve off-cycle runs"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      runs,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// GetOffCycleRunByID godoc
+// @Summary      Get off-cycle payroll run by ID
+// @Description  Retrieves an off-cycle payroll run with the payslips of its employees. Admin only.
+// @Tags         off-cycle
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Off-cycle run ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /off-cycle-runs/{id} [get]
+func (h *OffCycleHandler) GetOffCycleRunByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	run, err := h.service.GetRunByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Off-cycle run not found"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// CreateOffCycleRun godoc
+// @Summary      Create an off-cycle payroll run
+// @Description  Drafts an off-cycle payroll run of the given type for a chosen set of employees, each with manually entered earnings lines. Every employee gets an off-cycle payslip totalling their lines; regular period payslips are not affected. An employee can have only one final settlement. Admin only.
+// @Tags         off-cycle
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.OffCycleRunRequest  true  "Off-cycle run payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /off-cycle-runs [post]
+func (h *OffCycleHandler) CreateOffCycleRun(ctx *gin.Context) {
+	var req models.OffCycleRunRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	run, err := h.service.CreateRun(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create off-cycle run", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": run})
+}
+
+// ProcessOffCycleRun godoc
+// @Summary      Process an off-cycle payroll run
+// @Description  Finalises a drafted off-cycle run. Its payslips become visible to the employees and the run can no longer be deleted. Admin only.
+// @Tags         off-cycle
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Off-cycle run ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Failure      409    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /off-cycle-runs/{id}/process [post]
+func (h *OffCycleHandler) ProcessOffCycleRun(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+
+	run, err := h.service.ProcessRun(ctx, uint(id))
+	if err != nil {
+		respondOffCycleError(ctx, err, "Failed to process off-cycle run")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// DeleteOffCycleRun godoc
+// @Summary      Delete an off-cycle payroll run
+// @Description  Deletes a drafted off-cycle run and its payslips. Processed runs cannot be deleted. Admin only.
+// @Tags         off-cycle
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Off-cycle run ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Failure      409    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /off-cycle-runs/{id} [delete]
+func (h *OffCycleHandler) DeleteOffCycleRun(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteRun(uint(id)); err != nil {
+		respondOffCycleError(ctx, err, "Failed to delete off-cycle run")
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// GetMyOffCyclePayslips godoc
+// @Summary      Get my off-cycle payslips
+// @Description  Retrieves the authenticated user's payslips from processed off-cycle runs, latest pay date first.
+// @Tags         payslip
+// @Accept       json
+// @Produce      json
+// @Success      200    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payslips/off-cycle [get]
+func (h *OffCycleHandler) GetMyOffCyclePayslips(ctx *gin.Context) {
+	payslips, err := h.service.GetMyPayslips(ctx.GetUint("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve off-cycle payslips"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": payslips})
+}
+
+func respondOffCycleError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Off-cycle run not found"})
+	case errors.Is(err, services.ErrOffCycleProcessed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"github.com/galiherlangga/go-attendance/app/services"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type OvertimeHandler struct {
@@ -99,6 +101,7 @@ func (h *OvertimeHandler) GetOvertimeList(ctx *gin.Context) {
 // @Param        id     path      int  true  "Overtime ID"
 // @Success      200    {object}  map[string]interface{}  "Overtime record"
 // @Failure      400    {object}  map[string]string        "Invalid ID"
+// @Failure      403    {object}  map[string]string        "Forbidden access"
 // @Failure      404    {object}  map[string]string        "Overtime not found"
 // @Security     CookieAuth
 // @Security     BearerAuth
@@ -110,8 +113,11 @@ func (h *OvertimeHandler) GetOvertimeByID(ctx *gin.Context) {
 		return
 	}
 
-	overtime, err := h.service.GetOvertimeByID(uint(id))
+	overtime, err := h.service.GetOvertimeByID(ctx.GetUint("user_id"), uint(id))
 	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Overtime not found"})
 		return
 	}
@@ -129,6 +135,7 @@ func (h *OvertimeHandler) GetOvertimeByID(ctx *gin.Context) {
 // @Success      201   {object}  models.OvertimeResponse  "Created overtime record"
 // @Failure      400   {object}  map[string]string  "Invalid input"
 // @Failure      401   {object}  map[string]string  "Unauthorized"
+// @Failure      409   {object}  map[string]string  "Payroll period already processed"
 // @Failure      500   {object}  map[string]string  "Internal server error"
 // @Security     CookieAuth
 // @Security     BearerAuth
@@ -164,6 +171,9 @@ func (h *OvertimeHandler) CreateOvertime(ctx *gin.Context) {
 
 	createdOvertime, err := h.service.SubmitOvertime(ctx, &overtime)
 	if err != nil {
+		if respondPeriodLocked(ctx, err) {
+			return
+		}
 		log.Println(err.Error())
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create overtime", "details": err.Error()})
 		return
@@ -174,7 +184,7 @@ func (h *OvertimeHandler) CreateOvertime(ctx *gin.Context) {
 
 // UpdateOvertime godoc
 // @Summary      Update overtime
-// @Description  Updates an existing overtime record for the authenticated user.
+// @Description  Updates an existing overtime record. Admins can update any record, while users can only update their own.
 // @Tags         overtime
 // @Accept       json
 // @Produce      json
@@ -183,6 +193,9 @@ func (h *OvertimeHandler) CreateOvertime(ctx *gin.Context) {
 // @Success      200   {object}  models.OvertimeResponse  "Updated overtime record"
 // @Failure      400   {object}  map[string]string  "Invalid input"
 // @Failure      401   {object}  map[string]string  "Unauthorized"
+// @Failure      403   {object}  map[string]string  "Forbidden access"
+// @Failure      404   {object}  map[string]string  "Overtime not found"
+// @Failure      409   {object}  map[string]string  "Payroll period already processed"
 // @Failure      500   {object}  map[string]string  "Internal server error"
 // @Security     CookieAuth
 // @Security     BearerAuth
@@ -215,21 +228,23 @@ func (h *OvertimeHandler) UpdateOvertime(ctx *gin.Context) {
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", currentUserIDUint))
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
 
-	overtime, err := h.service.GetOvertimeByID(uint(overtimeID))
+	overtime, err := h.service.GetOvertimeByID(currentUserIDUint, uint(overtimeID))
 	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Overtime not found"})
 		return
 	}
 	overtime.Date = overtimeReq.Date
 	overtime.Hours = overtimeReq.Hours
 	overtime.Note = overtimeReq.Note
-	if overtime.UserID != currentUserIDUint {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this overtime"})
-		return
-	}
 
-	updatedOvertime, err := h.service.UpdateOvertime(ctx, overtime)
+	updatedOvertime, err := h.service.UpdateOvertime(ctx, currentUserIDUint, overtime)
 	if err != nil {
+		if respondPeriodLocked(ctx, err) || respondForbidden(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update overtime"})
 		return
 	}
@@ -248,6 +263,7 @@ func (h *OvertimeHandler) UpdateOvertime(ctx *gin.Context) {
 // @Failure      400    {object}  map[string]string  "Invalid ID"
 // @Failure      403    {object}  map[string]string  "Forbidden access"
 // @Failure      404    {object}  map[string]string  "Overtime not found"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
 // @Security     CookieAuth
 // @Security     BearerAuth
 // @Router       /overtimes/{id} [delete]
@@ -258,10 +274,75 @@ func (h *OvertimeHandler) DeleteOvertime(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteOvertime(uint(id)); err != nil {
+	if err := h.service.DeleteOvertime(ctx.GetUint("user_id"), uint(id)); err != nil {
+		if respondPeriodLocked(ctx, err) || respondForbidden(ctx, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Overtime not found"})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete overtime"})
 		return
 	}
 
 	ctx.JSON(http.StatusNoContent, nil)
 }
+
+// ConfirmOvertime godoc
+// @Summary      Confirm proposed overtime
+// @Description  Confirms overtime that was proposed from the authenticated user's attendance, submitting it for payroll.
+// @Tags         overtime
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Overtime ID"
+// @Success      200    {object}  models.OvertimeResponse  "Confirmed overtime record"
+// @Failure      400    {object}  map[string]string  "Invalid input"
+// @Failure      403    {object}  map[string]string  "Forbidden access"
+// @Failure      404    {object}  map[string]string  "Overtime not found"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /overtimes/{id}/confirm [post]
+func (h *OvertimeHandler) ConfirmOvertime(ctx *gin.Context) {
+	currentUserID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in middleware"})
+		return
+	}
+	currentUserIDUint, ok := currentUserID.(uint)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id type"})
+		return
+	}
+
+	overtimeID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", currentUserIDUint))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	overtime, err := h.service.GetOvertimeByID(currentUserIDUint, uint(overtimeID))
+	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Overtime not found"})
+		return
+	}
+
+	confirmedOvertime, err := h.service.ConfirmOvertime(ctx, currentUserIDUint, overtime)
+	if err != nil {
+		if respondPeriodLocked(ctx, err) || respondForbidden(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to confirm overtime", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": confirmedOvertime})
+}
\ No newline at end of file
@@ -2,23 +2,28 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/services"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type PayrollPeriodHandler struct {
-	service services.PayrollPeriodService
+	service         services.PayrollPeriodService
+	calendarService services.PayrollCalendarService
 }
 
-func NewPayrollPeriodHandler(service services.PayrollPeriodService) *PayrollPeriodHandler {
+func NewPayrollPeriodHandler(service services.PayrollPeriodService, calendarService services.PayrollCalendarService) *PayrollPeriodHandler {
 	return &PayrollPeriodHandler{
-		service: service,
+		service:         service,
+		calendarService: calendarService,
 	}
 }
 
@@ -83,13 +88,14 @@ func (h *PayrollPeriodHandler) GetPayrollPeriodByID(ctx *gin.Context) {
 
 // CreatePayrollPeriod godoc
 // @Summary      Create a payroll period
-// @Description  Creates a new payroll period. Admin only.
+// @Description  Creates a new payroll period. Admin only. The period may not overlap another period, and when it comes after the latest period it must start the day after that one ends.
 // @Tags         payroll
 // @Accept       json
 // @Produce      json
 // @Param        body   body      models.PayrollPeriodExample  true  "Payroll Period payload"
 // @Success      201    {object}  map[string]interface{}
 // @Failure      400    {object}  map[string]interface{}
+// @Failure      409    {object}  map[string]interface{}
 // @Failure      500    {object}  map[string]interface{}
 // @Security     CookieAuth
 // @Security     BearerAuth
@@ -108,6 +114,9 @@ func (h *PayrollPeriodHandler) CreatePayrollPeriod(ctx *gin.Context) {
 
 	createdPeriod, err := h.service.CreatePayrollPeriod(ctx, &period)
 	if err != nil {
+		if respondPeriodValidation(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payroll period"})
 		return
 	}
@@ -117,7 +126,7 @@ func (h *PayrollPeriodHandler) CreatePayrollPeriod(ctx *gin.Context) {
 
 // UpdatePayrollPeriod godoc
 // @Summary      Update a payroll period
-// @Description  Updates an existing payroll period. Admin only.
+// @Description  Updates an existing payroll period. Admin only. The period may not overlap another period.
 // @Tags         payroll
 // @Accept       json
 // @Produce      json
@@ -126,6 +135,7 @@ func (h *PayrollPeriodHandler) CreatePayrollPeriod(ctx *gin.Context) {
 // @Success      200    {object}  map[string]interface{}
 // @Failure      400    {object}  map[string]interface{}
 // @Failure      404    {object}  map[string]interface{}
+// @Failure      409    {object}  map[string]interface{}
 // @Security     CookieAuth
 // @Security     BearerAuth
 // @Router       /payroll-periods/{id} [put]
@@ -149,6 +159,9 @@ func (h *PayrollPeriodHandler) UpdatePayrollPeriod(ctx *gin.Context) {
 
 	updatedPeriod, err := h.service.UpdatePayrollPeriod(ctx, &period)
 	if err != nil {
+		if respondPeriodValidation(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll period"})
 		return
 	}
@@ -158,7 +171,7 @@ func (h *PayrollPeriodHandler) UpdatePayrollPeriod(ctx *gin.Context) {
 
 // DeletePayrollPeriod godoc
 // @Summary      Delete a payroll period
-// @Description  Deletes a payroll period by its ID. Admin only.
+// @Description  Deletes a payroll period by its ID. Deleting a processed period voids its payslips and reverses the loan installments they deducted. Admin only.
 // @Tags         payroll
 // @Accept       json
 // @Produce      json
@@ -177,6 +190,10 @@ func (h *PayrollPeriodHandler) DeletePayrollPeriod(ctx *gin.Context) {
 	}
 
 	if err := h.service.DeletePayrollPeriod(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Payroll period not found"})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payroll period"})
 		return
 	}
@@ -214,3 +231,113 @@ func (h *PayrollPeriodHandler) RunPayrollPeriod(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Payroll period successfully run"})
 }
+
+// ReopenPayrollPeriod godoc
+// @Summary      Reopen payroll period
+// @Description  Undoes the payroll run of a processed period: its payslips are voided, the loan installments they deducted are reversed and the period is unlocked so it can be corrected and run again. Admin only.
+// @Tags         payroll
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Payroll Period ID"
+// @Success      200    {object}  map[string]string
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      409    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payroll-periods/{id}/reopen [post]
+func (h *PayrollPeriodHandler) ReopenPayrollPeriod(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.ReopenPayrollPeriod(ctx, uint(id)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Payroll period not found"})
+		case errors.Is(err, services.ErrPeriodNotProcessed):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reopen payroll period"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Payroll period successfully reopened"})
+}
+
+// PreviewPayrollPeriod godoc
+// @Summary      Preview payroll period
+// @Description  Dry-runs the payroll of a period: calculates every employee's payslip like run-payroll does and compares the take-home pay with the previous period, without saving payslips or processing the period. Rows are flagged for a missing salary, zero attendance, negative take-home pay or a take-home pay change above swing_percent. Admin only.
+// @Tags         payroll
+// @Accept       json
+// @Produce      json
+// @Param        id             path      int     true   "Payroll Period ID"
+// @Param        swing_percent  query     number  false  "Take-home pay change (in percent) flagged as an anomaly"  default(20)
+// @Success      200    {object}  models.PayrollPreview
+// @Failure      400    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payroll-periods/{id}/preview [get]
+func (h *PayrollPeriodHandler) PreviewPayrollPeriod(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	swingPercent, err := strconv.ParseFloat(ctx.DefaultQuery("swing_percent", "20"), 64)
+	if err != nil || swingPercent < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "swing_percent must be a non-negative number"})
+		return
+	}
+
+	preview, err := h.service.PreviewPayroll(uint(id), swingPercent)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview payroll period", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": preview})
+}
+
+// GeneratePayrollPeriods godoc
+// @Summary      Generate upcoming payroll periods
+// @Description  Creates the periods of the configured payroll calendar (monthly, semi-monthly or bi-weekly) that don't exist yet, up to the configured number of periods ahead. New periods continue from the latest existing one. Admin only.
+// @Tags         payroll
+// @Accept       json
+// @Produce      json
+// @Success      201    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payroll-periods/generate [post]
+func (h *PayrollPeriodHandler) GeneratePayrollPeriods(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+
+	periods, err := h.calendarService.GeneratePayrollPeriods(ctx, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate payroll periods", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": periods, "total": len(periods)})
+}
+
+// respondPeriodValidation answers a rejected payroll period range with 400 or 409, and reports
+// whether it did.
+func respondPeriodValidation(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrInvalidPeriodRange):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPeriodOverlap), errors.Is(err, services.ErrPeriodGap):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
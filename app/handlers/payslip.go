@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -119,3 +122,104 @@ func (h *PayslipHandler) GetPayslipSummary(ctx *gin.Context) {
 		"total":   total,
 	})
 }
+
+// GetPayslipHistory godoc
+// @Summary      Get payslip history
+// @Description  Retrieves a user's payslips, latest payroll period first. Each payslip carries the year-to-date gross, deductions and net of the calendar year its period ends in, off-cycle payslips included. Defaults to the authenticated user; only admins can view other users.
+// @Tags         payslip
+// @Accept       json
+// @Produce      json
+// @Param        user_id   query     int  false  "User ID, defaults to the authenticated user"
+// @Param        page      query     int  false  "Page number"  default(1)
+// @Param        limit     query     int  false  "Number of items per page"  default(10)
+// @Success      200       {object}  map[string]interface{}  "List of payslips"
+// @Failure      400       {object}  map[string]string        "Invalid input"
+// @Failure      403       {object}  map[string]string        "Forbidden access"
+// @Failure      500       {object}  map[string]string        "Internal server error"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payslips/history [get]
+func (h *PayslipHandler) GetPayslipHistory(ctx *gin.Context) {
+	userID, ok := h.resolveUserID(ctx)
+	if !ok {
+		return
+	}
+	pagination := utils.GetPagination(ctx)
+
+	payslips, total, err := h.service.GetPayslipHistory(userID, pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payslip history: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      payslips,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// GetEarningsStatement godoc
+// @Summary      Get yearly earnings statement
+// @Description  Retrieves a user's earnings over a calendar year for annual tax forms: the regular payslips of the periods ending in the year and the processed off-cycle payslips paid in it, with their gross, deductions and net totals. Defaults to the authenticated user; only admins can view other users.
+// @Tags         payslip
+// @Accept       json
+// @Produce      json
+// @Param        user_id   query     int  false  "User ID, defaults to the authenticated user"
+// @Param        year      query     int  false  "Year, defaults to the current year"
+// @Success      200       {object}  map[string]interface{}  "Earnings statement"
+// @Failure      400       {object}  map[string]string        "Invalid input"
+// @Failure      403       {object}  map[string]string        "Forbidden access"
+// @Failure      500       {object}  map[string]string        "Internal server error"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payslips/statement [get]
+func (h *PayslipHandler) GetEarningsStatement(ctx *gin.Context) {
+	userID, ok := h.resolveUserID(ctx)
+	if !ok {
+		return
+	}
+	year, err := strconv.Atoi(ctx.DefaultQuery("year", strconv.Itoa(utils.DateIn(time.Now(), utils.CompanyLocation()).Year())))
+	if err != nil || year < 1 || year > 9999 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+		return
+	}
+
+	statement, err := h.service.GetEarningsStatement(userID, year)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get earnings statement: " + err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": statement})
+}
+
+// resolveUserID reads the optional user_id query, defaulting to the authenticated user. Only
+// admins may name another user; otherwise the response is written and false returned.
+func (h *PayslipHandler) resolveUserID(ctx *gin.Context) (uint, bool) {
+	currentUserID := ctx.GetUint("user_id")
+	userIDParam := ctx.Query("user_id")
+	if userIDParam == "" {
+		return currentUserID, true
+	}
+	userID, err := strconv.ParseUint(userIDParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return 0, false
+	}
+	if uint(userID) == currentUserID {
+		return currentUserID, true
+	}
+
+	isAdmin, err := h.userService.IsAdmin(currentUserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check user role"})
+		return 0, false
+	}
+	if !isAdmin {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to access this user's payslips"})
+		return 0, false
+	}
+	return uint(userID), true
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PayslipDeliveryHandler struct {
+	service services.PayslipDeliveryService
+}
+
+func NewPayslipDeliveryHandler(service services.PayslipDeliveryService) *PayslipDeliveryHandler {
+	return &PayslipDeliveryHandler{
+		service: service,
+	}
+}
+
+// GetDeliveryList godoc
+// @Summary      Get payslip email deliveries
+// @Description  Retrieves a paginated list of the payslip emails of a payroll period, newest first, with their delivery status, attempts and last error. Admin only.
+// @Tags         payslip-delivery
+// @Accept       json
+// @Produce      json
+// @Param        payroll_period_id  query     int     true   "Payroll Period ID"
+// @Param        status             query     string  false  "Delivery status"  Enums(pending, sending, sent, failed)
+// @Param        page               query     int     false  "Page number"  default(1)
+// @Param        limit              query     int     false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payslip-deliveries [get]
+func (h *PayslipDeliveryHandler) GetDeliveryList(ctx *gin.Context) {
+	periodID, err := strconv.ParseUint(ctx.DefaultQuery("payroll_period_id", "0"), 10, 64)
+	if err != nil || periodID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "payroll_period_id is required"})
+		return
+	}
+	status := ctx.Query("status")
+	switch status {
+	case "", models.PayslipDeliveryPending, models.PayslipDeliverySending, models.PayslipDeliverySent, models.PayslipDeliveryFailed:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+	pagination := utils.GetPagination(ctx)
+
+	deliveries, total, err := h.service.GetDeliveryList(uint(periodID), status, pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payslip deliveries"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      deliveries,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// ResendDelivery godoc
+// @Summary      Resend a payslip email
+// @Description  Queues the payslip of a sent or failed delivery again, to the employee's current email address. The earlier delivery is kept; when the payslip is still waiting to be sent, that delivery is returned instead. Admin only.
+// @Tags         payslip-delivery
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Delivery ID"
+// @Success      202    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payslip-deliveries/{id}/resend [post]
+func (h *PayslipDeliveryHandler) ResendDelivery(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	delivery, err := h.service.Resend(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Payslip delivery or payslip not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resend payslip"})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"data": delivery})
+}
+
+// GetPDFPassword godoc
+// @Summary      Get my payslip PDF password
+// @Description  Returns the password that opens the authenticated user's emailed payslip PDFs. Responds 404 while emailed payslips are not password protected.
+// @Tags         payslip
+// @Accept       json
+// @Produce      json
+// @Success      200    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /payslips/pdf-password [get]
+func (h *PayslipDeliveryHandler) GetPDFPassword(ctx *gin.Context) {
+	password, err := h.service.PDFPassword(ctx.GetUint("user_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrPDFProtectionOff) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payslip PDF password"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": gin.H{"password": password}})
+}
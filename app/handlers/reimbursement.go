@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/services"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type ReimbursementHandler struct {
@@ -98,6 +101,7 @@ func (h *ReimbursementHandler) GetReimbursementList(ctx *gin.Context) {
 // @Param        id     path      int  true  "Reimbursement ID"
 // @Success      200    {object}  models.ReimbursementResponse  "Reimbursement record"
 // @Failure      400    {object}  map[string]string    "Invalid ID"
+// @Failure      403    {object}  map[string]string    "Forbidden access"
 // @Failure      404    {object}  map[string]string    "Reimbursement not found"
 // @Security     CookieAuth
 // @Security     BearerAuth
@@ -109,8 +113,11 @@ func (h *ReimbursementHandler) GetReimbursementByID(ctx *gin.Context) {
 		return
 	}
 
-	reimbursement, err := h.service.GetReimbursementByID(uint(id))
+	reimbursement, err := h.service.GetReimbursementByID(ctx.GetUint("user_id"), uint(id))
 	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "reimbursement not found"})
 		return
 	}
@@ -120,7 +127,7 @@ func (h *ReimbursementHandler) GetReimbursementByID(ctx *gin.Context) {
 
 // CreateReimbursement godoc
 // @Summary      Create reimbursement
-// @Description  Creates a new reimbursement record for the current user. The user must be authenticated.
+// @Description  Creates a new reimbursement record for the current user. The user must be authenticated. Several claims may be submitted for the same day, but the amount must fit what is left of the category's monthly and yearly caps.
 // @Tags         reimbursement
 // @Accept       json
 // @Produce      json
@@ -128,6 +135,8 @@ func (h *ReimbursementHandler) GetReimbursementByID(ctx *gin.Context) {
 // @Success      201    {object}  models.ReimbursementResponse  "Created reimbursement record"
 // @Failure      400    {object}  map[string]string  "Invalid input"
 // @Failure      401    {object}  map[string]string  "Unauthorized"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
+// @Failure      422    {object}  map[string]string  "Category budget exceeded"
 // @Security     CookieAuth
 // @Security     BearerAuth
 // @Router       /reimbursements [post]
@@ -154,13 +163,17 @@ func (h *ReimbursementHandler) CreateReimbursement(ctx *gin.Context) {
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
 
 	reimbursement := models.Reimbursement{
-		UserID: currentUserIDUint,
-		Date:   reimbursementReq.Date,
-		Amount: reimbursementReq.Amount,
-		Note:   reimbursementReq.Note,
+		UserID:     currentUserIDUint,
+		Date:       reimbursementReq.Date,
+		CategoryID: &reimbursementReq.CategoryID,
+		Amount:     reimbursementReq.Amount,
+		Note:       reimbursementReq.Note,
 	}
 	newReimbursement, err := h.service.SubmitReimbursement(ctx, &reimbursement)
 	if err != nil {
+		if respondPeriodLocked(ctx, err) || respondBudgetRejected(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create reimbursement"})
 		return
 	}
@@ -180,6 +193,8 @@ func (h *ReimbursementHandler) CreateReimbursement(ctx *gin.Context) {
 // @Failure      400    {object}  map[string]string  "Invalid input"
 // @Failure      404    {object}  map[string]string  "Reimbursement not found"
 // @Failure      403    {object}  map[string]string  "Forbidden access"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
+// @Failure      422    {object}  map[string]string  "Category budget exceeded"
 // @Security     CookieAuth
 // @Security     BearerAuth
 // @Router       /reimbursements/{id} [put]
@@ -201,16 +216,23 @@ func (h *ReimbursementHandler) UpdateReimbursement(ctx *gin.Context) {
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
 	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
 
-	reimbursement, err := h.service.GetReimbursementByID(uint(id))
+	reimbursement, err := h.service.GetReimbursementByID(userID, uint(id))
 	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "reimbursement not found"})
 		return
 	}
 	reimbursement.Date = reimbursementReq.Date
+	reimbursement.CategoryID = &reimbursementReq.CategoryID
 	reimbursement.Amount = reimbursementReq.Amount
 	reimbursement.Note = reimbursementReq.Note
-	updatedReimbursement, err := h.service.UpdateReimbursement(ctx, reimbursement)
+	updatedReimbursement, err := h.service.UpdateReimbursement(ctx, userID, reimbursement)
 	if err != nil {
+		if respondPeriodLocked(ctx, err) || respondForbidden(ctx, err) || respondBudgetRejected(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update reimbursement"})
 		return
 	}
@@ -229,6 +251,7 @@ func (h *ReimbursementHandler) UpdateReimbursement(ctx *gin.Context) {
 // @Failure      400    {object}  map[string]string  "Invalid ID"
 // @Failure      403    {object}  map[string]string  "Forbidden access"
 // @Failure      404    {object}  map[string]string  "Reimbursement not found"
+// @Failure      409    {object}  map[string]string  "Payroll period already processed"
 // @Security     CookieAuth
 // @Security     BearerAuth
 // @Router       /reimbursements/{id} [delete]
@@ -239,11 +262,79 @@ func (h *ReimbursementHandler) DeleteReimbursement(ctx *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteReimbursement(uint(id))
+	err = h.service.DeleteReimbursement(ctx.GetUint("user_id"), uint(id))
 	if err != nil {
+		if respondPeriodLocked(ctx, err) || respondForbidden(ctx, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "reimbursement not found"})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete reimbursement"})
 		return
 	}
 
 	ctx.JSON(http.StatusNoContent, nil)
 }
+
+// GetBudgetUsage godoc
+// @Summary      Get reimbursement budget usage
+// @Description  Reports, for every active category, the caps that apply to the user and how much of them is used and left in the month and the year of the date. Users can only see their own budget.
+// @Tags         reimbursement
+// @Accept       json
+// @Produce      json
+// @Param        user_id  query     int     false  "User ID, defaults to the current user"
+// @Param        date     query     string  false  "Date in YYYY-MM-DD, defaults to today"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400      {object}  map[string]string  "Invalid input"
+// @Failure      403      {object}  map[string]string  "Forbidden access"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reimbursements/budget [get]
+func (h *ReimbursementHandler) GetBudgetUsage(ctx *gin.Context) {
+	currentUserID := ctx.GetUint("user_id")
+	userID := uint64(currentUserID)
+	if userIDParam := ctx.Query("user_id"); userIDParam != "" {
+		var err error
+		userID, err = strconv.ParseUint(userIDParam, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+			return
+		}
+	}
+	date := utils.DateIn(time.Now(), utils.CompanyLocation())
+	if dateParam := ctx.Query("date"); dateParam != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", dateParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	budgets, err := h.service.GetBudgetUsage(currentUserID, uint(userID), date)
+	if err != nil {
+		if respondForbidden(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get reimbursement budget"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": budgets})
+}
+
+// respondBudgetRejected answers 400 Bad Request for a claim without a valid category and 422
+// Unprocessable Entity for one over its category's budget, and reports whether it did.
+func respondBudgetRejected(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrReimbursementCategoryInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReimbursementBudgetExceeded):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
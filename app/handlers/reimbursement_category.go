@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ReimbursementCategoryHandler struct {
+	service services.ReimbursementCategoryService
+}
+
+func NewReimbursementCategoryHandler(service services.ReimbursementCategoryService) *ReimbursementCategoryHandler {
+	return &ReimbursementCategoryHandler{
+		service: service,
+	}
+}
+
+// GetCategoryList godoc
+// @Summary      Get reimbursement categories
+// @Description  Lists the reimbursement categories by name with their default caps and limit overrides. Only active categories are listed unless include_inactive is set.
+// @Tags         reimbursement-category
+// @Accept       json
+// @Produce      json
+// @Param        include_inactive  query     bool  false  "Include inactive categories"  default(false)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reimbursement-categories [get]
+func (h *ReimbursementCategoryHandler) GetCategoryList(ctx *gin.Context) {
+	includeInactive := ctx.DefaultQuery("include_inactive", "false") == "true"
+
+	categories, err := h.service.GetCategoryList(!includeInactive)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reimbursement categories"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": categories})
+}
+
+// CreateCategory godoc
+// @Summary      Create a reimbursement category
+// @Description  Creates a reimbursement category, such as medical, travel or meals, with optional monthly and yearly caps per employee. Leave a cap empty for no limit. Admin only.
+// @Tags         reimbursement-category
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.ReimbursementCategoryRequest  true  "Category payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reimbursement-categories [post]
+func (h *ReimbursementCategoryHandler) CreateCategory(ctx *gin.Context) {
+	var req models.ReimbursementCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	category, err := h.service.CreateCategory(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create reimbursement category", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": category})
+}
+
+// UpdateCategory godoc
+// @Summary      Update a reimbursement category
+// @Description  Updates a category's name and default caps, or deactivates it so no new claims can be submitted against it. Claims already submitted are kept. Admin only.
+// @Tags         reimbursement-category
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Category ID"
+// @Param        body   body      models.ReimbursementCategoryRequest  true  "Category payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reimbursement-categories/{id} [put]
+func (h *ReimbursementCategoryHandler) UpdateCategory(ctx *gin.Context) {
+	var req models.ReimbursementCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	category, err := h.service.UpdateCategory(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Reimbursement category not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update reimbursement category", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+// CreateLimit godoc
+// @Summary      Override a category's caps
+// @Description  Replaces a category's monthly and yearly caps for a single employee (user_id) or for every employee of a grade. An employee's own limit wins over their grade's. Leave a cap empty for no limit. Admin only.
+// @Tags         reimbursement-category
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Category ID"
+// @Param        body   body      models.ReimbursementLimitRequest  true  "Limit payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reimbursement-categories/{id}/limits [post]
+func (h *ReimbursementCategoryHandler) CreateLimit(ctx *gin.Context) {
+	var req models.ReimbursementLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	limit, err := h.service.CreateLimit(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Reimbursement category not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create reimbursement limit", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": limit})
+}
+
+// DeleteLimit godoc
+// @Summary      Remove a category cap override
+// @Description  Removes an employee or grade limit, so the category's default caps apply again. Admin only.
+// @Tags         reimbursement-category
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int  true  "Category ID"
+// @Param        limit_id  path      int  true  "Limit ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reimbursement-categories/{id}/limits/{limit_id} [delete]
+func (h *ReimbursementCategoryHandler) DeleteLimit(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	limitID, err := strconv.ParseUint(ctx.Param("limit_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit ID"})
+		return
+	}
+
+	if err := h.service.DeleteLimit(uint(id), uint(limitID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Reimbursement limit not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reimbursement limit"})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/gin-gonic/gin"
+)
+
+const maxTopOvertimeUsers = 100
+
+type ReportHandler struct {
+	service services.ReportService
+}
+
+func NewReportHandler(service services.ReportService) *ReportHandler {
+	return &ReportHandler{
+		service: service,
+	}
+}
+
+// GetAttendanceRateReport godoc
+// @Summary      Attendance rate report
+// @Description  Attendance rate per employee, per team (a manager's direct reports) and overall. Expected days are weekdays plus rostered weekend shifts up to today, minus leave. Admin only.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Param        start_date query     string  true  "Start date (YYYY-MM-DD)"
+// @Param        end_date   query     string  true  "End date (YYYY-MM-DD)"
+// @Success      200    {object}  models.AttendanceRateReport
+// @Failure      400    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reports/attendance-rate [get]
+func (h *ReportHandler) GetAttendanceRateReport(ctx *gin.Context) {
+	startDate, endDate, ok := reportDates(ctx)
+	if !ok {
+		return
+	}
+	report, err := h.service.GetAttendanceRateReport(startDate, endDate)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GetTopOvertimeUsers godoc
+// @Summary      Top overtime users
+// @Description  Employees with the most submitted overtime hours in the date range. Admin only.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Param        start_date query     string  true   "Start date (YYYY-MM-DD)"
+// @Param        end_date   query     string  true   "End date (YYYY-MM-DD)"
+// @Param        limit      query     int     false  "Number of employees"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reports/top-overtime [get]
+func (h *ReportHandler) GetTopOvertimeUsers(ctx *gin.Context) {
+	startDate, endDate, ok := reportDates(ctx)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxTopOvertimeUsers {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	users, err := h.service.GetTopOvertimeUsers(startDate, endDate, limit)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": users})
+}
+
+// GetOvertimeCostTrend godoc
+// @Summary      Overtime cost trend
+// @Description  Overtime hours and pay of each processed payroll period overlapping the date range, with the change from the previous period in percent. Admin only.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Param        start_date query     string  true  "Start date (YYYY-MM-DD)"
+// @Param        end_date   query     string  true  "End date (YYYY-MM-DD)"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reports/overtime-cost [get]
+func (h *ReportHandler) GetOvertimeCostTrend(ctx *gin.Context) {
+	startDate, endDate, ok := reportDates(ctx)
+	if !ok {
+		return
+	}
+	trend, err := h.service.GetOvertimeCostTrend(startDate, endDate)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": trend})
+}
+
+// GetPayrollCostByPeriod godoc
+// @Summary      Payroll cost per period
+// @Description  Totals of the payslips generated for each payroll period overlapping the date range. Admin only.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Param        start_date query     string  true  "Start date (YYYY-MM-DD)"
+// @Param        end_date   query     string  true  "End date (YYYY-MM-DD)"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reports/payroll-cost [get]
+func (h *ReportHandler) GetPayrollCostByPeriod(ctx *gin.Context) {
+	startDate, endDate, ok := reportDates(ctx)
+	if !ok {
+		return
+	}
+	periods, err := h.service.GetPayrollCostByPeriod(startDate, endDate)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": periods})
+}
+
+// GetReimbursementSpendByMonth godoc
+// @Summary      Reimbursement spend by month
+// @Description  Number and total amount of reimbursement claims per month in the date range. Admin only.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Param        start_date query     string  true  "Start date (YYYY-MM-DD)"
+// @Param        end_date   query     string  true  "End date (YYYY-MM-DD)"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /reports/reimbursement-spend [get]
+func (h *ReportHandler) GetReimbursementSpendByMonth(ctx *gin.Context) {
+	startDate, endDate, ok := reportDates(ctx)
+	if !ok {
+		return
+	}
+	spend, err := h.service.GetReimbursementSpendByMonth(startDate, endDate)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": spend})
+}
+
+// reportDates parses the required start_date and end_date, answering 400 when they are invalid.
+func reportDates(ctx *gin.Context) (time.Time, time.Time, bool) {
+	startDate, err := time.Parse("2006-01-02", ctx.Query("start_date"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "start_date is required in YYYY-MM-DD format"})
+		return time.Time{}, time.Time{}, false
+	}
+	endDate, err := time.Parse("2006-01-02", ctx.Query("end_date"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "end_date is required in YYYY-MM-DD format"})
+		return time.Time{}, time.Time{}, false
+	}
+	return startDate, endDate, true
+}
+
+func respondReportError(ctx *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidReportRange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build report"})
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ShiftHandler struct {
+	service services.ShiftService
+}
+
+func NewShiftHandler(service services.ShiftService) *ShiftHandler {
+	return &ShiftHandler{
+		service: service,
+	}
+}
+
+// GetShiftList godoc
+// @Summary      Get list of shifts
+// @Description  Retrieves a paginated list of shift templates. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        page   query     int  false  "Page number"  default(1)
+// @Param        limit  query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts [get]
+func (h *ShiftHandler) GetShiftList(ctx *gin.Context) {
+	pagination := utils.GetPagination(ctx)
+
+	shifts, total, err := h.service.GetShiftList(pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shifts"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      shifts,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// GetShiftByID godoc
+// @Summary      Get shift by ID
+// @Description  Retrieves a specific shift template by its ID. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Shift ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts/{id} [get]
+func (h *ShiftHandler) GetShiftByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	shift, err := h.service.GetShiftByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": shift})
+}
+
+// CreateShift godoc
+// @Summary      Create a shift
+// @Description  Creates a new shift template. A shift whose end time is before its start time crosses midnight. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.ShiftRequest  true  "Shift payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts [post]
+func (h *ShiftHandler) CreateShift(ctx *gin.Context) {
+	var req models.ShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	shift, err := h.service.CreateShift(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create shift", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": shift})
+}
+
+// UpdateShift godoc
+// @Summary      Update a shift
+// @Description  Updates an existing shift template. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Shift ID"
+// @Param        body   body      models.ShiftRequest  true  "Shift payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts/{id} [put]
+func (h *ShiftHandler) UpdateShift(ctx *gin.Context) {
+	var req models.ShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	shift, err := h.service.UpdateShift(ctx, uint(id), &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update shift", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": shift})
+}
+
+// DeleteShift godoc
+// @Summary      Delete a shift
+// @Description  Deletes a shift template by its ID. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Shift ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts/{id} [delete]
+func (h *ShiftHandler) DeleteShift(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteShift(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete shift"})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// GetAssignmentList godoc
+// @Summary      Get shift roster
+// @Description  Retrieves the shift assignments of a user over a date range. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        user_id    query     int     true  "User ID"
+// @Param        start_date query     string  true  "Start date (YYYY-MM-DD)"
+// @Param        end_date   query     string  true  "End date (YYYY-MM-DD)"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts/assignments [get]
+func (h *ShiftHandler) GetAssignmentList(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.DefaultQuery("user_id", "0"), 10, 64)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	startDate := ctx.DefaultQuery("start_date", "")
+	endDate := ctx.DefaultQuery("end_date", "")
+	if startDate == "" || endDate == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required"})
+		return
+	}
+
+	assignments, err := h.service.GetAssignmentList(uint(userID), startDate, endDate)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shift assignments"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": assignments})
+}
+
+// AssignShift godoc
+// @Summary      Assign a shift
+// @Description  Rosters a user on a shift for every date in a range, replacing any existing assignment on those dates. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.ShiftAssignmentRequest  true  "Roster payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts/assignments [post]
+func (h *ShiftHandler) AssignShift(ctx *gin.Context) {
+	var req models.ShiftAssignmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+
+	assignments, err := h.service.AssignShift(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to assign shift", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": assignments})
+}
+
+// DeleteAssignment godoc
+// @Summary      Delete a shift assignment
+// @Description  Removes a user's shift assignment for a single date. Admin only.
+// @Tags         shift
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Shift assignment ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /shifts/assignments/{id} [delete]
+func (h *ShiftHandler) DeleteAssignment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteAssignment(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete shift assignment"})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
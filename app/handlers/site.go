@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SiteHandler struct {
+	service services.SiteService
+}
+
+func NewSiteHandler(service services.SiteService) *SiteHandler {
+	return &SiteHandler{
+		service: service,
+	}
+}
+
+// GetSiteList godoc
+// @Summary      Get list of office sites
+// @Description  Retrieves a paginated list of office sites used for geofenced attendance. Admin only.
+// @Tags         site
+// @Accept       json
+// @Produce      json
+// @Param        page   query     int  false  "Page number"  default(1)
+// @Param        limit  query     int  false  "Number of items per page"  default(10)
+// @Success      200    {object}  map[string]interface{}
+// @Failure      500    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /sites [get]
+func (h *SiteHandler) GetSiteList(ctx *gin.Context) {
+	pagination := utils.GetPagination(ctx)
+
+	sites, total, err := h.service.GetSiteList(pagination)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve sites"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":      sites,
+		"total":     total,
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"totalPage": int(math.Ceil(float64(total) / float64(pagination.Limit))),
+	})
+}
+
+// GetSiteByID godoc
+// @Summary      Get office site by ID
+// @Description  Retrieves a specific office site by its ID. Admin only.
+// @Tags         site
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Site ID"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /sites/{id} [get]
+func (h *SiteHandler) GetSiteByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	site, err := h.service.GetSiteByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Site not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": site})
+}
+
+// CreateSite godoc
+// @Summary      Create an office site
+// @Description  Creates a new circle or polygon geofence for attendance. Admin only.
+// @Tags         site
+// @Accept       json
+// @Produce      json
+// @Param        body   body      models.SiteRequest  true  "Site payload"
+// @Success      201    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /sites [post]
+func (h *SiteHandler) CreateSite(ctx *gin.Context) {
+	var req models.SiteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	site, err := h.service.CreateSite(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create site", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": site})
+}
+
+// UpdateSite godoc
+// @Summary      Update an office site
+// @Description  Updates an existing office site. Admin only.
+// @Tags         site
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Site ID"
+// @Param        body   body      models.SiteRequest  true  "Site payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /sites/{id} [put]
+func (h *SiteHandler) UpdateSite(ctx *gin.Context) {
+	var req models.SiteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	requestID := ctx.GetString("request_id")
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "user_id", userID))
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), "request_id", requestID))
+
+	site, err := h.service.UpdateSite(ctx, uint(id), &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update site", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": site})
+}
+
+// DeleteSite godoc
+// @Summary      Delete an office site
+// @Description  Deletes an office site by its ID. Admin only.
+// @Tags         site
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Site ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /sites/{id} [delete]
+func (h *SiteHandler) DeleteSite(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteSite(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete site"})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
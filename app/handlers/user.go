@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
@@ -49,4 +52,121 @@ func (h *UserHandler) Login(ctx *gin.Context) {
 		"access_token": accessToken,
 		"refresh_token": refreshToken,
 	})
-}
\ No newline at end of file
+}
+
+// UpdateUserManager godoc
+// @Summary      Assign a user's manager
+// @Description  Sets the manager who reviews the user's attendance corrections. The manager must be another user with the manager role; a null manager_id removes the manager. Admin only.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        body   body      models.UserManagerRequest  true  "Manager payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /users/{id}/manager [put]
+func (h *UserHandler) UpdateUserManager(ctx *gin.Context) {
+	var req models.UserManagerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	user, err := h.service.AssignManager(ctx, uint(id), req.ManagerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to assign manager", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// UpdateUserTimezone godoc
+// @Summary      Set a user's timezone
+// @Description  Sets the IANA timezone the user's attendance dates are bucketed in. An empty or null timezone falls back to the site and then the company timezone. Admin only.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        body   body      models.UserTimezoneRequest  true  "Timezone payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /users/{id}/timezone [put]
+func (h *UserHandler) UpdateUserTimezone(ctx *gin.Context) {
+	var req models.UserTimezoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	user, err := h.service.SetTimezone(ctx, uint(id), req.Timezone)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to set timezone", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// UpdateUserGrade godoc
+// @Summary      Set a user's grade
+// @Description  Sets the grade whose reimbursement limits apply to the user. An empty or null grade clears it. Admin only.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        body   body      models.UserGradeRequest  true  "Grade payload"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Security     CookieAuth
+// @Security     BearerAuth
+// @Router       /users/{id}/grade [put]
+func (h *UserHandler) UpdateUserGrade(ctx *gin.Context) {
+	var req models.UserGradeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	user, err := h.service.SetGrade(ctx, uint(id), req.Grade)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set grade"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": user})
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// AllowanceAssignment is a fixed monthly allowance, such as transport, meal or position. Every
+// payslip of a period overlapping its effective dates pays the share of the month's days covered,
+// so it adds up to Amount per calendar month however payroll is split. An open EndDate keeps it running.
+type AllowanceAssignment struct {
+	BaseModel
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	Name      string     `json:"name" gorm:"size:100;not null"`
+	Amount    float64    `json:"amount" gorm:"not null"`
+	StartDate time.Time  `json:"start_date" gorm:"type:DATE;not null"`
+	EndDate   *time.Time `json:"end_date" gorm:"type:DATE;default:null"`
+}
+
+// PayrollAdjustment is a one-time amount on a user's payslip of one payroll period, such as a
+// back-pay correction. A negative amount is deducted.
+type PayrollAdjustment struct {
+	BaseModel
+	UserID          uint    `json:"user_id" gorm:"not null;index"`
+	PayrollPeriodID uint    `json:"payroll_period_id" gorm:"not null;index"`
+	Description     string  `json:"description" gorm:"size:255;not null"`
+	Amount          float64 `json:"amount" gorm:"not null"`
+}
+
+type AllowanceAssignmentRequest struct {
+	UserID    uint       `json:"user_id" binding:"required" example:"2"`
+	Name      string     `json:"name" binding:"required,max=100" example:"Transport"`
+	Amount    float64    `json:"amount" binding:"required,gt=0" example:"500000"`
+	StartDate time.Time  `json:"start_date" binding:"required" example:"2025-01-01T00:00:00Z"`
+	EndDate   *time.Time `json:"end_date" example:"2025-12-31T00:00:00Z"`
+}
+
+type PayrollAdjustmentRequest struct {
+	UserID          uint    `json:"user_id" binding:"required" example:"2"`
+	PayrollPeriodID uint    `json:"payroll_period_id" binding:"required" example:"3"`
+	Description     string  `json:"description" binding:"required,max=255" example:"Back pay for March"`
+	Amount          float64 `json:"amount" binding:"required,ne=0" example:"750000"`
+}
@@ -4,11 +4,132 @@ import (
 	"time"
 )
 
+const (
+	CloseOutAutoClosed = "auto_closed"
+	CloseOutIncomplete = "incomplete"
+	CloseOutCorrected  = "corrected"
+)
+
 type Attendance struct {
 	BaseModel
-	UserID   uint       `json:"user_id" gorm:"not null,uniqueIndex:idx_user_date"`
-	Date     time.Time  `json:"date" gorm:"type:DATE;not null,uniqueIndex:idx_user_date"`
-	CheckIn  *time.Time `json:"check_in" gorm:"not null"`
-	CheckOut *time.Time `json:"check_out" gorm:"nullable"`
-	User     User       `gorm:"foreignKey:UserID" json:"user" readonly:"true"`
+	UserID            uint                `json:"user_id" gorm:"not null,uniqueIndex:idx_user_date"`
+	Date              time.Time           `json:"date" gorm:"type:DATE;not null,uniqueIndex:idx_user_date"`
+	CheckIn           *time.Time          `json:"check_in" gorm:"not null"`
+	CheckOut          *time.Time          `json:"check_out" gorm:"nullable"`
+	CheckInLatitude   *float64            `json:"check_in_latitude"`
+	CheckInLongitude  *float64            `json:"check_in_longitude"`
+	CheckInAccuracy   *float64            `json:"check_in_accuracy"`
+	CheckOutLatitude  *float64            `json:"check_out_latitude"`
+	CheckOutLongitude *float64            `json:"check_out_longitude"`
+	CheckOutAccuracy  *float64            `json:"check_out_accuracy"`
+	SiteID            *uint               `json:"site_id"`
+	IsOutsideGeofence bool                `json:"is_outside_geofence" gorm:"default:false"`
+	WorkedHours       float64             `json:"worked_hours" gorm:"default:0"`
+	LateMinutes       int                 `json:"late_minutes" gorm:"default:0"`
+	EarlyLeaveMinutes int                 `json:"early_leave_minutes" gorm:"default:0"`
+	UndertimeMinutes  int                 `json:"undertime_minutes" gorm:"default:0"`
+	OvertimeMinutes   int                 `json:"overtime_minutes" gorm:"default:0"`
+	ShiftID           *uint               `json:"shift_id"`
+	CloseOutStatus    string              `json:"close_out_status" gorm:"size:20"`
+	Timezone          string              `json:"timezone" gorm:"size:64"`
+	User              User                `gorm:"foreignKey:UserID" json:"user" readonly:"true"`
+	Site              *Site               `gorm:"foreignKey:SiteID" json:"site,omitempty" readonly:"true"`
+	Shift             *Shift              `gorm:"foreignKey:ShiftID" json:"shift,omitempty" readonly:"true"`
+	Sessions          []AttendanceSession `gorm:"foreignKey:AttendanceID" json:"sessions"`
+}
+
+// OpenSession returns the session that has been checked in but not checked out yet.
+func (a *Attendance) OpenSession() *AttendanceSession {
+	for i := range a.Sessions {
+		if a.Sessions[i].CheckOut == nil {
+			return &a.Sessions[i]
+		}
+	}
+	return nil
+}
+
+// AttendanceSession is a single check-in/check-out pair within an attendance day.
+type AttendanceSession struct {
+	BaseModel
+	AttendanceID      uint       `json:"attendance_id" gorm:"not null;index"`
+	CheckIn           *time.Time `json:"check_in" gorm:"not null"`
+	CheckOut          *time.Time `json:"check_out" gorm:"nullable"`
+	CheckInLatitude   *float64   `json:"check_in_latitude"`
+	CheckInLongitude  *float64   `json:"check_in_longitude"`
+	CheckInAccuracy   *float64   `json:"check_in_accuracy"`
+	CheckOutLatitude  *float64   `json:"check_out_latitude"`
+	CheckOutLongitude *float64   `json:"check_out_longitude"`
+	CheckOutAccuracy  *float64   `json:"check_out_accuracy"`
+	SiteID            *uint      `json:"site_id"`
+	IsOutsideGeofence bool       `json:"is_outside_geofence" gorm:"default:false"`
+	// DeviceID is set on sessions paired from terminal punches
+	DeviceID *uint `json:"device_id"`
+}
+
+// AttendanceTotals summarizes an attendance list.
+type AttendanceTotals struct {
+	Days              int     `json:"days"`
+	Sessions          int     `json:"sessions"`
+	WorkedHours       float64 `json:"worked_hours"`
+	LateDays          int     `json:"late_days"`
+	LateMinutes       int     `json:"late_minutes"`
+	EarlyLeaveMinutes int     `json:"early_leave_minutes"`
+	UndertimeMinutes  int     `json:"undertime_minutes"`
+}
+
+// DailyAllowanceRule decides which attended days earn the per-day meal and transport allowances.
+type DailyAllowanceRule struct {
+	MaxLateMinutes   int
+	RequireFullHours bool
+	RequireOnSite    bool
+}
+
+// AttendancePenaltySummary aggregates the lateness of a user over a payroll period.
+type AttendancePenaltySummary struct {
+	LateDays         int64 `json:"late_days"`
+	LateMinutes      int64 `json:"late_minutes"`
+	UndertimeMinutes int64 `json:"undertime_minutes"`
+}
+
+// AttendanceRequest carries the device location sent with a check-in or check-out.
+type AttendanceRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90" example:"-6.2088"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180" example:"106.8456"`
+	Accuracy  *float64 `json:"accuracy" binding:"omitempty,min=0" example:"12.5"`
+}
+
+// AttendanceEntryRequest records attendance on behalf of a user. On an existing day
+// only the provided times are replaced.
+type AttendanceEntryRequest struct {
+	UserID       uint       `json:"user_id" binding:"required" example:"12"`
+	Date         string     `json:"date" binding:"required" example:"2025-06-11"`
+	CheckIn      *time.Time `json:"check_in" example:"2025-06-11T08:55:00+07:00"`
+	CheckOut     *time.Time `json:"check_out" example:"2025-06-11T17:05:00+07:00"`
+	AllowWeekend bool       `json:"allow_weekend" example:"false"`
+}
+
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+	ImportActionSkip   = "skip"
+)
+
+// AttendanceImportRow reports what happened, or would happen on a dry run, to one CSV row.
+type AttendanceImportRow struct {
+	Line     int        `json:"line"`
+	UserID   uint       `json:"user_id"`
+	Date     string     `json:"date"`
+	CheckIn  *time.Time `json:"check_in"`
+	CheckOut *time.Time `json:"check_out"`
+	Action   string     `json:"action"`
+	Error    string     `json:"error,omitempty"`
+}
+
+type AttendanceImportResult struct {
+	DryRun  bool                  `json:"dry_run"`
+	Total   int                   `json:"total"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Failed  int                   `json:"failed"`
+	Rows    []AttendanceImportRow `json:"rows"`
 }
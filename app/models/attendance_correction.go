@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	CorrectionStatusPending  = "pending"
+	CorrectionStatusApproved = "approved"
+	CorrectionStatusRejected = "rejected"
+)
+
+// AttendanceCorrection is an employee's request to fix the recorded times of an attendance.
+type AttendanceCorrection struct {
+	BaseModel
+	AttendanceID uint       `json:"attendance_id" gorm:"not null;index"`
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	CheckIn      *time.Time `json:"check_in"`
+	CheckOut     *time.Time `json:"check_out"`
+	Reason       string     `json:"reason" gorm:"type:text;not null"`
+	Status       string     `json:"status" gorm:"size:20;not null;default:pending"`
+	ReviewedBy   *uint      `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	ReviewNote   *string    `json:"review_note" gorm:"size:255"`
+}
+
+type AttendanceCorrectionCache struct {
+	Corrections []*AttendanceCorrection `json:"corrections"`
+	Total       int64                   `json:"total"`
+}
+
+// AttendanceCorrectionRequest proposes a new check-in, check-out, or both.
+type AttendanceCorrectionRequest struct {
+	CheckIn  *time.Time `json:"check_in" example:"2025-06-11T08:55:00+07:00"`
+	CheckOut *time.Time `json:"check_out" example:"2025-06-11T17:00:00+07:00"`
+	Reason   string     `json:"reason" binding:"required,max=500" example:"Forgot to check out after the client meeting"`
+}
+
+// CorrectionFilter narrows the correction list. ManagerID limits it to the manager's reports.
+type CorrectionFilter struct {
+	Status    string
+	UserID    uint
+	ManagerID uint
+}
+
+type CorrectionReviewRequest struct {
+	Note *string `json:"note" binding:"omitempty,max=255" example:"Confirmed with team lead"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// Statuses shown on the team attendance dashboard.
+const (
+	DashboardPresent      = "present"
+	DashboardLate         = "late"
+	DashboardAbsent       = "absent"
+	DashboardOnLeave      = "on_leave"
+	DashboardNotCheckedIn = "not_checked_in"
+	DashboardOff          = "off"
+	DashboardUpcoming     = "upcoming"
+)
+
+// TeamMember is the slice of a user the dashboard needs.
+type TeamMember struct {
+	ID           uint    `json:"user_id"`
+	Name         string  `json:"name"`
+	EmployeeCode *string `json:"employee_code"`
+	Timezone     *string `json:"-"`
+}
+
+// TeamAttendance is the per-day summary of an attendance read by the dashboard.
+type TeamAttendance struct {
+	UserID      uint       `json:"-"`
+	Date        time.Time  `json:"-"`
+	CheckIn     *time.Time `json:"check_in"`
+	CheckOut    *time.Time `json:"check_out"`
+	LateMinutes int        `json:"late_minutes"`
+}
+
+type DailyBoardRow struct {
+	TeamMember
+	Status     string          `json:"status"`
+	LeaveType  string          `json:"leave_type,omitempty"`
+	Attendance *TeamAttendance `json:"attendance,omitempty"`
+}
+
+// DailyBoard lists the status of every team member on one date.
+type DailyBoard struct {
+	Date    string           `json:"date"`
+	Summary map[string]int   `json:"summary"`
+	Rows    []*DailyBoardRow `json:"rows"`
+}
+
+type MonthlyMatrixRow struct {
+	TeamMember
+	// Days holds one status per day of the month, in order.
+	Days    []string       `json:"days"`
+	Summary map[string]int `json:"summary"`
+}
+
+// MonthlyMatrix is a users × days grid of statuses for one month.
+type MonthlyMatrix struct {
+	Month string              `json:"month"`
+	Dates []string            `json:"dates"`
+	Rows  []*MonthlyMatrixRow `json:"rows"`
+}
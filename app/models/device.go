@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+const (
+	PunchStatusProcessed = "processed"
+	PunchStatusUnmatched = "unmatched"
+	PunchStatusLocked    = "locked"
+)
+
+// Device is an attendance terminal allowed to push punches. Only a hash of its API key is
+// stored; the key itself is shown once when the device is registered or the key is rotated.
+type Device struct {
+	BaseModel
+	Name         string     `json:"name" gorm:"not null;size:100"`
+	Code         string     `json:"code" gorm:"not null;size:50;uniqueIndex"`
+	SiteID       *uint      `json:"site_id"`
+	APIKeyHash   string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	APIKeyPrefix string     `json:"api_key_prefix" gorm:"size:8"`
+	IsActive     bool       `json:"is_active" gorm:"default:true"`
+	LastSeenAt   *time.Time `json:"last_seen_at"`
+	Site         *Site      `gorm:"foreignKey:SiteID" json:"site,omitempty" readonly:"true"`
+}
+
+// DeviceWithKey is returned only when a key is issued.
+type DeviceWithKey struct {
+	*Device
+	APIKey string `json:"api_key"`
+}
+
+type DeviceRequest struct {
+	Name     string `json:"name" binding:"required,max=100" example:"Lobby terminal"`
+	Code     string `json:"code" binding:"required,max=50" example:"TERM-01"`
+	SiteID   *uint  `json:"site_id" example:"1"`
+	IsActive *bool  `json:"is_active" example:"true"`
+}
+
+// KioskToken is the rotating QR token a kiosk displays for employees to scan.
+type KioskToken struct {
+	Token     string    `json:"token"`
+	SiteID    uint      `json:"site_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type KioskCheckInRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Punch is a raw clock event received from a device. The same punch sent twice is stored once.
+type Punch struct {
+	BaseModel
+	DeviceID       uint       `json:"device_id" gorm:"not null;uniqueIndex:idx_punch_device_code_time"`
+	EmployeeCode   string     `json:"employee_code" gorm:"not null;size:50;uniqueIndex:idx_punch_device_code_time"`
+	PunchedAt      time.Time  `json:"punched_at" gorm:"not null;uniqueIndex:idx_punch_device_code_time"`
+	UserID         *uint      `json:"user_id" gorm:"index:idx_punch_user_date"`
+	AttendanceDate *time.Time `json:"attendance_date" gorm:"type:DATE;index:idx_punch_user_date"`
+	Status         string     `json:"status" gorm:"not null;size:20"`
+}
+
+type PunchRequest struct {
+	EmployeeCode string    `json:"employee_code" binding:"required,max=50" example:"EMP0012"`
+	Timestamp    time.Time `json:"timestamp" binding:"required" example:"2025-06-11T08:57:12+07:00"`
+	DeviceID     string    `json:"device_id" binding:"required,max=50" example:"TERM-01"`
+}
+
+type PunchBatchRequest struct {
+	Punches []PunchRequest `json:"punches" binding:"required,min=1,max=500,dive"`
+}
+
+// PunchIngestResult counts what happened to each punch of a batch.
+type PunchIngestResult struct {
+	Received   int `json:"received"`
+	Accepted   int `json:"accepted"`
+	Duplicates int `json:"duplicates"`
+	Unmatched  int `json:"unmatched"`
+	Locked     int `json:"locked"`
+	Rejected   int `json:"rejected"`
+}
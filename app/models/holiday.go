@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// Holiday is a public holiday. Overtime worked on it is paid at the holiday rates.
+type Holiday struct {
+	BaseModel
+	Date time.Time `json:"date" gorm:"type:DATE;not null;uniqueIndex"`
+	Name string    `json:"name" gorm:"size:100;not null"`
+}
+
+type HolidayRequest struct {
+	Date time.Time `json:"date" binding:"required" example:"2025-08-17T00:00:00Z"`
+	Name string    `json:"name" binding:"required,max=100" example:"Independence Day"`
+}
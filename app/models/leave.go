@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	LeaveTypeAnnual = "annual"
+	LeaveTypeSick   = "sick"
+	LeaveTypeUnpaid = "unpaid"
+	LeaveTypeOther  = "other"
+)
+
+// Leave records a user's approved absence over an inclusive range of dates. It is the
+// minimal record the team dashboard needs to tell "on leave" apart from "absent", and
+// the attendance-rate report to leave those days out of the expected ones. Admins enter
+// leave that was approved elsewhere; there is no request or approval workflow here.
+type Leave struct {
+	BaseModel
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	StartDate time.Time `json:"start_date" gorm:"type:DATE;not null"`
+	EndDate   time.Time `json:"end_date" gorm:"type:DATE;not null"`
+	Type      string    `json:"type" gorm:"size:20;not null"`
+	Note      *string   `json:"note" gorm:"size:255"`
+}
+
+type LeaveRequest struct {
+	UserID    uint      `json:"user_id" binding:"required" example:"2"`
+	StartDate time.Time `json:"start_date" binding:"required" example:"2025-06-16T00:00:00Z"`
+	EndDate   time.Time `json:"end_date" binding:"required" example:"2025-06-18T00:00:00Z"`
+	Type      string    `json:"type" binding:"required,oneof=annual sick unpaid other" example:"annual"`
+	Note      *string   `json:"note" binding:"omitempty,max=255" example:"Family trip"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	LoanTypeLoan          = "loan"
+	LoanTypeSalaryAdvance = "salary_advance"
+)
+
+// Loan is a company loan or salary advance repaid through monthly installments deducted from
+// the employee's payslips.
+type Loan struct {
+	BaseModel
+	UserID           uint              `json:"user_id" gorm:"not null;index"`
+	Type             string            `json:"type" gorm:"size:20;not null"`
+	Principal        float64           `json:"principal" gorm:"not null"`
+	RemainingBalance float64           `json:"remaining_balance" gorm:"not null"`
+	Note             *string           `json:"note" gorm:"size:255"`
+	Installments     []LoanInstallment `json:"installments,omitempty" gorm:"foreignKey:LoanID;constraint:OnDelete:CASCADE"`
+	User             *User             `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID" readonly:"true"`
+}
+
+// LoanInstallment is one scheduled repayment. It is deducted by the first payroll run whose
+// period ends on or after its due date, and released again when that period is reopened or voided.
+type LoanInstallment struct {
+	BaseModel
+	LoanID          uint       `json:"loan_id" gorm:"not null;uniqueIndex:idx_loan_installment_sequence"`
+	Sequence        int        `json:"sequence" gorm:"not null;uniqueIndex:idx_loan_installment_sequence"`
+	DueDate         time.Time  `json:"due_date" gorm:"type:DATE;not null"`
+	Amount          float64    `json:"amount" gorm:"not null"`
+	IsPaid          bool       `json:"is_paid" gorm:"default:false"`
+	PaidAt          *time.Time `json:"paid_at" gorm:"default:null"`
+	PayrollPeriodID *uint      `json:"payroll_period_id" gorm:"index;default:null"`
+}
+
+type LoanRequest struct {
+	UserID    uint    `json:"user_id" binding:"required" example:"2"`
+	Type      string  `json:"type" binding:"required,oneof=loan salary_advance" example:"loan"`
+	Principal float64 `json:"principal" binding:"required,gt=0" example:"6000000"`
+	// Installments is the number of monthly installments, the first one due on FirstDueDate.
+	Installments int       `json:"installments" binding:"required,min=1,max=120" example:"12"`
+	FirstDueDate time.Time `json:"first_due_date" binding:"required" example:"2025-07-31T00:00:00Z"`
+	Note         *string   `json:"note" binding:"omitempty,max=255" example:"Motorcycle loan"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+type Notification struct {
+	BaseModel
+	UserID  uint       `json:"user_id" gorm:"not null;index"`
+	Title   string     `json:"title" gorm:"not null;size:150"`
+	Message string     `json:"message" gorm:"type:text"`
+	ReadAt  *time.Time `json:"read_at" gorm:"default:null"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	OffCycleTypeBonus           = "bonus"
+	OffCycleTypeTHR             = "thr"
+	OffCycleTypeFinalSettlement = "final_settlement"
+)
+
+// OffCycleRun is a payroll run outside the regular periods, such as THR (the religious-holiday
+// bonus), an annual bonus or a final settlement, paying a chosen subset of employees.
+type OffCycleRun struct {
+	BaseModel
+	Type        string            `json:"type" gorm:"size:20;not null"`
+	PayDate     time.Time         `json:"pay_date" gorm:"type:DATE;not null"`
+	Note        *string           `json:"note" gorm:"size:255"`
+	IsProcessed bool              `json:"is_processed" gorm:"default:false"`
+	ProcessedAt *time.Time        `json:"processed_at" gorm:"default:null"`
+	Payslips    []OffCyclePayslip `json:"payslips,omitempty" gorm:"foreignKey:OffCycleRunID;constraint:OnDelete:CASCADE"`
+}
+
+// OffCyclePayslip is what one employee receives from an off-cycle run. It is kept apart from
+// the regular Payslip, which is unique per user and payroll period.
+type OffCyclePayslip struct {
+	BaseModel
+	OffCycleRunID uint              `json:"off_cycle_run_id" gorm:"not null;uniqueIndex:idx_off_cycle_run_user"`
+	UserID        uint              `json:"user_id" gorm:"not null;uniqueIndex:idx_off_cycle_run_user"`
+	Lines         []OffCycleEarning `json:"lines" gorm:"type:jsonb;serializer:json;not null"`
+	TotalEarnings float64           `json:"total_earnings" gorm:"not null"`
+	User          *User             `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID" readonly:"true"`
+	Run           *OffCycleRun      `json:"run,omitempty" gorm:"foreignKey:OffCycleRunID;references:ID" readonly:"true"`
+}
+
+// OffCycleEarning is a manually entered earnings line of an off-cycle payslip.
+type OffCycleEarning struct {
+	Description string  `json:"description" binding:"required,max=255" example:"THR 2025"`
+	Amount      float64 `json:"amount" binding:"required,gt=0" example:"5000000"`
+}
+
+type OffCycleRunRequest struct {
+	Type      string                    `json:"type" binding:"required,oneof=bonus thr final_settlement" example:"thr"`
+	PayDate   time.Time                 `json:"pay_date" binding:"required" example:"2025-03-24T00:00:00Z"`
+	Note      *string                   `json:"note" binding:"omitempty,max=255" example:"THR Idul Fitri 2025"`
+	Employees []OffCycleEmployeeRequest `json:"employees" binding:"required,min=1,dive"`
+}
+
+type OffCycleEmployeeRequest struct {
+	UserID uint              `json:"user_id" binding:"required" example:"2"`
+	Lines  []OffCycleEarning `json:"lines" binding:"required,min=1,dive"`
+}
@@ -4,12 +4,23 @@ import (
 	"time"
 )
 
+const (
+	OvertimeSourceDeclared   = "declared"
+	OvertimeSourceAttendance = "attendance"
+
+	OvertimeStatusProposed  = "proposed"
+	OvertimeStatusSubmitted = "submitted"
+)
+
 type Overtime struct {
 	BaseModel
-	UserID uint      `json:"user_id" gorm:"not null,uniqueIndex:idx_user_date"`
-	Date   time.Time `json:"date" gorm:"type:DATE;not null,uniqueIndex:idx_user_date"`
-	Hours  int       `json:"hours" gorm:"not null"`
-	Note   *string   `json:"note" gorm:"size:255"`
+	UserID       uint      `json:"user_id" gorm:"not null,uniqueIndex:idx_user_date"`
+	Date         time.Time `json:"date" gorm:"type:DATE;not null,uniqueIndex:idx_user_date"`
+	Hours        int       `json:"hours" gorm:"not null"`
+	Note         *string   `json:"note" gorm:"size:255"`
+	AttendanceID *uint     `json:"attendance_id"`
+	Source       string    `json:"source" gorm:"size:20;default:declared"`
+	Status       string    `json:"status" gorm:"size:20;default:submitted"`
 }
 
 type OvertimeCache struct {
@@ -33,4 +44,6 @@ type OvertimeResponse struct {
 	Date      time.Time  `json:"date"`
 	Hours     int        `json:"hours"`
 	Note      *string    `json:"note"`
+	Source    string     `json:"source" example:"declared"`
+	Status    string     `json:"status" example:"submitted"`
 }
@@ -0,0 +1,33 @@
+package models
+
+// Anomalies flagged on a payroll preview row.
+const (
+	AnomalyMissingSalary    = "missing_salary"
+	AnomalyZeroAttendance   = "zero_attendance"
+	AnomalyTakeHomeSwing    = "take_home_swing"
+	AnomalyNegativeTakeHome = "negative_take_home"
+)
+
+// PayrollPreviewRow is one employee's payslip as it would be generated, next to the
+// take-home pay of the previous period.
+type PayrollPreviewRow struct {
+	UserID               uint     `json:"user_id"`
+	Name                 string   `json:"name"`
+	Payslip              *Payslip `json:"payslip"`
+	PreviousTakeHomePay  *float64 `json:"previous_take_home_pay"`
+	TakeHomePayChange    *float64 `json:"take_home_pay_change"`
+	TakeHomePayChangePct *float64 `json:"take_home_pay_change_percent"`
+	Anomalies            []string `json:"anomalies"`
+}
+
+// PayrollPreview is the dry run of a payroll period. Nothing in it has been saved.
+type PayrollPreview struct {
+	PayrollPeriodID          uint                 `json:"payroll_period_id"`
+	PreviousPayrollPeriodID  *uint                `json:"previous_payroll_period_id"`
+	SwingThresholdPercent    float64              `json:"swing_threshold_percent"`
+	Employees                int                  `json:"employees"`
+	TotalTakeHomePay         float64              `json:"total_take_home_pay"`
+	PreviousTotalTakeHomePay float64              `json:"previous_total_take_home_pay"`
+	Anomalies                map[string]int       `json:"anomalies"`
+	Rows                     []*PayrollPreviewRow `json:"rows"`
+}
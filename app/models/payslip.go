@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 )
 
@@ -14,8 +15,94 @@ type Payslip struct {
 	OvertimeHours      float64   `json:"overtime_hours" gorm:"not null"`
 	OvertimeEarnings   float64   `json:"overtime_earnings" gorm:"not null"`
 	TotalReimbursement float64   `json:"total_reimbursement" gorm:"not null"`
+	LateDays           int       `json:"late_days" gorm:"default:0"`
+	LateMinutes        int       `json:"late_minutes" gorm:"default:0"`
+	LateDeduction      float64   `json:"late_deduction" gorm:"default:0"`
+	UndertimeMinutes   int       `json:"undertime_minutes" gorm:"default:0"`
+	UndertimeDeduction float64   `json:"undertime_deduction" gorm:"default:0"`
+	LoanDeduction      float64   `json:"loan_deduction" gorm:"default:0"`
 	TakeHomePay        float64   `json:"take_home_pay" gorm:"not null"`
 	User               User      `json:"user" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" readonly:"true"`
+
+	// Lines are the payslip's allowances, daily allowances and one-time adjustments, each shown separately.
+	Lines         []PayslipLine  `json:"lines" gorm:"type:jsonb;serializer:json"`
+	PayrollPeriod *PayrollPeriod `json:"payroll_period,omitempty" gorm:"foreignKey:PayrollPeriodID;references:ID" readonly:"true"`
+	// YearToDate totals every payslip of the calendar year up to and including this one.
+	YearToDate *PayslipTotals `json:"year_to_date,omitempty" gorm:"-"`
+}
+
+const (
+	PayslipLineAllowance      = "allowance"
+	PayslipLineDailyAllowance = "daily_allowance"
+	PayslipLineAdjustment     = "adjustment"
+)
+
+// PayslipLine is an extra amount on a payslip. Negative amounts are deductions.
+type PayslipLine struct {
+	Type        string  `json:"type"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+// GrossPay is everything earned on the payslip before deductions.
+func (p *Payslip) GrossPay() float64 {
+	gross := float64(p.AttendanceDays)*p.AttendanceEarnings + p.OvertimeEarnings + p.TotalReimbursement
+	for _, line := range p.Lines {
+		if line.Amount > 0 {
+			gross += line.Amount
+		}
+	}
+	return math.Round(gross*100) / 100
+}
+
+// Deductions is everything taken off the gross pay.
+func (p *Payslip) Deductions() float64 {
+	deductions := p.LateDeduction + p.UndertimeDeduction + p.LoanDeduction
+	for _, line := range p.Lines {
+		if line.Amount < 0 {
+			deductions -= line.Amount
+		}
+	}
+	return math.Round(deductions*100) / 100
+}
+
+// PayslipTotals are the gross pay, deductions and net pay of one or more payslips.
+type PayslipTotals struct {
+	Gross      float64 `json:"gross"`
+	Deductions float64 `json:"deductions"`
+	Net        float64 `json:"net"`
+}
+
+// Add adds one payslip's amounts to the totals.
+func (t *PayslipTotals) Add(gross, deductions, net float64) {
+	t.Gross = math.Round((t.Gross+gross)*100) / 100
+	t.Deductions = math.Round((t.Deductions+deductions)*100) / 100
+	t.Net = math.Round((t.Net+net)*100) / 100
+}
+
+// EarningsStatementLine is one regular or off-cycle payslip on a yearly earnings statement.
+type EarningsStatementLine struct {
+	// Type is "payroll" for a regular payslip, otherwise the off-cycle run type.
+	Type            string    `json:"type"`
+	PayrollPeriodID *uint     `json:"payroll_period_id,omitempty"`
+	OffCycleRunID   *uint     `json:"off_cycle_run_id,omitempty"`
+	Date            time.Time `json:"date"`
+	PayslipTotals
+}
+
+// EarningsStatement is an employee's earnings over a calendar year, as needed for annual tax forms.
+type EarningsStatement struct {
+	UserID             uint                     `json:"user_id"`
+	Name               string                   `json:"name"`
+	Year               int                      `json:"year"`
+	AttendanceEarnings float64                  `json:"attendance_earnings"`
+	OvertimeEarnings   float64                  `json:"overtime_earnings"`
+	Reimbursements     float64                  `json:"reimbursements"`
+	Allowances         float64                  `json:"allowances"`
+	Adjustments        float64                  `json:"adjustments"`
+	OffCycleEarnings   float64                  `json:"off_cycle_earnings"`
+	Totals             PayslipTotals            `json:"totals"`
+	Lines              []*EarningsStatementLine `json:"lines"`
 }
 
 type PayslipSummary struct {
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	PayslipDeliveryPending = "pending"
+	PayslipDeliverySending = "sending"
+	PayslipDeliverySent    = "sent"
+	PayslipDeliveryFailed  = "failed"
+)
+
+// PayslipDelivery records the email that sends an employee their payslip of a payroll period.
+// A pending delivery is retried until it is sent or has used up its attempts, and then fails.
+// While one instance sends it the delivery is "sending", so no other instance picks it up.
+type PayslipDelivery struct {
+	BaseModel
+	UserID          uint       `json:"user_id" gorm:"not null;index"`
+	PayrollPeriodID uint       `json:"payroll_period_id" gorm:"not null;index"`
+	Recipient       string     `json:"recipient" gorm:"size:100;not null"`
+	Status          string     `json:"status" gorm:"size:20;not null;default:pending;index"`
+	Attempts        int        `json:"attempts" gorm:"not null;default:0"`
+	LastError       *string    `json:"last_error" gorm:"type:text"`
+	NextAttemptAt   *time.Time `json:"next_attempt_at" gorm:"default:null"`
+	SentAt          *time.Time `json:"sent_at" gorm:"default:null"`
+}
@@ -4,12 +4,16 @@ import (
 	"time"
 )
 
+// Reimbursement is a claim against a ReimbursementCategory. An employee may submit several
+// claims on the same day. Claims created before categories existed have no CategoryID.
 type Reimbursement struct {
 	BaseModel
-	UserID uint      `json:"user_id" gorm:"not null,uniqueIndex:idx_user_date"`
-	Date   time.Time `json:"date" gorm:"type:DATE;not null,uniqueIndex:idx_user_date"`
-	Amount float64   `json:"amount" gorm:"not null"`
-	Note   *string   `json:"note" gorm:"type:text"`
+	UserID     uint                   `json:"user_id" gorm:"not null;index:idx_reimbursement_user_date"`
+	Date       time.Time              `json:"date" gorm:"type:DATE;not null;index:idx_reimbursement_user_date"`
+	CategoryID *uint                  `json:"category_id" gorm:"index;default:null"`
+	Amount     float64                `json:"amount" gorm:"not null"`
+	Note       *string                `json:"note" gorm:"type:text"`
+	Category   *ReimbursementCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
 }
 
 type ReimbursementCache struct {
@@ -18,18 +22,90 @@ type ReimbursementCache struct {
 }
 
 type ReimbursementRequest struct {
-	Date   time.Time `json:"date" binding:"required" format:"2006-01-02" example:"2025-06-11T00:00:00Z"`
-	Amount float64   `json:"amount" binding:"required,min=0" example:"250000"`
-	Note   *string   `json:"note" binding:"omitempty,max=255" example:"Client lunch and parking"`
+	Date       time.Time `json:"date" binding:"required" format:"2006-01-02" example:"2025-06-11T00:00:00Z"`
+	CategoryID uint      `json:"category_id" binding:"required" example:"3"`
+	Amount     float64   `json:"amount" binding:"required,min=0" example:"250000"`
+	Note       *string   `json:"note" binding:"omitempty,max=255" example:"Client lunch and parking"`
 }
 
 type ReimbursementResponse struct {
-	ID        uint       `json:"id" example:"1"`
-	CreatedAt time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
-	UpdatedAt time.Time  `json:"updated_at" example:"2023-01-02T12:00:00Z"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2023-01-10T00:00:00Z"`
-	UserID    uint       `json:"user_id" example:"101"`
-	Date      time.Time  `json:"date" example:"2023-06-01"`
-	Amount    float64    `json:"amount" example:"250000"`
-	Note      *string    `json:"note" example:"Client lunch and parking"`
+	ID         uint       `json:"id" example:"1"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt  time.Time  `json:"updated_at" example:"2023-01-02T12:00:00Z"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" example:"2023-01-10T00:00:00Z"`
+	UserID     uint       `json:"user_id" example:"101"`
+	Date       time.Time  `json:"date" example:"2023-06-01"`
+	CategoryID *uint      `json:"category_id" example:"3"`
+	Amount     float64    `json:"amount" example:"250000"`
+	Note       *string    `json:"note" example:"Client lunch and parking"`
+}
+
+// ReimbursementCategory groups claims, such as medical, travel or meals, under a monthly and a
+// yearly cap per employee. A nil cap is unlimited.
+type ReimbursementCategory struct {
+	BaseModel
+	Name       string               `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	MonthlyCap *float64             `json:"monthly_cap" gorm:"default:null"`
+	YearlyCap  *float64             `json:"yearly_cap" gorm:"default:null"`
+	IsActive   bool                 `json:"is_active" gorm:"not null;default:true"`
+	Limits     []ReimbursementLimit `json:"limits,omitempty" gorm:"foreignKey:CategoryID"`
+}
+
+// ReimbursementLimit replaces a category's caps for every employee of a grade, or for a single
+// employee when UserID is set. A nil cap is unlimited. A category has at most one live limit per
+// employee and per grade.
+type ReimbursementLimit struct {
+	BaseModel
+	CategoryID uint     `json:"category_id" gorm:"not null;index;uniqueIndex:idx_limit_category_user,where:deleted_at IS NULL;uniqueIndex:idx_limit_category_grade,where:deleted_at IS NULL"`
+	UserID     *uint    `json:"user_id" gorm:"index;default:null;uniqueIndex:idx_limit_category_user,where:deleted_at IS NULL"`
+	Grade      *string  `json:"grade" gorm:"size:50;default:null;uniqueIndex:idx_limit_category_grade,where:deleted_at IS NULL"`
+	MonthlyCap *float64 `json:"monthly_cap" gorm:"default:null"`
+	YearlyCap  *float64 `json:"yearly_cap" gorm:"default:null"`
+}
+
+// CapsFor returns the caps that apply to the user. The user's own limit wins over their
+// grade's, which wins over the category's.
+func (c *ReimbursementCategory) CapsFor(user *User) (monthly, yearly *float64) {
+	var gradeLimit *ReimbursementLimit
+	for i := range c.Limits {
+		limit := &c.Limits[i]
+		if limit.UserID != nil && *limit.UserID == user.ID {
+			return limit.MonthlyCap, limit.YearlyCap
+		}
+		if limit.UserID == nil && limit.Grade != nil && user.Grade != nil && *limit.Grade == *user.Grade {
+			gradeLimit = limit
+		}
+	}
+	if gradeLimit != nil {
+		return gradeLimit.MonthlyCap, gradeLimit.YearlyCap
+	}
+	return c.MonthlyCap, c.YearlyCap
+}
+
+// ReimbursementBudget is an employee's usage of one category in the month and the year of a
+// date. A nil cap, and so a nil remaining amount, is unlimited.
+type ReimbursementBudget struct {
+	CategoryID       uint     `json:"category_id" example:"3"`
+	Category         string   `json:"category" example:"Medical"`
+	MonthlyCap       *float64 `json:"monthly_cap" example:"1000000"`
+	MonthlyUsed      float64  `json:"monthly_used" example:"250000"`
+	MonthlyRemaining *float64 `json:"monthly_remaining" example:"750000"`
+	YearlyCap        *float64 `json:"yearly_cap" example:"6000000"`
+	YearlyUsed       float64  `json:"yearly_used" example:"1750000"`
+	YearlyRemaining  *float64 `json:"yearly_remaining" example:"4250000"`
+}
+
+type ReimbursementCategoryRequest struct {
+	Name       string   `json:"name" binding:"required,max=100" example:"Medical"`
+	MonthlyCap *float64 `json:"monthly_cap" binding:"omitempty,gt=0" example:"1000000"`
+	YearlyCap  *float64 `json:"yearly_cap" binding:"omitempty,gt=0" example:"6000000"`
+	IsActive   *bool    `json:"is_active" example:"true"`
+}
+
+// ReimbursementLimitRequest sets either UserID or Grade.
+type ReimbursementLimitRequest struct {
+	UserID     *uint    `json:"user_id" example:"2"`
+	Grade      *string  `json:"grade" binding:"omitempty,max=50" example:"G5"`
+	MonthlyCap *float64 `json:"monthly_cap" binding:"omitempty,gt=0" example:"2000000"`
+	YearlyCap  *float64 `json:"yearly_cap" binding:"omitempty,gt=0" example:"12000000"`
 }
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+)
+
+// EmployeeAttendanceRate compares the days an employee attended with the days they were expected to.
+// Expected days are weekdays plus rostered weekend shifts, minus leave.
+type EmployeeAttendanceRate struct {
+	UserID         uint    `json:"user_id"`
+	Name           string  `json:"name"`
+	ManagerID      *uint   `json:"manager_id"`
+	ManagerName    *string `json:"-"`
+	ExpectedDays   int     `json:"expected_days"`
+	AttendedDays   int     `json:"attended_days"`
+	LateDays       int     `json:"late_days"`
+	LeaveDays      int     `json:"leave_days"`
+	AttendanceRate float64 `json:"attendance_rate"`
+}
+
+// TeamAttendanceRate aggregates the direct reports of one manager. A nil ManagerID groups
+// employees without a manager.
+type TeamAttendanceRate struct {
+	ManagerID      *uint   `json:"manager_id"`
+	ManagerName    *string `json:"manager_name"`
+	Members        int     `json:"members"`
+	ExpectedDays   int     `json:"expected_days"`
+	AttendedDays   int     `json:"attended_days"`
+	AttendanceRate float64 `json:"attendance_rate"`
+}
+
+type AttendanceRateReport struct {
+	StartDate      string                    `json:"start_date"`
+	EndDate        string                    `json:"end_date"`
+	AttendanceRate float64                   `json:"attendance_rate"`
+	Employees      []*EmployeeAttendanceRate `json:"employees"`
+	Teams          []*TeamAttendanceRate     `json:"teams"`
+}
+
+type OvertimeUserReport struct {
+	UserID uint    `json:"user_id"`
+	Name   string  `json:"name"`
+	Hours  float64 `json:"hours"`
+	Days   int     `json:"days"`
+}
+
+// PayrollPeriodCost totals the payslips generated for one payroll period. OffCycleEarnings are
+// the processed off-cycle runs paid within the period, on top of the take-home pay.
+type PayrollPeriodCost struct {
+	PayrollPeriodID    uint      `json:"payroll_period_id"`
+	StartDate          time.Time `json:"start_date"`
+	EndDate            time.Time `json:"end_date"`
+	Employees          int       `json:"employees"`
+	AttendanceEarnings float64   `json:"attendance_earnings"`
+	OvertimeHours      float64   `json:"overtime_hours"`
+	OvertimeEarnings   float64   `json:"overtime_earnings"`
+	TotalReimbursement float64   `json:"total_reimbursement"`
+	Allowances         float64   `json:"allowances"`
+	Deductions         float64   `json:"deductions"`
+	TakeHomePay        float64   `json:"take_home_pay"`
+	OffCycleEarnings   float64   `json:"off_cycle_earnings"`
+}
+
+// OvertimeCostPoint is one payroll period of the overtime cost trend. Change is the
+// percentage change from the previous period, nil for the first one or after a period without overtime.
+type OvertimeCostPoint struct {
+	PayrollPeriodID uint      `json:"payroll_period_id"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	OvertimeHours   float64   `json:"overtime_hours"`
+	OvertimeCost    float64   `json:"overtime_cost"`
+	Change          *float64  `json:"change"`
+}
+
+type MonthlySpend struct {
+	Month  string  `json:"month"`
+	Claims int     `json:"claims"`
+	Amount float64 `json:"amount"`
+}
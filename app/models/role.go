@@ -2,6 +2,12 @@ package models
 
 import "gorm.io/gorm"
 
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleUser    = "user"
+)
+
 type Role struct {
 	gorm.Model
 	Name        string `gorm:"uniqueIndex;not null;size:10"`
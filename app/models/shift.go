@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// Shift is a working schedule template. StartTime and EndTime are "HH:MM" clock
+// times; when the end is not after the start the shift crosses midnight.
+type Shift struct {
+	BaseModel
+	Name               string `json:"name" gorm:"not null;size:100"`
+	StartTime          string `json:"start_time" gorm:"not null;size:5"`
+	EndTime            string `json:"end_time" gorm:"not null;size:5"`
+	GracePeriodMinutes int    `json:"grace_period_minutes" gorm:"default:0"`
+	CrossesMidnight    bool   `json:"crosses_midnight" gorm:"default:false"`
+}
+
+// Window returns the exact start and end of the shift when worked on the given date.
+func (s *Shift) Window(date time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	startClock, err := time.Parse("15:04", s.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endClock, err := time.Parse("15:04", s.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	y, m, d := date.Date()
+	start := time.Date(y, m, d, startClock.Hour(), startClock.Minute(), 0, 0, loc)
+	end := time.Date(y, m, d, endClock.Hour(), endClock.Minute(), 0, 0, loc)
+	if s.CrossesMidnight {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
+
+// ShiftAssignment puts a user on a shift for one roster date. For overnight
+// shifts the date is the day the shift starts.
+type ShiftAssignment struct {
+	BaseModel
+	UserID  uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_shift_user_date"`
+	ShiftID uint      `json:"shift_id" gorm:"not null"`
+	Date    time.Time `json:"date" gorm:"type:DATE;not null;uniqueIndex:idx_shift_user_date"`
+	Shift   Shift     `gorm:"foreignKey:ShiftID" json:"shift" readonly:"true"`
+}
+
+type ShiftCache struct {
+	Shifts []*Shift `json:"shifts"`
+	Total  int64    `json:"total"`
+}
+
+type ShiftRequest struct {
+	Name               string `json:"name" binding:"required,max=100" example:"Night Shift"`
+	StartTime          string `json:"start_time" binding:"required,datetime=15:04" example:"22:00"`
+	EndTime            string `json:"end_time" binding:"required,datetime=15:04" example:"06:00"`
+	GracePeriodMinutes int    `json:"grace_period_minutes" binding:"min=0" example:"15"`
+}
+
+type ShiftAssignmentRequest struct {
+	UserID          uint      `json:"user_id" binding:"required" example:"2"`
+	ShiftID         uint      `json:"shift_id" binding:"required" example:"1"`
+	StartDate       time.Time `json:"start_date" binding:"required" example:"2025-06-02T00:00:00Z"`
+	EndDate         time.Time `json:"end_date" binding:"required" example:"2025-06-06T00:00:00Z"`
+	IncludeWeekends bool      `json:"include_weekends" example:"false"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+)
+
+const (
+	GeofenceCircle  = "circle"
+	GeofencePolygon = "polygon"
+)
+
+// Site is an office location employees are expected to check in from.
+// A site is either a circle (center + radius) or a polygon of lat/lng points.
+type Site struct {
+	BaseModel
+	Name         string   `json:"name" gorm:"not null;size:100"`
+	Type         string   `json:"type" gorm:"not null;size:10;default:circle"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	RadiusMeters *float64 `json:"radius_meters"`
+	Polygon      *string  `json:"polygon" gorm:"type:text"`
+	Timezone     *string  `json:"timezone" gorm:"size:64"`
+	IsActive     bool     `json:"is_active" gorm:"default:true"`
+}
+
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PolygonPoints decodes the stored polygon into a list of points.
+func (s *Site) PolygonPoints() ([]GeoPoint, error) {
+	var points []GeoPoint
+	if s.Polygon == nil {
+		return points, nil
+	}
+	if err := json.Unmarshal([]byte(*s.Polygon), &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+type SiteCache struct {
+	Sites []*Site `json:"sites"`
+	Total int64   `json:"total"`
+}
+
+type SiteRequest struct {
+	Name         string     `json:"name" binding:"required,max=100" example:"Head Office"`
+	Type         string     `json:"type" binding:"required,oneof=circle polygon" example:"circle"`
+	Latitude     *float64   `json:"latitude" binding:"omitempty,min=-90,max=90" example:"-6.2088"`
+	Longitude    *float64   `json:"longitude" binding:"omitempty,min=-180,max=180" example:"106.8456"`
+	RadiusMeters *float64   `json:"radius_meters" binding:"omitempty,gt=0" example:"150"`
+	Polygon      []GeoPoint `json:"polygon" binding:"omitempty,min=3,dive"`
+	Timezone     *string    `json:"timezone" binding:"omitempty,max=64" example:"Asia/Jakarta"`
+	IsActive     *bool      `json:"is_active" example:"true"`
+}
@@ -6,12 +6,34 @@ type User struct {
 	gorm.Model
 	Name          string   `gorm:"not null;size:100" json:"name"`
 	Email         string   `gorm:"uniqueIndex;not null;size:100" json:"email"`
-	Password      string   `gorm:"not null;size:100" json:"password"`
+	Password      string   `gorm:"not null;size:100" json:"-"`
 	RoleID        uint     `gorm:"not null" json:"role_id"`
 	MonthlySalary *float64 `gorm:"default:0" json:"monthly_salary"`
+	ManagerID     *uint    `gorm:"default:null" json:"manager_id"`
+	EmployeeCode  *string  `gorm:"uniqueIndex;size:50;default:null" json:"employee_code"`
+	Timezone      *string  `gorm:"size:64;default:null" json:"timezone"`
+	Grade         *string  `gorm:"size:50;default:null" json:"grade"`
 	Role          Role     `gorm:"foreignKey:RoleID;references:ID" json:"role" readonly:"true"`
 }
 
+// UserManagerRequest assigns the manager who reviews a user's attendance corrections. A null
+// manager_id removes the manager.
+type UserManagerRequest struct {
+	ManagerID *uint `json:"manager_id" example:"3"`
+}
+
+// UserTimezoneRequest sets the IANA timezone a user's attendance dates are bucketed in. An
+// empty or null timezone falls back to the site and then the company timezone.
+type UserTimezoneRequest struct {
+	Timezone *string `json:"timezone" binding:"omitempty,max=64" example:"Asia/Jakarta"`
+}
+
+// UserGradeRequest sets the grade whose reimbursement limits apply to a user. An empty or null
+// grade clears it.
+type UserGradeRequest struct {
+	Grade *string `json:"grade" binding:"omitempty,max=50" example:"G5"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"email@example.com"`
 	Password string `json:"password" binding:"required,min=6,max=100" example:"yourpassword"`
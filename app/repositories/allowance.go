@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type AllowanceRepository interface {
+	GetAssignmentList(userID uint, pagination utils.Pagination) ([]*models.AllowanceAssignment, int64, error)
+	GetAssignmentByID(id uint) (*models.AllowanceAssignment, error)
+	CreateAssignment(ctx context.Context, assignment *models.AllowanceAssignment) (*models.AllowanceAssignment, error)
+	UpdateAssignment(ctx context.Context, assignment *models.AllowanceAssignment) (*models.AllowanceAssignment, error)
+	DeleteAssignment(id uint) error
+	GetActiveAssignments(userID uint, startDate, endDate string) ([]*models.AllowanceAssignment, error)
+	GetAdjustmentList(periodID uint, userID uint) ([]*models.PayrollAdjustment, error)
+	GetAdjustmentByID(id uint) (*models.PayrollAdjustment, error)
+	CreateAdjustment(ctx context.Context, adjustment *models.PayrollAdjustment) (*models.PayrollAdjustment, error)
+	DeleteAdjustment(id uint) error
+}
+
+type allowanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAllowanceRepository(db *gorm.DB) AllowanceRepository {
+	return &allowanceRepository{
+		db: db,
+	}
+}
+
+// GetAssignmentList lists allowance assignments, latest start date first. A zero userID lists
+// every user.
+func (r *allowanceRepository) GetAssignmentList(userID uint, pagination utils.Pagination) ([]*models.AllowanceAssignment, int64, error) {
+	var assignments []*models.AllowanceAssignment
+	var total int64
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	query := r.db.Model(&models.AllowanceAssignment{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Limit(pagination.Limit).Offset(offset).Order("start_date DESC").Find(&assignments).Error; err != nil {
+		return nil, 0, err
+	}
+	return assignments, total, nil
+}
+
+func (r *allowanceRepository) GetAssignmentByID(id uint) (*models.AllowanceAssignment, error) {
+	var assignment models.AllowanceAssignment
+	if err := r.db.First(&assignment, id).Error; err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *allowanceRepository) CreateAssignment(ctx context.Context, assignment *models.AllowanceAssignment) (*models.AllowanceAssignment, error) {
+	if err := r.db.WithContext(ctx).Create(assignment).Error; err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (r *allowanceRepository) UpdateAssignment(ctx context.Context, assignment *models.AllowanceAssignment) (*models.AllowanceAssignment, error) {
+	if err := r.db.WithContext(ctx).Save(assignment).Error; err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (r *allowanceRepository) DeleteAssignment(id uint) error {
+	return r.db.Delete(&models.AllowanceAssignment{}, id).Error
+}
+
+// GetActiveAssignments lists the user's allowances effective on any day of the date range.
+func (r *allowanceRepository) GetActiveAssignments(userID uint, startDate, endDate string) ([]*models.AllowanceAssignment, error) {
+	var assignments []*models.AllowanceAssignment
+	err := r.db.Where("user_id = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)", userID, endDate, startDate).
+		Order("id ASC").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// GetAdjustmentList lists the adjustments of a payroll period. A zero userID lists every user.
+func (r *allowanceRepository) GetAdjustmentList(periodID uint, userID uint) ([]*models.PayrollAdjustment, error) {
+	var adjustments []*models.PayrollAdjustment
+	query := r.db.Where("payroll_period_id = ?", periodID)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Order("id ASC").Find(&adjustments).Error; err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+func (r *allowanceRepository) GetAdjustmentByID(id uint) (*models.PayrollAdjustment, error) {
+	var adjustment models.PayrollAdjustment
+	if err := r.db.First(&adjustment, id).Error; err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+func (r *allowanceRepository) CreateAdjustment(ctx context.Context, adjustment *models.PayrollAdjustment) (*models.PayrollAdjustment, error) {
+	if err := r.db.WithContext(ctx).Create(adjustment).Error; err != nil {
+		return nil, err
+	}
+	return adjustment, nil
+}
+
+func (r *allowanceRepository) DeleteAdjustment(id uint) error {
+	return r.db.Delete(&models.PayrollAdjustment{}, id).Error
+}
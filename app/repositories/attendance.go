@@ -5,6 +5,7 @@ import (
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AttendanceRepository interface {
@@ -14,7 +15,14 @@ type AttendanceRepository interface {
 	CreateAttendance(ctx context.Context, attendance *models.Attendance) (*models.Attendance, error)
 	UpdateAttendance(ctx context.Context, attendance *models.Attendance) (*models.Attendance, error)
 	DeleteAttendance(id uint) error
+	CreateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error)
+	UpdateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error)
+	DeleteDeviceSessions(attendanceID uint) error
 	CountWorkingDays(userID uint, startDate, endDate string) (int64, error)
+	SumPenalties(userID uint, startDate, endDate string) (*models.AttendancePenaltySummary, error)
+	CountAllowanceDays(userID uint, startDate, endDate string, rule models.DailyAllowanceRule) (int64, error)
+	GetOpenAttendances(beforeDate string) ([]*models.Attendance, error)
+	Transaction(fn func(repo AttendanceRepository) error) error
 }
 
 type attendanceRepository struct {
@@ -29,7 +37,9 @@ func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
 
 func (r *attendanceRepository) GetAttendanceList(userID uint, startDate, endDate string) ([]*models.Attendance, error) {
 	var attendances []*models.Attendance
-	query := r.db.Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate)
+	query := r.db.Preload("Sessions", sessionOrder).Preload("Shift").
+		Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).
+		Order("date ASC")
 	if err := query.Find(&attendances).Error; err != nil {
 		return nil, err
 	}
@@ -38,7 +48,7 @@ func (r *attendanceRepository) GetAttendanceList(userID uint, startDate, endDate
 
 func (r *attendanceRepository) GetAttendanceByID(id uint) (*models.Attendance, error) {
 	var attendance models.Attendance
-	if err := r.db.First(&attendance, id).Error; err != nil {
+	if err := r.db.Preload("Sessions", sessionOrder).Preload("Shift").First(&attendance, id).Error; err != nil {
 		return nil, err
 	}
 	return &attendance, nil
@@ -46,7 +56,7 @@ func (r *attendanceRepository) GetAttendanceByID(id uint) (*models.Attendance, e
 
 func (r *attendanceRepository) GetAttendanceByUserAndDate(userID uint, date string) (*models.Attendance, error) {
 	var attendance models.Attendance
-	if err := r.db.Where("user_id = ? AND date = ?", userID, date).First(&attendance).Error; err != nil {
+	if err := r.db.Preload("Sessions", sessionOrder).Preload("Shift").Where("user_id = ? AND date = ?", userID, date).First(&attendance).Error; err != nil {
 		return nil, err
 	}
 	return &attendance, nil
@@ -60,7 +70,8 @@ func (r *attendanceRepository) CreateAttendance(ctx context.Context, attendance
 }
 
 func (r *attendanceRepository) UpdateAttendance(ctx context.Context, attendance *models.Attendance) (*models.Attendance, error) {
-	if err := r.db.WithContext(ctx).Save(attendance).Error; err != nil {
+	// Sessions are written through CreateSession/UpdateSession, not as associations
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(attendance).Error; err != nil {
 		return nil, err
 	}
 	return attendance, nil
@@ -73,6 +84,36 @@ func (r *attendanceRepository) DeleteAttendance(id uint) error {
 	return nil
 }
 
+func (r *attendanceRepository) CreateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error) {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (r *attendanceRepository) UpdateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error) {
+	if err := r.db.WithContext(ctx).Save(session).Error; err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Transaction runs fn against a repository bound to one database transaction, which is
+// rolled back when fn returns an error.
+func (r *attendanceRepository) Transaction(fn func(repo AttendanceRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&attendanceRepository{db: tx})
+	})
+}
+
+// DeleteDeviceSessions removes the sessions paired from terminal punches so they can be
+// rebuilt. They are derived data, so they are deleted permanently.
+func (r *attendanceRepository) DeleteDeviceSessions(attendanceID uint) error {
+	return r.db.Unscoped().
+		Where("attendance_id = ? AND device_id IS NOT NULL", attendanceID).
+		Delete(&models.AttendanceSession{}).Error
+}
+
 func (r *attendanceRepository) CountWorkingDays(userID uint, startDate, endDate string) (int64, error) {
 	var count int64
 	query := r.db.Model(&models.Attendance{}).
@@ -82,4 +123,55 @@ func (r *attendanceRepository) CountWorkingDays(userID uint, startDate, endDate
 		return 0, err
 	}
 	return count, nil
+}
+
+func (r *attendanceRepository) SumPenalties(userID uint, startDate, endDate string) (*models.AttendancePenaltySummary, error) {
+	var summary models.AttendancePenaltySummary
+	query := r.db.Model(&models.Attendance{}).
+		Select("COUNT(CASE WHEN late_minutes > 0 THEN 1 END) AS late_days, "+
+			"COALESCE(SUM(late_minutes), 0) AS late_minutes, "+
+			"COALESCE(SUM(undertime_minutes), 0) AS undertime_minutes").
+		Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("check_in IS NOT NULL AND check_out IS NOT NULL")
+	if err := query.Scan(&summary).Error; err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// CountAllowanceDays counts the completed attendance days that satisfy the daily allowance rule.
+func (r *attendanceRepository) CountAllowanceDays(userID uint, startDate, endDate string, rule models.DailyAllowanceRule) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.Attendance{}).
+		Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("check_in IS NOT NULL AND check_out IS NOT NULL").
+		Where("late_minutes <= ?", rule.MaxLateMinutes)
+	if rule.RequireFullHours {
+		query = query.Where("undertime_minutes = 0")
+	}
+	if rule.RequireOnSite {
+		// A day without a site check-in was worked remotely, not on site
+		query = query.Where("site_id IS NOT NULL AND is_outside_geofence = false")
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetOpenAttendances returns attendances before the given date that were never checked out
+// and have not been handled by the close-out job yet.
+func (r *attendanceRepository) GetOpenAttendances(beforeDate string) ([]*models.Attendance, error) {
+	var attendances []*models.Attendance
+	query := r.db.Preload("Sessions", sessionOrder).Preload("Shift").
+		Where("date < ? AND check_out IS NULL", beforeDate).
+		Where("close_out_status IS NULL OR close_out_status = ''")
+	if err := query.Find(&attendances).Error; err != nil {
+		return nil, err
+	}
+	return attendances, nil
+}
+
+func sessionOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("check_in ASC")
 }
\ No newline at end of file
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type AttendanceCorrectionRepository interface {
+	GetCorrectionList(filter models.CorrectionFilter, pagination utils.Pagination) ([]*models.AttendanceCorrection, int64, error)
+	GetCorrectionByID(id uint) (*models.AttendanceCorrection, error)
+	HasPendingCorrection(attendanceID uint) (bool, error)
+	CreateCorrection(ctx context.Context, correction *models.AttendanceCorrection) (*models.AttendanceCorrection, error)
+	ReviewCorrection(ctx context.Context, correction *models.AttendanceCorrection, apply func(repo AttendanceRepository) error) (*models.AttendanceCorrection, error)
+}
+
+type attendanceCorrectionRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceCorrectionRepository(db *gorm.DB) AttendanceCorrectionRepository {
+	return &attendanceCorrectionRepository{
+		db: db,
+	}
+}
+
+func (r *attendanceCorrectionRepository) GetCorrectionList(filter models.CorrectionFilter, pagination utils.Pagination) ([]*models.AttendanceCorrection, int64, error) {
+	var corrections []*models.AttendanceCorrection
+	var total int64
+
+	query := r.db.Model(&models.AttendanceCorrection{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ManagerID != 0 {
+		query = query.Where("user_id IN (?)", r.db.Model(&models.User{}).Select("id").Where("manager_id = ?", filter.ManagerID))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Order("created_at ASC").
+		Find(&corrections).Error; err != nil {
+		return nil, 0, err
+	}
+	return corrections, total, nil
+}
+
+func (r *attendanceCorrectionRepository) GetCorrectionByID(id uint) (*models.AttendanceCorrection, error) {
+	var correction models.AttendanceCorrection
+	if err := r.db.First(&correction, id).Error; err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+func (r *attendanceCorrectionRepository) HasPendingCorrection(attendanceID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.AttendanceCorrection{}).
+		Where("attendance_id = ? AND status = ?", attendanceID, models.CorrectionStatusPending).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *attendanceCorrectionRepository) CreateCorrection(ctx context.Context, correction *models.AttendanceCorrection) (*models.AttendanceCorrection, error) {
+	if err := r.db.WithContext(ctx).Create(correction).Error; err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
+
+// ReviewCorrection saves a reviewed correction. A non-nil apply changes the attendance through
+// a repository bound to the same transaction, so an approval is never saved without its
+// change to the attendance, or the other way around.
+func (r *attendanceCorrectionRepository) ReviewCorrection(ctx context.Context, correction *models.AttendanceCorrection, apply func(repo AttendanceRepository) error) (*models.AttendanceCorrection, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if apply != nil {
+			if err := apply(&attendanceRepository{db: tx}); err != nil {
+				return err
+			}
+		}
+		return tx.Save(correction).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
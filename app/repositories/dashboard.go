@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"github.com/galiherlangga/go-attendance/app/models"
+	"gorm.io/gorm"
+)
+
+// DashboardRepository reads attendance data for a whole team at once. Every method
+// runs a single query regardless of the team size. A zero managerID means every
+// non-admin user, otherwise only the manager's direct reports.
+type DashboardRepository interface {
+	GetTeamMembers(managerID uint) ([]*models.TeamMember, error)
+	GetTeamAttendances(managerID uint, startDate, endDate string) ([]*models.TeamAttendance, error)
+	GetTeamLeaves(managerID uint, startDate, endDate string) ([]*models.Leave, error)
+	GetTeamAssignments(managerID uint, startDate, endDate string) ([]*models.ShiftAssignment, error)
+}
+
+type dashboardRepository struct {
+	db *gorm.DB
+}
+
+func NewDashboardRepository(db *gorm.DB) DashboardRepository {
+	return &dashboardRepository{
+		db: db,
+	}
+}
+
+// teamUserIDs is a subquery selecting the IDs of the team members.
+func (r *dashboardRepository) teamUserIDs(managerID uint) *gorm.DB {
+	query := r.db.Model(&models.User{}).
+		Select("users.id").
+		Joins("JOIN roles ON roles.id = users.role_id").
+		Where("roles.name <> ?", models.RoleAdmin)
+	if managerID != 0 {
+		query = query.Where("users.manager_id = ?", managerID)
+	}
+	return query
+}
+
+func (r *dashboardRepository) GetTeamMembers(managerID uint) ([]*models.TeamMember, error) {
+	var members []*models.TeamMember
+	if err := r.db.Model(&models.User{}).
+		Select("id, name, employee_code, timezone").
+		Where("id IN (?)", r.teamUserIDs(managerID)).
+		Order("name ASC").
+		Scan(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *dashboardRepository) GetTeamAttendances(managerID uint, startDate, endDate string) ([]*models.TeamAttendance, error) {
+	var attendances []*models.TeamAttendance
+	if err := r.db.Model(&models.Attendance{}).
+		Select("user_id, date, check_in, check_out, late_minutes").
+		Where("user_id IN (?) AND date BETWEEN ? AND ?", r.teamUserIDs(managerID), startDate, endDate).
+		Scan(&attendances).Error; err != nil {
+		return nil, err
+	}
+	return attendances, nil
+}
+
+func (r *dashboardRepository) GetTeamLeaves(managerID uint, startDate, endDate string) ([]*models.Leave, error) {
+	var leaves []*models.Leave
+	if err := r.db.Where("user_id IN (?) AND start_date <= ? AND end_date >= ?", r.teamUserIDs(managerID), endDate, startDate).
+		Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+func (r *dashboardRepository) GetTeamAssignments(managerID uint, startDate, endDate string) ([]*models.ShiftAssignment, error) {
+	var assignments []*models.ShiftAssignment
+	if err := r.db.Preload("Shift").
+		Where("user_id IN (?) AND date BETWEEN ? AND ?", r.teamUserIDs(managerID), startDate, endDate).
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type DeviceRepository interface {
+	GetDeviceList(pagination utils.Pagination) ([]*models.Device, int64, error)
+	GetDeviceByID(id uint) (*models.Device, error)
+	GetDeviceByAPIKeyHash(hash string) (*models.Device, error)
+	CreateDevice(ctx context.Context, device *models.Device) (*models.Device, error)
+	UpdateDevice(ctx context.Context, device *models.Device) (*models.Device, error)
+	DeleteDevice(id uint) error
+	TouchDevice(id uint, seenAt time.Time) error
+}
+
+type deviceRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) DeviceRepository {
+	return &deviceRepository{
+		db: db,
+	}
+}
+
+func (r *deviceRepository) GetDeviceList(pagination utils.Pagination) ([]*models.Device, int64, error) {
+	var devices []*models.Device
+	var total int64
+
+	query := r.db.Model(&models.Device{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Order("name ASC").
+		Find(&devices).Error; err != nil {
+		return nil, 0, err
+	}
+	return devices, total, nil
+}
+
+func (r *deviceRepository) GetDeviceByID(id uint) (*models.Device, error) {
+	var device models.Device
+	if err := r.db.First(&device, id).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) GetDeviceByAPIKeyHash(hash string) (*models.Device, error) {
+	var device models.Device
+	if err := r.db.Where("api_key_hash = ?", hash).First(&device).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) CreateDevice(ctx context.Context, device *models.Device) (*models.Device, error) {
+	if err := r.db.WithContext(ctx).Create(device).Error; err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func (r *deviceRepository) UpdateDevice(ctx context.Context, device *models.Device) (*models.Device, error) {
+	if err := r.db.WithContext(ctx).Omit("Site").Save(device).Error; err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func (r *deviceRepository) DeleteDevice(id uint) error {
+	if err := r.db.Delete(&models.Device{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// TouchDevice records when a device last pushed punches. It skips the audit callbacks
+// since it is not a change made by a user.
+func (r *deviceRepository) TouchDevice(id uint, seenAt time.Time) error {
+	return r.db.Model(&models.Device{}).Where("id = ?", id).UpdateColumn("last_seen_at", seenAt).Error
+}
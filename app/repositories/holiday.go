@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"gorm.io/gorm"
+)
+
+type HolidayRepository interface {
+	GetHolidayList(startDate, endDate string) ([]*models.Holiday, error)
+	IsHoliday(date string) (bool, error)
+	CreateHoliday(ctx context.Context, holiday *models.Holiday) (*models.Holiday, error)
+	DeleteHoliday(id uint) error
+}
+
+type holidayRepository struct {
+	db *gorm.DB
+}
+
+func NewHolidayRepository(db *gorm.DB) HolidayRepository {
+	return &holidayRepository{
+		db: db,
+	}
+}
+
+func (r *holidayRepository) GetHolidayList(startDate, endDate string) ([]*models.Holiday, error) {
+	var holidays []*models.Holiday
+	if err := r.db.Where("date BETWEEN ? AND ?", startDate, endDate).
+		Order("date ASC").
+		Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (r *holidayRepository) IsHoliday(date string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Holiday{}).Where("date = ?", date).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *holidayRepository) CreateHoliday(ctx context.Context, holiday *models.Holiday) (*models.Holiday, error) {
+	if err := r.db.WithContext(ctx).Create(holiday).Error; err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (r *holidayRepository) DeleteHoliday(id uint) error {
+	// Hard delete so the date can be declared again
+	if err := r.db.Unscoped().Delete(&models.Holiday{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
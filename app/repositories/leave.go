@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"gorm.io/gorm"
+)
+
+type LeaveRepository interface {
+	GetLeaveList(userID uint, startDate, endDate string) ([]*models.Leave, error)
+	GetLeaveByID(id uint) (*models.Leave, error)
+	HasOverlappingLeave(userID uint, startDate, endDate string) (bool, error)
+	CreateLeave(ctx context.Context, leave *models.Leave) (*models.Leave, error)
+	DeleteLeave(id uint) error
+}
+
+type leaveRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveRepository(db *gorm.DB) LeaveRepository {
+	return &leaveRepository{
+		db: db,
+	}
+}
+
+// GetLeaveList returns the leaves overlapping the date range. A zero userID lists every user.
+func (r *leaveRepository) GetLeaveList(userID uint, startDate, endDate string) ([]*models.Leave, error) {
+	var leaves []*models.Leave
+	query := r.db.Where("start_date <= ? AND end_date >= ?", endDate, startDate)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Order("start_date ASC").Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+func (r *leaveRepository) GetLeaveByID(id uint) (*models.Leave, error) {
+	var leave models.Leave
+	if err := r.db.First(&leave, id).Error; err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+func (r *leaveRepository) HasOverlappingLeave(userID uint, startDate, endDate string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Leave{}).
+		Where("user_id = ? AND start_date <= ? AND end_date >= ?", userID, endDate, startDate).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *leaveRepository) CreateLeave(ctx context.Context, leave *models.Leave) (*models.Leave, error) {
+	if err := r.db.WithContext(ctx).Create(leave).Error; err != nil {
+		return nil, err
+	}
+	return leave, nil
+}
+
+func (r *leaveRepository) DeleteLeave(id uint) error {
+	if err := r.db.Delete(&models.Leave{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type LoanRepository interface {
+	GetLoanList(userID uint, pagination utils.Pagination) ([]*models.Loan, int64, error)
+	GetLoanByID(id uint) (*models.Loan, error)
+	CreateLoan(ctx context.Context, loan *models.Loan) (*models.Loan, error)
+	CreateInstallment(ctx context.Context, installment *models.LoanInstallment) error
+	DeleteLoan(id uint) error
+	GetDueInstallments(userID uint, dueBy string) ([]*models.LoanInstallment, error)
+}
+
+type loanRepository struct {
+	db *gorm.DB
+}
+
+func NewLoanRepository(db *gorm.DB) LoanRepository {
+	return &loanRepository{
+		db: db,
+	}
+}
+
+// GetLoanList lists loans, latest first. A zero userID lists every user.
+func (r *loanRepository) GetLoanList(userID uint, pagination utils.Pagination) ([]*models.Loan, int64, error) {
+	var loans []*models.Loan
+	var total int64
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	query := r.db.Model(&models.Loan{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Limit(pagination.Limit).Offset(offset).Order("created_at DESC").Find(&loans).Error; err != nil {
+		return nil, 0, err
+	}
+	return loans, total, nil
+}
+
+func (r *loanRepository) GetLoanByID(id uint) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.db.Preload("Installments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sequence ASC")
+	}).First(&loan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+func (r *loanRepository) CreateLoan(ctx context.Context, loan *models.Loan) (*models.Loan, error) {
+	if err := r.db.WithContext(ctx).Omit("Installments", "User").Create(loan).Error; err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+func (r *loanRepository) CreateInstallment(ctx context.Context, installment *models.LoanInstallment) error {
+	return r.db.WithContext(ctx).Create(installment).Error
+}
+
+func (r *loanRepository) DeleteLoan(id uint) error {
+	if err := r.db.Where("loan_id = ?", id).Delete(&models.LoanInstallment{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&models.Loan{}, id).Error
+}
+
+// GetDueInstallments lists the user's unpaid installments due on or before the date, overdue
+// ones included.
+func (r *loanRepository) GetDueInstallments(userID uint, dueBy string) ([]*models.LoanInstallment, error) {
+	var installments []*models.LoanInstallment
+	err := r.db.Where("is_paid = false AND due_date <= ?", dueBy).
+		Where("loan_id IN (?)", userLoans(r.db, userID)).
+		Order("due_date ASC").
+		Find(&installments).Error
+	if err != nil {
+		return nil, err
+	}
+	return installments, nil
+}
+
+// markInstallmentsPaid settles the installments GetDueInstallments returns against the payroll
+// period and lowers the remaining balances. It takes the payslip's transaction.
+func markInstallmentsPaid(db *gorm.DB, userID uint, dueBy string, periodID uint, paidAt time.Time) error {
+	err := db.Model(&models.LoanInstallment{}).
+		Where("is_paid = false AND due_date <= ?", dueBy).
+		Where("loan_id IN (?)", userLoans(db, userID)).
+		Updates(map[string]interface{}{"is_paid": true, "paid_at": paidAt, "payroll_period_id": periodID}).Error
+	if err != nil {
+		return err
+	}
+	return refreshBalances(db, "user_id = ?", userID)
+}
+
+// reverseInstallments releases the installments paid by the payroll period so the next run
+// deducts them again. It takes the transaction voiding the period's payslips.
+func reverseInstallments(db *gorm.DB, periodID uint) error {
+	var loanIDs []uint
+	err := db.Model(&models.LoanInstallment{}).
+		Where("payroll_period_id = ?", periodID).
+		Distinct().Pluck("loan_id", &loanIDs).Error
+	if err != nil {
+		return err
+	}
+	if len(loanIDs) == 0 {
+		return nil
+	}
+
+	err = db.Model(&models.LoanInstallment{}).
+		Where("payroll_period_id = ?", periodID).
+		Updates(map[string]interface{}{"is_paid": false, "paid_at": nil, "payroll_period_id": nil}).Error
+	if err != nil {
+		return err
+	}
+	return refreshBalances(db, "id IN ?", loanIDs)
+}
+
+func userLoans(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Model(&models.Loan{}).Select("id").Where("user_id = ?", userID)
+}
+
+// refreshBalances recomputes the remaining balance of the matching loans from their paid installments.
+func refreshBalances(db *gorm.DB, query string, args ...interface{}) error {
+	return db.Model(&models.Loan{}).Where(query, args...).
+		Update("remaining_balance", gorm.Expr(`ROUND((principal - COALESCE((
+			SELECT SUM(loan_installments.amount) FROM loan_installments
+			WHERE loan_installments.loan_id = loans.id AND loan_installments.is_paid = true AND loan_installments.deleted_at IS NULL
+		), 0))::numeric, 2)`)).Error
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	GetNotificationList(userID uint, pagination utils.Pagination) ([]*models.Notification, int64, error)
+	GetNotificationByID(id uint) (*models.Notification, error)
+	CreateNotification(ctx context.Context, notification *models.Notification) (*models.Notification, error)
+	MarkAsRead(id uint) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) GetNotificationList(userID uint, pagination utils.Pagination) ([]*models.Notification, int64, error) {
+	var notifications []*models.Notification
+	var total int64
+
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Order("created_at DESC").
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) GetNotificationByID(id uint) (*models.Notification, error) {
+	var notification models.Notification
+	if err := r.db.First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) CreateNotification(ctx context.Context, notification *models.Notification) (*models.Notification, error) {
+	if err := r.db.WithContext(ctx).Create(notification).Error; err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+func (r *notificationRepository) MarkAsRead(id uint) error {
+	if err := r.db.Model(&models.Notification{}).
+		Where("id = ? AND read_at IS NULL", id).
+		Update("read_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type OffCycleRepository interface {
+	GetRunList(pagination utils.Pagination) ([]*models.OffCycleRun, int64, error)
+	GetRunByID(id uint) (*models.OffCycleRun, error)
+	CreateRun(ctx context.Context, run *models.OffCycleRun) (*models.OffCycleRun, error)
+	MarkRunAsProcessed(id uint, processedAt time.Time) error
+	DeleteRun(id uint) error
+	GetProcessedPayslipsByUser(userID uint) ([]*models.OffCyclePayslip, error)
+	GetProcessedPayslipsBetween(userID uint, startDate, endDate string) ([]*models.OffCyclePayslip, error)
+	HasFinalSettlement(userID uint) (bool, error)
+}
+
+type offCycleRepository struct {
+	db *gorm.DB
+}
+
+func NewOffCycleRepository(db *gorm.DB) OffCycleRepository {
+	return &offCycleRepository{
+		db: db,
+	}
+}
+
+func (r *offCycleRepository) GetRunList(pagination utils.Pagination) ([]*models.OffCycleRun, int64, error) {
+	var runs []*models.OffCycleRun
+	var total int64
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	query := r.db.Model(&models.OffCycleRun{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Limit(pagination.Limit).Offset(offset).Order("pay_date DESC").Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+func (r *offCycleRepository) GetRunByID(id uint) (*models.OffCycleRun, error) {
+	var run models.OffCycleRun
+	if err := r.db.Preload("Payslips.User").First(&run, id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CreateRun creates the run together with its payslips, so a failed payslip leaves no partial run behind.
+func (r *offCycleRepository) CreateRun(ctx context.Context, run *models.OffCycleRun) (*models.OffCycleRun, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Payslips").Create(run).Error; err != nil {
+			return err
+		}
+		for i := range run.Payslips {
+			payslipCtx := utils.WithFreshRequestID(ctx)
+			run.Payslips[i].OffCycleRunID = run.ID
+			if err := tx.WithContext(payslipCtx).Omit("User", "Run").Create(&run.Payslips[i]).Error; err != nil {
+				return fmt.Errorf("failed to create payslip for employee %d: %w", run.Payslips[i].UserID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (r *offCycleRepository) MarkRunAsProcessed(id uint, processedAt time.Time) error {
+	return r.db.Model(&models.OffCycleRun{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"is_processed": true, "processed_at": processedAt}).Error
+}
+
+func (r *offCycleRepository) DeleteRun(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("off_cycle_run_id = ?", id).Delete(&models.OffCyclePayslip{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.OffCycleRun{}, id).Error
+	})
+}
+
+// GetProcessedPayslipsByUser lists the user's payslips of processed runs, latest pay date first.
+func (r *offCycleRepository) GetProcessedPayslipsByUser(userID uint) ([]*models.OffCyclePayslip, error) {
+	var payslips []*models.OffCyclePayslip
+	err := r.db.Preload("Run").
+		Joins("JOIN off_cycle_runs ON off_cycle_runs.id = off_cycle_payslips.off_cycle_run_id AND off_cycle_runs.deleted_at IS NULL").
+		Where("off_cycle_payslips.user_id = ? AND off_cycle_runs.is_processed = true", userID).
+		Order("off_cycle_runs.pay_date DESC").
+		Find(&payslips).Error
+	if err != nil {
+		return nil, err
+	}
+	return payslips, nil
+}
+
+// GetProcessedPayslipsBetween lists the user's payslips of processed runs paid within the range,
+// earliest pay date first.
+func (r *offCycleRepository) GetProcessedPayslipsBetween(userID uint, startDate, endDate string) ([]*models.OffCyclePayslip, error) {
+	var payslips []*models.OffCyclePayslip
+	err := r.db.Preload("Run").
+		Joins("JOIN off_cycle_runs ON off_cycle_runs.id = off_cycle_payslips.off_cycle_run_id AND off_cycle_runs.deleted_at IS NULL").
+		Where("off_cycle_payslips.user_id = ? AND off_cycle_runs.is_processed = true", userID).
+		Where("off_cycle_runs.pay_date BETWEEN ? AND ?", startDate, endDate).
+		Order("off_cycle_runs.pay_date ASC").
+		Find(&payslips).Error
+	if err != nil {
+		return nil, err
+	}
+	return payslips, nil
+}
+
+func (r *offCycleRepository) HasFinalSettlement(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.OffCyclePayslip{}).
+		Joins("JOIN off_cycle_runs ON off_cycle_runs.id = off_cycle_payslips.off_cycle_run_id AND off_cycle_runs.deleted_at IS NULL").
+		Where("off_cycle_payslips.user_id = ? AND off_cycle_runs.type = ?", userID, models.OffCycleTypeFinalSettlement).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -15,8 +15,9 @@ type OvertimeRepository interface {
 	GetOvertimeByUserAndDate(userID uint, date time.Time) (*models.Overtime, error)
 	CreateOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error)
 	UpdateOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error)
+	ConfirmProposedOvertime(ctx context.Context, id uint) (bool, error)
 	DeleteOvertime(id uint) error
-	CountOvertimeHours(userID uint, startDate, endDate string) (float64, error)
+	GetSubmittedOvertimes(userID uint, startDate, endDate string) ([]*models.Overtime, error)
 }
 
 type overtimeRepository struct {
@@ -83,6 +84,17 @@ func (r *overtimeRepository) UpdateOvertime(ctx context.Context, overtime *model
 	return overtime, nil
 }
 
+// ConfirmProposedOvertime submits the overtime only if it is still proposed, and reports whether it was.
+func (r *overtimeRepository) ConfirmProposedOvertime(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Overtime{}).
+		Where("id = ? AND status = ?", id, models.OvertimeStatusProposed).
+		Update("status", models.OvertimeStatusSubmitted)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *overtimeRepository) DeleteOvertime(id uint) error {
 	if err := r.db.Delete(&models.Overtime{}, id).Error; err != nil {
 		return err
@@ -90,16 +102,14 @@ func (r *overtimeRepository) DeleteOvertime(id uint) error {
 	return nil
 }
 
-func (r *overtimeRepository) CountOvertimeHours(userID uint, startDate, endDate string) (float64, error) {
-	var totalHours float64
-
-	query := r.db.Model(&models.Overtime{}).
-		Select("SUM(hours)").
-		Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate)
-
-	if err := query.Scan(&totalHours).Error; err != nil {
-		return 0, err
+// GetSubmittedOvertimes returns the overtime counted for pay and caps, leaving out unconfirmed proposals.
+func (r *overtimeRepository) GetSubmittedOvertimes(userID uint, startDate, endDate string) ([]*models.Overtime, error) {
+	var overtimes []*models.Overtime
+	if err := r.db.Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("status <> ?", models.OvertimeStatusProposed).
+		Order("date ASC").
+		Find(&overtimes).Error; err != nil {
+		return nil, err
 	}
-
-	return totalHours, nil
-}
\ No newline at end of file
+	return overtimes, nil
+}
@@ -12,10 +12,14 @@ type PayrollPeriodRepository interface {
 	FindAll(pagination utils.Pagination) ([]*models.PayrollPeriod, int64, error)
 	FindByID(id uint) (*models.PayrollPeriod, error)
 	IsDateLocked(date string) (bool, error)
+	FindLatest() (*models.PayrollPeriod, error)
+	FindPrevious(startDate string) (*models.PayrollPeriod, error)
+	HasOverlappingPeriod(startDate, endDate string, excludeID uint) (bool, error)
 	Create(ctx context.Context, period *models.PayrollPeriod) (*models.PayrollPeriod, error)
 	Update(ctx context.Context, period *models.PayrollPeriod) (*models.PayrollPeriod, error)
 	Delete(id uint) error
 	MarkAsProcessed(id uint) error
+	Reopen(id uint) error
 }
 
 type payrollPeriodRepository struct {
@@ -92,11 +96,14 @@ func (r *payrollPeriodRepository) Update(ctx context.Context, period *models.Pay
 	return &updated, nil
 }
 
+// Delete removes the period together with any payslips it has, releasing their loan installments.
 func (r *payrollPeriodRepository) Delete(id uint) error {
-	if err := r.db.Delete(&models.PayrollPeriod{}, id).Error; err != nil {
-		return err // Other error
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := voidPayslips(tx, id); err != nil {
+			return err
+		}
+		return tx.Delete(&models.PayrollPeriod{}, id).Error
+	})
 }
 
 func (r *payrollPeriodRepository) IsDateLocked(date string) (bool, error) {
@@ -109,6 +116,40 @@ func (r *payrollPeriodRepository) IsDateLocked(date string) (bool, error) {
 	return count > 0, nil
 }
 
+// FindLatest returns the period that ends last, or nil when there are none yet.
+func (r *payrollPeriodRepository) FindLatest() (*models.PayrollPeriod, error) {
+	var periods []*models.PayrollPeriod
+	if err := r.db.Order("end_date DESC").Limit(1).Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, nil
+	}
+	return periods[0], nil
+}
+
+// FindPrevious returns the last period ending before the given date, or nil when there is none.
+func (r *payrollPeriodRepository) FindPrevious(startDate string) (*models.PayrollPeriod, error) {
+	var periods []*models.PayrollPeriod
+	if err := r.db.Where("end_date < ?", startDate).Order("end_date DESC").Limit(1).Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, nil
+	}
+	return periods[0], nil
+}
+
+func (r *payrollPeriodRepository) HasOverlappingPeriod(startDate, endDate string, excludeID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.PayrollPeriod{}).
+		Where("start_date <= ? AND end_date >= ? AND id <> ?", endDate, startDate, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *payrollPeriodRepository) MarkAsProcessed(id uint) error {
 	if err := r.db.Model(&models.PayrollPeriod{}).
 		Where("id = ?", id).
@@ -117,3 +158,16 @@ func (r *payrollPeriodRepository) MarkAsProcessed(id uint) error {
 	}
 	return nil
 }
+
+// Reopen voids the period's payslips and unlocks it in one transaction, so a failure leaves the
+// period processed with its payslips intact.
+func (r *payrollPeriodRepository) Reopen(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := voidPayslips(tx, id); err != nil {
+			return err
+		}
+		return tx.Model(&models.PayrollPeriod{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"is_processed": false, "processed_at": nil}).Error
+	})
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 
 	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"gorm.io/gorm"
 )
 
 type PayslipRepository interface {
-	Create(ctx context.Context, payslip *models.Payslip) error
+	Create(ctx context.Context, payslip *models.Payslip, loansDueBy string) error
 	GetByUserAndPeriod(userID uint, periodID uint) (*models.Payslip, error)
 	GetByID(id uint) (*models.Payslip, error)
 	GetByPeriod(periodID uint) ([]*models.Payslip, error)
 	Exists(userID uint, periodID uint) (bool, error)
+	GetHistoryByUser(userID uint, pagination utils.Pagination) ([]*models.Payslip, int64, error)
+	GetByUserBetween(userID uint, startDate, endDate string) ([]*models.Payslip, error)
 }
 
 type payslipRepository struct {
@@ -25,8 +28,18 @@ func NewPayslipRepository(db *gorm.DB) PayslipRepository {
 	}
 }
 
-func (r *payslipRepository) Create(ctx context.Context, payslip *models.Payslip) error {
-	return r.db.WithContext(ctx).Create(payslip).Error
+// Create saves the payslip and, when it deducts a loan, settles the installments due by
+// loansDueBy in the same transaction, so a payslip never exists without its repayments.
+func (r *payslipRepository) Create(ctx context.Context, payslip *models.Payslip, loansDueBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payslip).Error; err != nil {
+			return err
+		}
+		if payslip.LoanDeduction > 0 {
+			return markInstallmentsPaid(tx, payslip.UserID, loansDueBy, payslip.PayrollPeriodID, payslip.GeneratedAt)
+		}
+		return nil
+	})
 }
 
 func (r *payslipRepository) GetByUserAndPeriod(userID uint, periodID uint) (*models.Payslip, error) {
@@ -65,4 +78,50 @@ func (r *payslipRepository) Exists(userID uint, periodID uint) (bool, error) {
 	return count > 0, nil
 }
 
+// GetHistoryByUser lists the user's payslips with their payroll periods, latest period first.
+func (r *payslipRepository) GetHistoryByUser(userID uint, pagination utils.Pagination) ([]*models.Payslip, int64, error) {
+	var payslips []*models.Payslip
+	var total int64
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	query := r.db.Model(&models.Payslip{}).Where("payslips.user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("PayrollPeriod").
+		Joins("JOIN payroll_periods ON payroll_periods.id = payslips.payroll_period_id AND payroll_periods.deleted_at IS NULL").
+		Order("payroll_periods.end_date DESC").
+		Limit(pagination.Limit).Offset(offset).
+		Find(&payslips).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return payslips, total, nil
+}
+
+// GetByUserBetween lists the user's payslips of the periods ending within the range, earliest first.
+func (r *payslipRepository) GetByUserBetween(userID uint, startDate, endDate string) ([]*models.Payslip, error) {
+	var payslips []*models.Payslip
+	err := r.db.Preload("PayrollPeriod").
+		Joins("JOIN payroll_periods ON payroll_periods.id = payslips.payroll_period_id AND payroll_periods.deleted_at IS NULL").
+		Where("payslips.user_id = ? AND payroll_periods.end_date BETWEEN ? AND ?", userID, startDate, endDate).
+		Order("payroll_periods.end_date ASC").
+		Find(&payslips).Error
+	if err != nil {
+		return nil, err
+	}
+	return payslips, nil
+}
 
+// voidPayslips removes the period's payslips and their email deliveries for good, so the period
+// can be run again, and releases the loan installments they deducted. It takes the transaction
+// reopening or deleting the period.
+func voidPayslips(db *gorm.DB, periodID uint) error {
+	if err := reverseInstallments(db, periodID); err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("payroll_period_id = ?", periodID).Delete(&models.PayslipDelivery{}).Error; err != nil {
+		return err
+	}
+	return db.Unscoped().Where("payroll_period_id = ?", periodID).Delete(&models.Payslip{}).Error
+}
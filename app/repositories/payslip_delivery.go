@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PayslipDeliveryRepository interface {
+	GetDeliveryList(periodID uint, status string, pagination utils.Pagination) ([]*models.PayslipDelivery, int64, error)
+	GetDeliveryByID(id uint) (*models.PayslipDelivery, error)
+	HasDelivery(userID, periodID uint) (bool, error)
+	ClaimDueDeliveries(now, claimUntil time.Time, limit int) ([]*models.PayslipDelivery, error)
+	CreateDelivery(ctx context.Context, delivery *models.PayslipDelivery) (*models.PayslipDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.PayslipDelivery) (*models.PayslipDelivery, error)
+}
+
+type payslipDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewPayslipDeliveryRepository(db *gorm.DB) PayslipDeliveryRepository {
+	return &payslipDeliveryRepository{
+		db: db,
+	}
+}
+
+// GetDeliveryList lists the deliveries of a payroll period, newest first. An empty status lists
+// every status.
+func (r *payslipDeliveryRepository) GetDeliveryList(periodID uint, status string, pagination utils.Pagination) ([]*models.PayslipDelivery, int64, error) {
+	var deliveries []*models.PayslipDelivery
+	var total int64
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	query := r.db.Model(&models.PayslipDelivery{}).Where("payroll_period_id = ?", periodID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Limit(pagination.Limit).Offset(offset).Order("id DESC").Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *payslipDeliveryRepository) GetDeliveryByID(id uint) (*models.PayslipDelivery, error) {
+	var delivery models.PayslipDelivery
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// HasDelivery reports whether the user's payslip of the period has been queued, whatever the
+// outcome.
+func (r *payslipDeliveryRepository) HasDelivery(userID, periodID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.PayslipDelivery{}).
+		Where("user_id = ? AND payroll_period_id = ?", userID, periodID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ClaimDueDeliveries marks the pending deliveries whose next attempt is due as sending, oldest
+// first, and returns them. Rows another instance is claiming are skipped. A claim expires at
+// claimUntil, so a delivery left sending by a crashed instance is picked up again.
+func (r *payslipDeliveryRepository) ClaimDueDeliveries(now, claimUntil time.Time, limit int) ([]*models.PayslipDelivery, error) {
+	var deliveries []*models.PayslipDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+				[]string{models.PayslipDeliveryPending, models.PayslipDeliverySending}, now).
+			Order("id ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			delivery.Status = models.PayslipDeliverySending
+			delivery.NextAttemptAt = &claimUntil
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&models.PayslipDelivery{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": models.PayslipDeliverySending, "next_attempt_at": claimUntil}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *payslipDeliveryRepository) CreateDelivery(ctx context.Context, delivery *models.PayslipDelivery) (*models.PayslipDelivery, error) {
+	if err := r.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (r *payslipDeliveryRepository) UpdateDelivery(ctx context.Context, delivery *models.PayslipDelivery) (*models.PayslipDelivery, error) {
+	if err := r.db.WithContext(ctx).Save(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
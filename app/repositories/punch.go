@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PunchRepository interface {
+	CreatePunch(ctx context.Context, punch *models.Punch) (bool, error)
+	GetPunchesByUserAndDate(userID uint, date string) ([]*models.Punch, error)
+}
+
+type punchRepository struct {
+	db *gorm.DB
+}
+
+func NewPunchRepository(db *gorm.DB) PunchRepository {
+	return &punchRepository{
+		db: db,
+	}
+}
+
+// CreatePunch stores a punch unless the same device already sent it for the same employee
+// and time. It reports whether the punch was new.
+func (r *punchRepository) CreatePunch(ctx context.Context, punch *models.Punch) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "device_id"}, {Name: "employee_code"}, {Name: "punched_at"}},
+			DoNothing: true,
+		}).
+		Create(punch)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *punchRepository) GetPunchesByUserAndDate(userID uint, date string) ([]*models.Punch, error) {
+	var punches []*models.Punch
+	query := r.db.Where("user_id = ? AND attendance_date = ?", userID, date).
+		Where("status = ?", models.PunchStatusProcessed).
+		Order("punched_at ASC")
+	if err := query.Find(&punches).Error; err != nil {
+		return nil, err
+	}
+	return punches, nil
+}
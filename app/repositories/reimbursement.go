@@ -6,17 +6,24 @@ import (
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReimbursementRepository interface {
 	GetReimbursementList(userID uint, pagination utils.Pagination) ([]*models.Reimbursement, int64, error)
 	GetReimbursementByID(id uint) (*models.Reimbursement, error)
-	CreateReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error)
-	UpdateReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error)
+	CreateReimbursement(ctx context.Context, reimbursement *models.Reimbursement, check BudgetCheck) (*models.Reimbursement, error)
+	UpdateReimbursement(ctx context.Context, reimbursement *models.Reimbursement, check BudgetCheck) (*models.Reimbursement, error)
 	DeleteReimbursement(id uint) error
 	SumReimbursement(userID uint, startDate, endDate string) (float64, error)
+	SumCategoryUsage(userID, categoryID uint, startDate, endDate string, excludeID uint) (float64, error)
 }
 
+// BudgetCheck decides whether a claim fits the budget, reading the usage through the repository
+// it is given. Saving a claim runs it in the saving transaction, after locking the claimant's user
+// row, so concurrent claims of one user are checked one at a time against each other's usage.
+type BudgetCheck func(repo ReimbursementRepository) error
+
 type reimbursementRepository struct {
 	db *gorm.DB
 }
@@ -52,20 +59,41 @@ func (r *reimbursementRepository) GetReimbursementByID(id uint) (*models.Reimbur
 	return &reimbursement, nil
 }
 
-func (r *reimbursementRepository) CreateReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error) {
-	if err := r.db.WithContext(ctx).Create(reimbursement).Error; err != nil {
+func (r *reimbursementRepository) CreateReimbursement(ctx context.Context, reimbursement *models.Reimbursement, check BudgetCheck) (*models.Reimbursement, error) {
+	err := r.withinBudget(ctx, reimbursement.UserID, check, func(tx *gorm.DB) error {
+		return tx.Create(reimbursement).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 	return reimbursement, nil
 }
 
-func (r *reimbursementRepository) UpdateReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error) {
-	if err := r.db.WithContext(ctx).Save(reimbursement).Error; err != nil {
+func (r *reimbursementRepository) UpdateReimbursement(ctx context.Context, reimbursement *models.Reimbursement, check BudgetCheck) (*models.Reimbursement, error) {
+	err := r.withinBudget(ctx, reimbursement.UserID, check, func(tx *gorm.DB) error {
+		return tx.Save(reimbursement).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 	return reimbursement, nil
 }
 
+// withinBudget locks the user's row with SELECT ... FOR UPDATE, runs the budget check and saves
+// the claim in one transaction.
+func (r *reimbursementRepository) withinBudget(ctx context.Context, userID uint, check BudgetCheck, save func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if err := check(&reimbursementRepository{db: tx}); err != nil {
+			return err
+		}
+		return save(tx)
+	})
+}
+
 func (r *reimbursementRepository) DeleteReimbursement(id uint) error {
 	if err := r.db.Delete(&models.Reimbursement{}, id).Error; err != nil {
 		return err
@@ -82,4 +110,17 @@ func (r *reimbursementRepository) SumReimbursement(userID uint, startDate, endDa
 		return 0, err
 	}
 	return total, nil
+}
+
+// SumCategoryUsage totals the user's claims of a category in the date range, leaving out the
+// claim excludeID so that an update is not counted against itself.
+func (r *reimbursementRepository) SumCategoryUsage(userID, categoryID uint, startDate, endDate string, excludeID uint) (float64, error) {
+	var total float64
+	if err := r.db.Model(&models.Reimbursement{}).
+		Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ? AND id <> ?", userID, categoryID, startDate, endDate, excludeID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
 }
\ No newline at end of file
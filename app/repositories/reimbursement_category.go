@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"gorm.io/gorm"
+)
+
+type ReimbursementCategoryRepository interface {
+	GetCategoryList(activeOnly bool) ([]*models.ReimbursementCategory, error)
+	GetCategoryByID(id uint) (*models.ReimbursementCategory, error)
+	CreateCategory(ctx context.Context, category *models.ReimbursementCategory) (*models.ReimbursementCategory, error)
+	UpdateCategory(ctx context.Context, category *models.ReimbursementCategory) (*models.ReimbursementCategory, error)
+	GetLimitByID(id uint) (*models.ReimbursementLimit, error)
+	CreateLimit(ctx context.Context, limit *models.ReimbursementLimit) (*models.ReimbursementLimit, error)
+	DeleteLimit(id uint) error
+}
+
+type reimbursementCategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewReimbursementCategoryRepository(db *gorm.DB) ReimbursementCategoryRepository {
+	return &reimbursementCategoryRepository{
+		db: db,
+	}
+}
+
+// GetCategoryList lists the categories by name with their limits.
+func (r *reimbursementCategoryRepository) GetCategoryList(activeOnly bool) ([]*models.ReimbursementCategory, error) {
+	var categories []*models.ReimbursementCategory
+	query := r.db.Preload("Limits")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *reimbursementCategoryRepository) GetCategoryByID(id uint) (*models.ReimbursementCategory, error) {
+	var category models.ReimbursementCategory
+	if err := r.db.Preload("Limits").First(&category, id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *reimbursementCategoryRepository) CreateCategory(ctx context.Context, category *models.ReimbursementCategory) (*models.ReimbursementCategory, error) {
+	if err := r.db.WithContext(ctx).Omit("Limits").Create(category).Error; err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (r *reimbursementCategoryRepository) UpdateCategory(ctx context.Context, category *models.ReimbursementCategory) (*models.ReimbursementCategory, error) {
+	if err := r.db.WithContext(ctx).Omit("Limits").Save(category).Error; err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (r *reimbursementCategoryRepository) GetLimitByID(id uint) (*models.ReimbursementLimit, error) {
+	var limit models.ReimbursementLimit
+	if err := r.db.First(&limit, id).Error; err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+func (r *reimbursementCategoryRepository) CreateLimit(ctx context.Context, limit *models.ReimbursementLimit) (*models.ReimbursementLimit, error) {
+	if err := r.db.WithContext(ctx).Create(limit).Error; err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+func (r *reimbursementCategoryRepository) DeleteLimit(id uint) error {
+	return r.db.Delete(&models.ReimbursementLimit{}, id).Error
+}
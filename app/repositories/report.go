@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"github.com/galiherlangga/go-attendance/app/models"
+	"gorm.io/gorm"
+)
+
+// ReportRepository runs the aggregate queries behind the admin reports.
+type ReportRepository interface {
+	GetAttendanceCounts(startDate, endDate string) ([]*models.EmployeeAttendanceRate, error)
+	GetWeekendShiftCounts(startDate, endDate string) (map[uint]int, error)
+	GetTopOvertimeUsers(startDate, endDate string, limit int) ([]*models.OvertimeUserReport, error)
+	GetPayrollCostByPeriod(startDate, endDate string) ([]*models.PayrollPeriodCost, error)
+	GetReimbursementSpendByMonth(startDate, endDate string) ([]*models.MonthlySpend, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+// GetAttendanceCounts counts the attended and late days of every non-admin user.
+func (r *reportRepository) GetAttendanceCounts(startDate, endDate string) ([]*models.EmployeeAttendanceRate, error) {
+	var rows []*models.EmployeeAttendanceRate
+	if err := r.db.Table("users").
+		Select(`users.id AS user_id, users.name, users.manager_id, managers.name AS manager_name,
+			COUNT(attendances.id) AS attended_days,
+			COUNT(attendances.id) FILTER (WHERE attendances.late_minutes > 0) AS late_days`).
+		Joins("JOIN roles ON roles.id = users.role_id").
+		Joins("LEFT JOIN users managers ON managers.id = users.manager_id").
+		Joins("LEFT JOIN attendances ON attendances.user_id = users.id AND attendances.deleted_at IS NULL AND attendances.date BETWEEN ? AND ?", startDate, endDate).
+		Where("users.deleted_at IS NULL AND roles.name <> ?", models.RoleAdmin).
+		Group("users.id, managers.name").
+		Order("users.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetWeekendShiftCounts counts, per user, the Saturdays and Sundays they were rostered on.
+func (r *reportRepository) GetWeekendShiftCounts(startDate, endDate string) (map[uint]int, error) {
+	var rows []struct {
+		UserID uint
+		Days   int
+	}
+	if err := r.db.Model(&models.ShiftAssignment{}).
+		Select("user_id, COUNT(*) AS days").
+		Where("date BETWEEN ? AND ? AND EXTRACT(ISODOW FROM date) >= 6", startDate, endDate).
+		Group("user_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserID] = row.Days
+	}
+	return counts, nil
+}
+
+func (r *reportRepository) GetTopOvertimeUsers(startDate, endDate string, limit int) ([]*models.OvertimeUserReport, error) {
+	var rows []*models.OvertimeUserReport
+	if err := r.db.Table("overtimes").
+		Select("overtimes.user_id, users.name, SUM(overtimes.hours) AS hours, COUNT(*) AS days").
+		Joins("JOIN users ON users.id = overtimes.user_id").
+		Where("overtimes.deleted_at IS NULL AND overtimes.status <> ? AND overtimes.date BETWEEN ? AND ?", models.OvertimeStatusProposed, startDate, endDate).
+		Group("overtimes.user_id, users.name").
+		Order("hours DESC, users.name ASC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetPayrollCostByPeriod totals the payslips of every payroll period overlapping the date range.
+// Attendance earnings are stored per day, and the payslip lines are split like Payslip.GrossPay
+// and Payslip.Deductions do: positive lines are allowances, negative ones deductions. Processed
+// off-cycle runs count towards the period their pay date falls in.
+func (r *reportRepository) GetPayrollCostByPeriod(startDate, endDate string) ([]*models.PayrollPeriodCost, error) {
+	var rows []*models.PayrollPeriodCost
+	if err := r.db.Table("payslips").
+		Select(`payroll_periods.id AS payroll_period_id, payroll_periods.start_date, payroll_periods.end_date,
+			COUNT(payslips.id) AS employees,
+			SUM(payslips.attendance_days * payslips.attendance_earnings) AS attendance_earnings,
+			SUM(payslips.overtime_hours) AS overtime_hours,
+			SUM(payslips.overtime_earnings) AS overtime_earnings,
+			SUM(payslips.total_reimbursement) AS total_reimbursement,
+			COALESCE(SUM(lines.allowances), 0) AS allowances,
+			SUM(payslips.late_deduction + payslips.undertime_deduction + payslips.loan_deduction + COALESCE(lines.deductions, 0)) AS deductions,
+			SUM(payslips.take_home_pay) AS take_home_pay,
+			(SELECT COALESCE(SUM(off_cycle_payslips.total_earnings), 0) FROM off_cycle_payslips
+				JOIN off_cycle_runs ON off_cycle_runs.id = off_cycle_payslips.off_cycle_run_id
+				WHERE off_cycle_payslips.deleted_at IS NULL AND off_cycle_runs.deleted_at IS NULL AND off_cycle_runs.is_processed = true
+					AND off_cycle_runs.pay_date BETWEEN payroll_periods.start_date AND payroll_periods.end_date) AS off_cycle_earnings`).
+		Joins("JOIN payroll_periods ON payroll_periods.id = payslips.payroll_period_id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT SUM((line.value->>'amount')::numeric) FILTER (WHERE (line.value->>'amount')::numeric > 0) AS allowances,
+				-SUM((line.value->>'amount')::numeric) FILTER (WHERE (line.value->>'amount')::numeric < 0) AS deductions
+			FROM jsonb_array_elements(CASE WHEN jsonb_typeof(payslips.lines) = 'array' THEN payslips.lines ELSE '[]'::jsonb END) AS line(value)
+		) lines ON TRUE`).
+		Where("payslips.deleted_at IS NULL AND payroll_periods.deleted_at IS NULL").
+		Where("payroll_periods.start_date <= ? AND payroll_periods.end_date >= ?", endDate, startDate).
+		Group("payroll_periods.id").
+		Order("payroll_periods.start_date ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *reportRepository) GetReimbursementSpendByMonth(startDate, endDate string) ([]*models.MonthlySpend, error) {
+	var rows []*models.MonthlySpend
+	if err := r.db.Model(&models.Reimbursement{}).
+		Select("TO_CHAR(date, 'YYYY-MM') AS month, COUNT(*) AS claims, SUM(amount) AS amount").
+		Where("date BETWEEN ? AND ?", startDate, endDate).
+		Group("month").
+		Order("month ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShiftRepository interface {
+	GetShiftList(pagination utils.Pagination) ([]*models.Shift, int64, error)
+	GetShiftByID(id uint) (*models.Shift, error)
+	CreateShift(ctx context.Context, shift *models.Shift) (*models.Shift, error)
+	UpdateShift(ctx context.Context, shift *models.Shift) (*models.Shift, error)
+	DeleteShift(id uint) error
+	GetAssignmentList(userID uint, startDate, endDate string) ([]*models.ShiftAssignment, error)
+	GetAssignmentByUserAndDate(userID uint, date string) (*models.ShiftAssignment, error)
+	UpsertAssignment(ctx context.Context, assignment *models.ShiftAssignment) (*models.ShiftAssignment, error)
+	DeleteAssignment(id uint) error
+}
+
+type shiftRepository struct {
+	db *gorm.DB
+}
+
+func NewShiftRepository(db *gorm.DB) ShiftRepository {
+	return &shiftRepository{
+		db: db,
+	}
+}
+
+func (r *shiftRepository) GetShiftList(pagination utils.Pagination) ([]*models.Shift, int64, error) {
+	var shifts []*models.Shift
+	var total int64
+
+	query := r.db.Model(&models.Shift{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Order("start_time ASC").
+		Find(&shifts).Error; err != nil {
+		return nil, 0, err
+	}
+	return shifts, total, nil
+}
+
+func (r *shiftRepository) GetShiftByID(id uint) (*models.Shift, error) {
+	var shift models.Shift
+	if err := r.db.First(&shift, id).Error; err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+func (r *shiftRepository) CreateShift(ctx context.Context, shift *models.Shift) (*models.Shift, error) {
+	if err := r.db.WithContext(ctx).Create(shift).Error; err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+func (r *shiftRepository) UpdateShift(ctx context.Context, shift *models.Shift) (*models.Shift, error) {
+	if err := r.db.WithContext(ctx).Save(shift).Error; err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+func (r *shiftRepository) DeleteShift(id uint) error {
+	if err := r.db.Delete(&models.Shift{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *shiftRepository) GetAssignmentList(userID uint, startDate, endDate string) ([]*models.ShiftAssignment, error) {
+	var assignments []*models.ShiftAssignment
+	if err := r.db.Preload("Shift").
+		Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).
+		Order("date ASC").
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (r *shiftRepository) GetAssignmentByUserAndDate(userID uint, date string) (*models.ShiftAssignment, error) {
+	var assignment models.ShiftAssignment
+	if err := r.db.Preload("Shift").Where("user_id = ? AND date = ?", userID, date).First(&assignment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil // No shift rostered
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *shiftRepository) UpsertAssignment(ctx context.Context, assignment *models.ShiftAssignment) (*models.ShiftAssignment, error) {
+	// Re-assigning a date replaces the previous shift, including soft-deleted rows
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"shift_id", "updated_at", "updated_by", "deleted_at"}),
+	}).Create(assignment).Error; err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (r *shiftRepository) DeleteAssignment(id uint) error {
+	if err := r.db.Delete(&models.ShiftAssignment{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type SiteRepository interface {
+	GetSiteList(pagination utils.Pagination) ([]*models.Site, int64, error)
+	GetActiveSites() ([]*models.Site, error)
+	GetSiteByID(id uint) (*models.Site, error)
+	CreateSite(ctx context.Context, site *models.Site) (*models.Site, error)
+	UpdateSite(ctx context.Context, site *models.Site) (*models.Site, error)
+	DeleteSite(id uint) error
+}
+
+type siteRepository struct {
+	db *gorm.DB
+}
+
+func NewSiteRepository(db *gorm.DB) SiteRepository {
+	return &siteRepository{
+		db: db,
+	}
+}
+
+func (r *siteRepository) GetSiteList(pagination utils.Pagination) ([]*models.Site, int64, error) {
+	var sites []*models.Site
+	var total int64
+
+	query := r.db.Model(&models.Site{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Order("name ASC").
+		Find(&sites).Error; err != nil {
+		return nil, 0, err
+	}
+	return sites, total, nil
+}
+
+func (r *siteRepository) GetActiveSites() ([]*models.Site, error) {
+	var sites []*models.Site
+	if err := r.db.Where("is_active = ?", true).Find(&sites).Error; err != nil {
+		return nil, err
+	}
+	return sites, nil
+}
+
+func (r *siteRepository) GetSiteByID(id uint) (*models.Site, error) {
+	var site models.Site
+	if err := r.db.First(&site, id).Error; err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+func (r *siteRepository) CreateSite(ctx context.Context, site *models.Site) (*models.Site, error) {
+	if err := r.db.WithContext(ctx).Create(site).Error; err != nil {
+		return nil, err
+	}
+	return site, nil
+}
+
+func (r *siteRepository) UpdateSite(ctx context.Context, site *models.Site) (*models.Site, error) {
+	if err := r.db.WithContext(ctx).Save(site).Error; err != nil {
+		return nil, err
+	}
+	return site, nil
+}
+
+func (r *siteRepository) DeleteSite(id uint) error {
+	if err := r.db.Delete(&models.Site{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/galiherlangga/go-attendance/app/models"
 	"gorm.io/gorm"
 )
@@ -8,7 +10,9 @@ import (
 type UserRepository interface {
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id uint) (*models.User, error)
+	FindByEmployeeCode(code string) (*models.User, error)
 	GetAllEmployee(offset int, limit int) ([]*models.User, error)
+	UpdateUser(ctx context.Context, user *models.User) (*models.User, error)
 }
 
 type userRepository struct {
@@ -37,6 +41,14 @@ func (r *userRepository) FindByID(id uint) (*models.User, error) {
 	return user, nil
 }
 
+func (r *userRepository) FindByEmployeeCode(code string) (*models.User, error) {
+	user := &models.User{}
+	if err := r.db.Where("employee_code = ?", code).First(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (r *userRepository) GetAllEmployee(offset int, limit int) ([]*models.User, error) {
 	var users []*models.User
 	employeeRole := &models.Role{}
@@ -52,3 +64,10 @@ func (r *userRepository) GetAllEmployee(offset int, limit int) ([]*models.User,
 
 	return users, nil
 }
+
+func (r *userRepository) UpdateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	if err := r.db.WithContext(ctx).Omit("Role").Save(user).Error; err != nil {
+		return nil, err
+	}
+	return r.FindByID(user.ID)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type AllowanceService interface {
+	GetAssignmentList(userID uint, pagination utils.Pagination) ([]*models.AllowanceAssignment, int64, error)
+	CreateAssignment(ctx context.Context, req *models.AllowanceAssignmentRequest) (*models.AllowanceAssignment, error)
+	UpdateAssignment(ctx context.Context, id uint, req *models.AllowanceAssignmentRequest) (*models.AllowanceAssignment, error)
+	DeleteAssignment(id uint) error
+	GetAdjustmentList(periodID uint, userID uint) ([]*models.PayrollAdjustment, error)
+	CreateAdjustment(ctx context.Context, req *models.PayrollAdjustmentRequest) (*models.PayrollAdjustment, error)
+	DeleteAdjustment(id uint) error
+}
+
+type allowanceService struct {
+	repo       repositories.AllowanceRepository
+	userRepo   repositories.UserRepository
+	periodRepo repositories.PayrollPeriodRepository
+}
+
+func NewAllowanceService(repo repositories.AllowanceRepository, userRepo repositories.UserRepository, periodRepo repositories.PayrollPeriodRepository) AllowanceService {
+	return &allowanceService{
+		repo:       repo,
+		userRepo:   userRepo,
+		periodRepo: periodRepo,
+	}
+}
+
+func (s *allowanceService) GetAssignmentList(userID uint, pagination utils.Pagination) ([]*models.AllowanceAssignment, int64, error) {
+	return s.repo.GetAssignmentList(userID, pagination)
+}
+
+func (s *allowanceService) CreateAssignment(ctx context.Context, req *models.AllowanceAssignmentRequest) (*models.AllowanceAssignment, error) {
+	assignment := &models.AllowanceAssignment{}
+	if err := s.applyAssignmentRequest(assignment, req); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateAssignment(ctx, assignment)
+}
+
+// UpdateAssignment changes an allowance from now on. Payslips already generated keep the
+// amount they were calculated with.
+func (s *allowanceService) UpdateAssignment(ctx context.Context, id uint, req *models.AllowanceAssignmentRequest) (*models.AllowanceAssignment, error) {
+	assignment, err := s.repo.GetAssignmentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyAssignmentRequest(assignment, req); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateAssignment(ctx, assignment)
+}
+
+func (s *allowanceService) DeleteAssignment(id uint) error {
+	if _, err := s.repo.GetAssignmentByID(id); err != nil {
+		return err
+	}
+	return s.repo.DeleteAssignment(id)
+}
+
+func (s *allowanceService) GetAdjustmentList(periodID uint, userID uint) ([]*models.PayrollAdjustment, error) {
+	return s.repo.GetAdjustmentList(periodID, userID)
+}
+
+// CreateAdjustment adds a one-time amount to the user's payslip of a payroll period that has
+// not been processed yet.
+func (s *allowanceService) CreateAdjustment(ctx context.Context, req *models.PayrollAdjustmentRequest) (*models.PayrollAdjustment, error) {
+	if err := s.ensureUserExists(req.UserID); err != nil {
+		return nil, err
+	}
+	period, err := s.periodRepo.FindByID(req.PayrollPeriodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("payroll period %d not found", req.PayrollPeriodID)
+		}
+		return nil, err
+	}
+	if err := ensureUnlocked(s.periodRepo, period.StartDate); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateAdjustment(ctx, &models.PayrollAdjustment{
+		UserID:          req.UserID,
+		PayrollPeriodID: req.PayrollPeriodID,
+		Description:     req.Description,
+		Amount:          req.Amount,
+	})
+}
+
+func (s *allowanceService) DeleteAdjustment(id uint) error {
+	adjustment, err := s.repo.GetAdjustmentByID(id)
+	if err != nil {
+		return err
+	}
+	period, err := s.periodRepo.FindByID(adjustment.PayrollPeriodID)
+	if err != nil {
+		return err
+	}
+	if err := ensureUnlocked(s.periodRepo, period.StartDate); err != nil {
+		return err
+	}
+	return s.repo.DeleteAdjustment(id)
+}
+
+func (s *allowanceService) applyAssignmentRequest(assignment *models.AllowanceAssignment, req *models.AllowanceAssignmentRequest) error {
+	if err := s.ensureUserExists(req.UserID); err != nil {
+		return err
+	}
+	startDate := utils.CivilDate(req.StartDate)
+	var endDate *time.Time
+	if req.EndDate != nil {
+		date := utils.CivilDate(*req.EndDate)
+		if date.Before(startDate) {
+			return errors.New("end_date must not be before start_date")
+		}
+		endDate = &date
+	}
+
+	assignment.UserID = req.UserID
+	assignment.Name = req.Name
+	assignment.Amount = req.Amount
+	assignment.StartDate = startDate
+	assignment.EndDate = endDate
+	return nil
+}
+
+func (s *allowanceService) ensureUserExists(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user == nil) {
+		return fmt.Errorf("user %d not found", userID)
+	}
+	return err
+}
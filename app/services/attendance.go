@@ -4,67 +4,215 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/config"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
 type AttendanceService interface {
-	GetAttendanceList(userID uint, startDate, endDate string) ([]*models.Attendance, error)
-	GetAttendanceByID(id uint) (*models.Attendance, error)
-	CheckIn(ctx context.Context, userID uint) (*models.Attendance, error)
-	CheckOut(ctx context.Context, userID uint) (*models.Attendance, error)
+	GetAttendanceList(userID uint, startDate, endDate string) ([]*models.Attendance, *models.AttendanceTotals, error)
+	GetAttendanceByID(viewerID, id uint) (*models.Attendance, error)
+	CheckIn(ctx context.Context, userID uint, location *models.AttendanceRequest) (*models.Attendance, error)
+	CheckOut(ctx context.Context, userID uint, location *models.AttendanceRequest) (*models.Attendance, error)
+	KioskCheckIn(ctx context.Context, userID uint, site *models.Site) (*models.Attendance, error)
+	ApplyCorrection(ctx context.Context, repo repositories.AttendanceRepository, att *models.Attendance, checkIn, checkOut *time.Time) (*models.Attendance, error)
+	RecordAttendance(ctx context.Context, user *models.User, date time.Time, checkIn, checkOut *time.Time) (*models.Attendance, error)
+	ResolveAttendanceDate(user *models.User, at time.Time) (time.Time, error)
+	ApplyPunches(ctx context.Context, user *models.User, date time.Time, punches []*models.Punch) (*models.Attendance, error)
+	CloseOpenAttendances(ctx context.Context, now time.Time) (int, error)
 }
 
 type attendanceService struct {
-	repo repositories.AttendanceRepository
+	repo                repositories.AttendanceRepository
+	siteRepo            repositories.SiteRepository
+	shiftRepo           repositories.ShiftRepository
+	userRepo            repositories.UserRepository
+	payrollPeriodRepo   repositories.PayrollPeriodRepository
+	overtimeService     OvertimeService
+	notificationService NotificationService
+	cache               *redis.Client
+	config              config.AttendanceConfig
 }
 
-func NewAttendanceService(repo repositories.AttendanceRepository) AttendanceService {
+func NewAttendanceService(
+	repo repositories.AttendanceRepository,
+	siteRepo repositories.SiteRepository,
+	shiftRepo repositories.ShiftRepository,
+	userRepo repositories.UserRepository,
+	payrollPeriodRepo repositories.PayrollPeriodRepository,
+	overtimeService OvertimeService,
+	notificationService NotificationService,
+	cache *redis.Client,
+	cfg config.AttendanceConfig) AttendanceService {
 	return &attendanceService{
-		repo: repo,
+		repo:                repo,
+		siteRepo:            siteRepo,
+		shiftRepo:           shiftRepo,
+		userRepo:            userRepo,
+		payrollPeriodRepo:   payrollPeriodRepo,
+		overtimeService:     overtimeService,
+		notificationService: notificationService,
+		cache:               cache,
+		config:              cfg,
 	}
 }
 
-func (s *attendanceService) GetAttendanceList(userID uint, startDate, endDate string) ([]*models.Attendance, error) {
-	return s.repo.GetAttendanceList(userID, startDate, endDate)
+func (s *attendanceService) GetAttendanceList(userID uint, startDate, endDate string) ([]*models.Attendance, *models.AttendanceTotals, error) {
+	attendances, err := s.repo.GetAttendanceList(userID, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totals := &models.AttendanceTotals{}
+	for _, att := range attendances {
+		s.applyTimeMetrics(att, att.Shift)
+		totals.Days++
+		totals.Sessions += len(att.Sessions)
+		totals.WorkedHours += att.WorkedHours
+		totals.LateMinutes += att.LateMinutes
+		totals.EarlyLeaveMinutes += att.EarlyLeaveMinutes
+		totals.UndertimeMinutes += att.UndertimeMinutes
+		if att.LateMinutes > 0 {
+			totals.LateDays++
+		}
+	}
+	totals.WorkedHours = math.Round(totals.WorkedHours*100) / 100
+
+	return attendances, totals, nil
 }
 
-func (s *attendanceService) GetAttendanceByID(id uint) (*models.Attendance, error) {
-	return s.repo.GetAttendanceByID(id)
+func (s *attendanceService) GetAttendanceByID(viewerID, id uint) (*models.Attendance, error) {
+	att, err := s.repo.GetAttendanceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(s.userRepo, viewerID, att.UserID); err != nil {
+		return nil, err
+	}
+	s.applyTimeMetrics(att, att.Shift)
+	return att, nil
 }
 
-func (s *attendanceService) CheckIn(ctx context.Context, userID uint) (*models.Attendance, error) {
-	today := time.Now().Truncate(24 * time.Hour)
-	if utils.IsWeekend(today) {
+func (s *attendanceService) CheckIn(ctx context.Context, userID uint, location *models.AttendanceRequest) (*models.Attendance, error) {
+	site, outside, err := s.resolveGeofence(location)
+	if err != nil {
+		return nil, err
+	}
+	session := models.AttendanceSession{
+		IsOutsideGeofence: outside,
+	}
+	if location != nil {
+		session.CheckInLatitude = location.Latitude
+		session.CheckInLongitude = location.Longitude
+		session.CheckInAccuracy = location.Accuracy
+	}
+	if site != nil {
+		session.SiteID = &site.ID
+	}
+	return s.startSession(ctx, userID, session, site)
+}
+
+// KioskCheckIn checks a user in at the site of the kiosk whose QR token they scanned.
+// Scanning the kiosk proves presence at the site, so no geofence check is needed.
+func (s *attendanceService) KioskCheckIn(ctx context.Context, userID uint, site *models.Site) (*models.Attendance, error) {
+	return s.startSession(ctx, userID, models.AttendanceSession{SiteID: &site.ID}, site)
+}
+
+// startSession opens a new session now, creating the attendance on the first check-in of the day.
+func (s *attendanceService) startSession(ctx context.Context, userID uint, session models.AttendanceSession, site *models.Site) (*models.Attendance, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	loc := userLocation(user, site)
+	today, assignment, err := s.resolveShiftDate(userID, now, false, loc)
+	if err != nil {
+		return nil, err
+	}
+	if assignment == nil && utils.IsWeekend(today) {
 		return nil, errors.New("cannot submit attendance on weekends")
 	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, today); err != nil {
+		return nil, err
+	}
 
 	attDate := today.Format("2006-01-02")
 	att, err := s.repo.GetAttendanceByUserAndDate(userID, attDate)
-	fmt.Println(att)
-	now := time.Now()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	session.CheckIn = &now
+	outside := session.IsOutsideGeofence
 
+	// First check-in of the day creates the attendance together with its first session
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		att = &models.Attendance{
-			UserID:  userID,
-			Date:    today,
-			CheckIn: &now,
+			UserID:            userID,
+			Date:              today,
+			CheckIn:           &now,
+			CheckInLatitude:   session.CheckInLatitude,
+			CheckInLongitude:  session.CheckInLongitude,
+			CheckInAccuracy:   session.CheckInAccuracy,
+			SiteID:            session.SiteID,
+			IsOutsideGeofence: outside,
+			Timezone:          loc.String(),
+			Sessions:          []models.AttendanceSession{session},
 		}
-		return s.repo.CreateAttendance(ctx, att)
+		var shift *models.Shift
+		if assignment != nil {
+			att.ShiftID = &assignment.ShiftID
+			shift = &assignment.Shift
+		}
+		s.applyTimeMetrics(att, shift)
+		return s.createAttendance(ctx, att)
+	}
+
+	if att.OpenSession() != nil {
+		return nil, errors.New("already checked in, check out before starting a new session")
 	}
 
-	return nil, errors.New("attendance already exists for today")
+	session.AttendanceID = att.ID
+	if _, err := s.repo.CreateSession(ctx, &session); err != nil {
+		return nil, err
+	}
+
+	// The day is open again until the new session is checked out
+	att.CheckOut = nil
+	att.IsOutsideGeofence = att.IsOutsideGeofence || outside
+	att.Sessions = append(att.Sessions, session)
+	return s.repo.UpdateAttendance(ctx, att)
 }
 
-func (s *attendanceService) CheckOut(ctx context.Context, userID uint) (*models.Attendance, error) {
-	today := time.Now().Truncate(24 * time.Hour)
-	if utils.IsWeekend(today) {
+func (s *attendanceService) CheckOut(ctx context.Context, userID uint, location *models.AttendanceRequest) (*models.Attendance, error) {
+	site, outside, err := s.resolveGeofence(location)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	today, assignment, err := s.resolveShiftDate(userID, now, true, userLocation(user, site))
+	if err != nil {
+		return nil, err
+	}
+	if assignment == nil && utils.IsWeekend(today) {
 		return nil, errors.New("cannot submit attendance on weekends")
 	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, today); err != nil {
+		return nil, err
+	}
 
 	attDate := today.Format("2006-01-02")
 	att, err := s.repo.GetAttendanceByUserAndDate(userID, attDate)
@@ -75,12 +223,537 @@ func (s *attendanceService) CheckOut(ctx context.Context, userID uint) (*models.
 	if att.CheckIn == nil {
 		return nil, errors.New("check-in must be done before check-out")
 	}
-	
-	if att.CheckOut != nil {
-		return nil, errors.New("check-out already done for today")
+
+	session := att.OpenSession()
+	if session == nil {
+		return nil, errors.New("no open session, check in before checking out")
+	}
+
+	session.CheckOut = &now
+	session.IsOutsideGeofence = session.IsOutsideGeofence || outside
+	if location != nil {
+		session.CheckOutLatitude = location.Latitude
+		session.CheckOutLongitude = location.Longitude
+		session.CheckOutAccuracy = location.Accuracy
+	}
+	if _, err := s.repo.UpdateSession(ctx, session); err != nil {
+		return nil, err
 	}
 
-	now := time.Now()
 	att.CheckOut = &now
-	return s.repo.UpdateAttendance(ctx, att)
+	att.IsOutsideGeofence = att.IsOutsideGeofence || outside
+	att.CheckOutLatitude = session.CheckOutLatitude
+	att.CheckOutLongitude = session.CheckOutLongitude
+	att.CheckOutAccuracy = session.CheckOutAccuracy
+	shift := att.Shift
+	if assignment != nil {
+		shift = &assignment.Shift
+	}
+	s.applyTimeMetrics(att, shift)
+	updated, err := s.repo.UpdateAttendance(ctx, att)
+	if err != nil {
+		return nil, err
+	}
+
+	// Time worked beyond the schedule becomes an overtime proposal; failing to
+	// record it must not undo the check-out itself
+	if hours := updated.OvertimeMinutes / 60; hours > 0 {
+		if _, err := s.overtimeService.DeriveOvertime(ctx, updated, hours); err != nil {
+			log.Println("failed to derive overtime from attendance:", err)
+		}
+	}
+	return updated, nil
+}
+
+// resolveShiftDate finds the attendance date the employee is working on at the given time.
+// An overnight shift rostered yesterday still owns check-ins until it ends, and check-outs
+// until the configured late check-out window has passed. Without a rostered shift the
+// attendance belongs to today.
+func (s *attendanceService) resolveShiftDate(userID uint, now time.Time, checkOut bool, loc *time.Location) (time.Time, *models.ShiftAssignment, error) {
+	today := utils.DateIn(now, loc)
+	yesterday := today.AddDate(0, 0, -1)
+
+	previous, err := s.shiftRepo.GetAssignmentByUserAndDate(userID, yesterday.Format("2006-01-02"))
+	if err != nil {
+		return today, nil, err
+	}
+	if previous != nil && previous.Shift.CrossesMidnight {
+		_, end, err := previous.Shift.Window(yesterday, loc)
+		if err != nil {
+			return today, nil, err
+		}
+		if checkOut {
+			end = end.Add(s.config.ShiftLateCheckOut)
+		}
+		if now.Before(end) {
+			return yesterday, previous, nil
+		}
+	}
+
+	current, err := s.shiftRepo.GetAssignmentByUserAndDate(userID, today.Format("2006-01-02"))
+	if err != nil {
+		return today, nil, err
+	}
+	if current != nil && !checkOut {
+		start, _, err := current.Shift.Window(today, loc)
+		if err != nil {
+			return today, nil, err
+		}
+		if now.Before(start.Add(-s.config.ShiftEarlyCheckIn)) {
+			return today, nil, fmt.Errorf("check-in for %s opens at %s", current.Shift.Name, start.Add(-s.config.ShiftEarlyCheckIn).Format("15:04"))
+		}
+	}
+	return today, current, nil
+}
+
+// applyTimeMetrics computes worked hours, lateness, early leave and undertime of an
+// attendance against its shift, or the configured default schedule when none is rostered.
+// Arriving within the grace period is not late; arriving after it counts from shift start.
+// On a rest day, a weekend without a rostered shift, all the time worked is overtime.
+func (s *attendanceService) applyTimeMetrics(att *models.Attendance, shift *models.Shift) {
+	att.WorkedHours = calculateWorkedHours(att)
+	att.LateMinutes = 0
+	att.EarlyLeaveMinutes = 0
+	att.UndertimeMinutes = 0
+	att.OvertimeMinutes = 0
+
+	if shift == nil && utils.IsWeekend(att.Date.UTC()) {
+		if att.CheckOut != nil {
+			att.OvertimeMinutes = int(math.Round(att.WorkedHours * 60))
+		}
+		return
+	}
+
+	shift = s.scheduledShift(shift)
+	start, end, err := shift.Window(att.Date.UTC(), attendanceLocation(att))
+	if err != nil {
+		return
+	}
+
+	grace := time.Duration(shift.GracePeriodMinutes) * time.Minute
+	if att.CheckIn != nil && att.CheckIn.After(start.Add(grace)) {
+		att.LateMinutes = int(att.CheckIn.Sub(start).Minutes())
+	}
+	// Early leave and undertime are only known once the day is checked out
+	if att.CheckOut == nil {
+		return
+	}
+	if att.CheckOut.Before(end) {
+		att.EarlyLeaveMinutes = int(end.Sub(*att.CheckOut).Minutes())
+	}
+	scheduled := end.Sub(start).Hours()
+	if att.WorkedHours < scheduled {
+		att.UndertimeMinutes = int(math.Round((scheduled - att.WorkedHours) * 60))
+	} else {
+		att.OvertimeMinutes = int(math.Round((att.WorkedHours - scheduled) * 60))
+	}
+}
+
+// ApplyCorrection overwrites the first check-in and/or the last check-out of an attendance,
+// closing the open session when a check-out is supplied, and recomputes the day metrics. It
+// writes through repo, so callers can apply the correction inside their own transaction.
+func (s *attendanceService) ApplyCorrection(ctx context.Context, repo repositories.AttendanceRepository, att *models.Attendance, checkIn, checkOut *time.Time) (*models.Attendance, error) {
+	if err := ensureUnlocked(s.payrollPeriodRepo, att.Date); err != nil {
+		return nil, err
+	}
+	// Validate the times the day ends up with, so a later check-in cannot pass an existing check-out
+	finalCheckIn, finalCheckOut := att.CheckIn, att.CheckOut
+	if checkIn != nil {
+		finalCheckIn = checkIn
+	}
+	if checkOut != nil {
+		finalCheckOut = checkOut
+	}
+	if finalCheckIn != nil && finalCheckOut != nil && !finalCheckOut.After(*finalCheckIn) {
+		return nil, errors.New("check-out must be after check-in")
+	}
+
+	changed := map[int]bool{}
+	if checkIn != nil {
+		att.CheckIn = checkIn
+		if len(att.Sessions) > 0 {
+			att.Sessions[0].CheckIn = checkIn
+			changed[0] = true
+		}
+	}
+	if checkOut != nil {
+		att.CheckOut = checkOut
+		for i := range att.Sessions {
+			if att.Sessions[i].CheckOut == nil || i == len(att.Sessions)-1 {
+				att.Sessions[i].CheckOut = checkOut
+				changed[i] = true
+				break
+			}
+		}
+	}
+
+	for i := range changed {
+		session := &att.Sessions[i]
+		if session.CheckIn != nil && session.CheckOut != nil && session.CheckOut.Before(*session.CheckIn) {
+			return nil, errors.New("correction would end a session before it starts")
+		}
+	}
+	for i := range changed {
+		if _, err := repo.UpdateSession(utils.WithFreshRequestID(ctx), &att.Sessions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if att.CheckOut != nil {
+		att.CloseOutStatus = models.CloseOutCorrected
+	}
+	s.applyTimeMetrics(att, att.Shift)
+	updated, err := repo.UpdateAttendance(ctx, att)
+	if err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportAttendance)
+	return updated, nil
+}
+
+// RecordAttendance creates the attendance of a user on a date with a single session, or
+// corrects the existing one. Used for entries made by admins rather than by the employee.
+func (s *attendanceService) RecordAttendance(ctx context.Context, user *models.User, date time.Time, checkIn, checkOut *time.Time) (*models.Attendance, error) {
+	userID := user.ID
+	if err := ensureUnlocked(s.payrollPeriodRepo, date); err != nil {
+		return nil, err
+	}
+	attDate := date.Format("2006-01-02")
+	att, err := s.repo.GetAttendanceByUserAndDate(userID, attDate)
+	if err == nil {
+		return s.ApplyCorrection(ctx, s.repo, att, checkIn, checkOut)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if checkIn == nil {
+		return nil, errors.New("check-in is required for a new attendance")
+	}
+	if checkOut != nil && !checkOut.After(*checkIn) {
+		return nil, errors.New("check-out must be after check-in")
+	}
+	assignment, err := s.shiftRepo.GetAssignmentByUserAndDate(userID, attDate)
+	if err != nil {
+		return nil, err
+	}
+
+	att = &models.Attendance{
+		UserID:   userID,
+		Date:     date,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Timezone: userLocation(user, nil).String(),
+		Sessions: []models.AttendanceSession{{CheckIn: checkIn, CheckOut: checkOut}},
+	}
+	var shift *models.Shift
+	if assignment != nil {
+		att.ShiftID = &assignment.ShiftID
+		shift = &assignment.Shift
+	}
+	s.applyTimeMetrics(att, shift)
+	return s.createAttendance(ctx, att)
+}
+
+// ResolveAttendanceDate returns the attendance date a clock event at the given time belongs to,
+// so punches during an overnight shift land on the day the shift started.
+func (s *attendanceService) ResolveAttendanceDate(user *models.User, at time.Time) (time.Time, error) {
+	date, _, err := s.resolveShiftDate(user.ID, at, true, userLocation(user, nil))
+	return date, err
+}
+
+// ApplyPunches rebuilds the terminal sessions of an attendance day from all of its punches, so
+// punches that arrive late or out of order still end up paired correctly. Sessions recorded
+// through the app are kept. The day is rewritten in one transaction, so a failure leaves its
+// earlier sessions in place.
+func (s *attendanceService) ApplyPunches(ctx context.Context, user *models.User, date time.Time, punches []*models.Punch) (*models.Attendance, error) {
+	userID := user.ID
+	times := make([]time.Time, 0, len(punches))
+	deviceAt := map[time.Time]uint{}
+	for _, punch := range punches {
+		times = append(times, punch.PunchedAt)
+		deviceAt[punch.PunchedAt] = punch.DeviceID
+	}
+	pairs := utils.PairPunches(times, s.config.PunchDebounce)
+	if len(pairs) == 0 {
+		return nil, errors.New("no punches to apply")
+	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, date); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.AttendanceSession, 0, len(pairs))
+	for _, pair := range pairs {
+		checkIn := pair.In
+		deviceID := deviceAt[pair.In]
+		sessions = append(sessions, models.AttendanceSession{CheckIn: &checkIn, CheckOut: pair.Out, DeviceID: &deviceID})
+	}
+
+	attDate := date.Format("2006-01-02")
+	att, err := s.repo.GetAttendanceByUserAndDate(userID, attDate)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		assignment, err := s.shiftRepo.GetAssignmentByUserAndDate(userID, attDate)
+		if err != nil {
+			return nil, err
+		}
+		att = &models.Attendance{UserID: userID, Date: date, Timezone: userLocation(user, nil).String(), Sessions: sessions}
+		var shift *models.Shift
+		if assignment != nil {
+			att.ShiftID = &assignment.ShiftID
+			shift = &assignment.Shift
+		}
+		summarizeSessions(att)
+		s.applyTimeMetrics(att, shift)
+		// Sessions are inserted one by one below, each with its own request_id
+		att.Sessions = nil
+		err = s.repo.Transaction(func(repo repositories.AttendanceRepository) error {
+			if _, err := repo.CreateAttendance(ctx, att); err != nil {
+				return err
+			}
+			return createSessions(ctx, repo, att.ID, sessions)
+		})
+		if err != nil {
+			return nil, err
+		}
+		att.Sessions = sessions
+		invalidateReports(ctx, s.cache, reportAttendance)
+		return att, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]models.AttendanceSession, 0, len(att.Sessions))
+	for _, session := range att.Sessions {
+		if session.DeviceID == nil {
+			kept = append(kept, session)
+		}
+	}
+	err = s.repo.Transaction(func(repo repositories.AttendanceRepository) error {
+		if err := repo.DeleteDeviceSessions(att.ID); err != nil {
+			return err
+		}
+		if err := createSessions(ctx, repo, att.ID, sessions); err != nil {
+			return err
+		}
+		att.Sessions = append(kept, sessions...)
+		sort.Slice(att.Sessions, func(i, j int) bool { return att.Sessions[i].CheckIn.Before(*att.Sessions[j].CheckIn) })
+
+		summarizeSessions(att)
+		// A late punch may complete a day the close-out job already flagged
+		if att.CheckOut != nil && att.CloseOutStatus == models.CloseOutIncomplete {
+			att.CloseOutStatus = ""
+		}
+		s.applyTimeMetrics(att, att.Shift)
+		_, err := repo.UpdateAttendance(ctx, att)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportAttendance)
+	return att, nil
+}
+
+// CloseOpenAttendances handles attendances from previous days that were never checked out,
+// according to the configured close-out policy. Overnight shifts are left alone until their
+// late check-out window has passed. It returns the number of attendances handled.
+func (s *attendanceService) CloseOpenAttendances(ctx context.Context, now time.Time) (int, error) {
+	today := utils.DateIn(now, utils.CompanyLocation())
+	attendances, err := s.repo.GetOpenAttendances(today.Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for _, att := range attendances {
+		_, end, err := s.scheduledShift(att.Shift).Window(att.Date.UTC(), attendanceLocation(att))
+		if err != nil {
+			log.Printf("failed to resolve shift for attendance %d: %v\n", att.ID, err)
+			continue
+		}
+		if now.Before(end.Add(s.config.ShiftLateCheckOut)) {
+			continue
+		}
+
+		if err := s.closeOut(utils.WithFreshRequestID(ctx), att, end); err != nil {
+			if errors.Is(err, ErrPeriodLocked) {
+				continue // Payroll already paid this day out as it was
+			}
+			log.Printf("failed to close out attendance %d: %v\n", att.ID, err)
+			continue
+		}
+		handled++
+	}
+	return handled, nil
+}
+
+func (s *attendanceService) closeOut(ctx context.Context, att *models.Attendance, shiftEnd time.Time) error {
+	if err := ensureUnlocked(s.payrollPeriodRepo, att.Date); err != nil {
+		return err
+	}
+	switch s.config.CloseOutPolicy {
+	case config.CloseOutPolicyAutoClose:
+		closeAt := shiftEnd
+		if session := att.OpenSession(); session != nil {
+			if session.CheckIn != nil && closeAt.Before(*session.CheckIn) {
+				closeAt = *session.CheckIn
+			}
+			session.CheckOut = &closeAt
+			if _, err := s.repo.UpdateSession(ctx, session); err != nil {
+				return err
+			}
+		}
+		att.CheckOut = &closeAt
+		att.CloseOutStatus = models.CloseOutAutoClosed
+		s.applyTimeMetrics(att, att.Shift)
+	case config.CloseOutPolicyNotify:
+		att.CloseOutStatus = models.CloseOutIncomplete
+		_, err := s.notificationService.Notify(ctx, att.UserID,
+			"Missing check-out",
+			fmt.Sprintf("You did not check out on %s. Please submit an attendance correction with your check-out time.", att.Date.Format("2006-01-02")))
+		if err != nil {
+			return err
+		}
+	default:
+		att.CloseOutStatus = models.CloseOutIncomplete
+	}
+
+	_, err := s.repo.UpdateAttendance(ctx, att)
+	return err
+}
+
+// userLocation returns the timezone a user's attendance dates are bucketed in: the user's own,
+// then that of the site they clock in at, then the company timezone. Callers pass the user
+// they already loaded, so batches of punches or imported rows don't re-read it every time.
+func userLocation(user *models.User, site *models.Site) *time.Location {
+	var userTimezone, siteTimezone *string
+	if user != nil {
+		userTimezone = user.Timezone
+	}
+	if site != nil {
+		siteTimezone = site.Timezone
+	}
+	return utils.LocationOrDefault(userTimezone, siteTimezone)
+}
+
+// attendanceLocation returns the timezone an attendance was recorded in. Attendances
+// recorded before timezones were tracked use the company timezone.
+func attendanceLocation(att *models.Attendance) *time.Location {
+	return utils.LocationOrDefault(&att.Timezone)
+}
+
+// createAttendance stores a new attendance day, which changes the attendance reports.
+func (s *attendanceService) createAttendance(ctx context.Context, att *models.Attendance) (*models.Attendance, error) {
+	created, err := s.repo.CreateAttendance(ctx, att)
+	if err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportAttendance)
+	return created, nil
+}
+
+// updateAttendanceTimes saves an attendance whose check-in may have moved, changing its lateness in the reports.
+func (s *attendanceService) updateAttendanceTimes(ctx context.Context, att *models.Attendance) (*models.Attendance, error) {
+	updated, err := s.repo.UpdateAttendance(ctx, att)
+	if err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportAttendance)
+	return updated, nil
+}
+
+// scheduledShift falls back to the configured default schedule when no shift is rostered.
+func (s *attendanceService) scheduledShift(shift *models.Shift) *models.Shift {
+	if shift != nil {
+		return shift
+	}
+	return &models.Shift{
+		StartTime:          s.config.DefaultShiftStart,
+		EndTime:            s.config.DefaultShiftEnd,
+		GracePeriodMinutes: s.config.DefaultShiftGracePeriod,
+	}
+}
+
+func createSessions(ctx context.Context, repo repositories.AttendanceRepository, attendanceID uint, sessions []models.AttendanceSession) error {
+	for i := range sessions {
+		sessionCtx := utils.WithFreshRequestID(ctx)
+		sessions[i].AttendanceID = attendanceID
+		if _, err := repo.CreateSession(sessionCtx, &sessions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summarizeSessions sets the day's check-in to the first session and its check-out to the
+// last one, leaving the day open while any session is.
+func summarizeSessions(att *models.Attendance) {
+	att.CheckIn, att.CheckOut = nil, nil
+	for _, session := range att.Sessions {
+		if att.CheckIn == nil || session.CheckIn.Before(*att.CheckIn) {
+			att.CheckIn = session.CheckIn
+		}
+	}
+	for _, session := range att.Sessions {
+		if session.CheckOut == nil {
+			att.CheckOut = nil
+			return
+		}
+		if att.CheckOut == nil || session.CheckOut.After(*att.CheckOut) {
+			att.CheckOut = session.CheckOut
+		}
+	}
+}
+
+// calculateWorkedHours sums the closed sessions of an attendance day.
+// Attendances recorded before sessions existed fall back to check-in/check-out.
+func calculateWorkedHours(att *models.Attendance) float64 {
+	var worked time.Duration
+	if len(att.Sessions) == 0 {
+		if att.CheckIn != nil && att.CheckOut != nil {
+			worked = att.CheckOut.Sub(*att.CheckIn)
+		}
+	}
+	for _, session := range att.Sessions {
+		if session.CheckIn != nil && session.CheckOut != nil {
+			worked += session.CheckOut.Sub(*session.CheckIn)
+		}
+	}
+	return math.Round(worked.Hours()*100) / 100
+}
+
+// resolveGeofence matches the submitted location against the active sites.
+// With no sites configured geofencing is disabled. Otherwise a location outside
+// every site is either rejected or flagged, depending on the configured mode.
+func (s *attendanceService) resolveGeofence(location *models.AttendanceRequest) (*models.Site, bool, error) {
+	sites, err := s.siteRepo.GetActiveSites()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(sites) == 0 {
+		return nil, false, nil
+	}
+
+	var reason error
+	var site *models.Site
+	switch {
+	case location == nil || location.Latitude == nil || location.Longitude == nil:
+		reason = errors.New("location is required for attendance")
+	case location.Accuracy != nil && *location.Accuracy > s.config.GeofenceMaxAccuracy:
+		reason = fmt.Errorf("location accuracy must be within %.0f meters", s.config.GeofenceMaxAccuracy)
+	default:
+		site = matchSite(sites, *location.Latitude, *location.Longitude)
+		if site == nil {
+			reason = errors.New("location is outside of any office site")
+		}
+	}
+
+	if reason == nil {
+		return site, false, nil
+	}
+	if s.config.GeofenceMode == config.GeofenceModeFlag {
+		return nil, true, nil
+	}
+	return nil, false, reason
 }
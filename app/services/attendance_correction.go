@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+)
+
+type AttendanceCorrectionService interface {
+	GetCorrectionList(filter models.CorrectionFilter, pagination utils.Pagination) ([]*models.AttendanceCorrection, int64, error)
+	GetReviewableCorrectionList(reviewerID uint, status string, pagination utils.Pagination) ([]*models.AttendanceCorrection, int64, error)
+	GetCorrectionByID(id uint) (*models.AttendanceCorrection, error)
+	RequestCorrection(ctx context.Context, att *models.Attendance, req *models.AttendanceCorrectionRequest) (*models.AttendanceCorrection, error)
+	ApproveCorrection(ctx context.Context, correction *models.AttendanceCorrection, reviewerID uint, note *string) (*models.AttendanceCorrection, error)
+	RejectCorrection(ctx context.Context, correction *models.AttendanceCorrection, reviewerID uint, note *string) (*models.AttendanceCorrection, error)
+}
+
+type attendanceCorrectionService struct {
+	repo                repositories.AttendanceCorrectionRepository
+	attendanceRepo      repositories.AttendanceRepository
+	userRepo            repositories.UserRepository
+	payrollPeriodRepo   repositories.PayrollPeriodRepository
+	attendanceService   AttendanceService
+	notificationService NotificationService
+}
+
+func NewAttendanceCorrectionService(
+	repo repositories.AttendanceCorrectionRepository,
+	attendanceRepo repositories.AttendanceRepository,
+	userRepo repositories.UserRepository,
+	payrollPeriodRepo repositories.PayrollPeriodRepository,
+	attendanceService AttendanceService,
+	notificationService NotificationService) AttendanceCorrectionService {
+	return &attendanceCorrectionService{
+		repo:                repo,
+		attendanceRepo:      attendanceRepo,
+		userRepo:            userRepo,
+		payrollPeriodRepo:   payrollPeriodRepo,
+		attendanceService:   attendanceService,
+		notificationService: notificationService,
+	}
+}
+
+func (s *attendanceCorrectionService) GetCorrectionList(filter models.CorrectionFilter, pagination utils.Pagination) ([]*models.AttendanceCorrection, int64, error) {
+	return s.repo.GetCorrectionList(filter, pagination)
+}
+
+// GetReviewableCorrectionList lists every correction for admins, and only the
+// corrections of their direct reports for managers.
+func (s *attendanceCorrectionService) GetReviewableCorrectionList(reviewerID uint, status string, pagination utils.Pagination) ([]*models.AttendanceCorrection, int64, error) {
+	reviewer, err := s.userRepo.FindByID(reviewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter := models.CorrectionFilter{Status: status}
+	switch reviewer.Role.Name {
+	case models.RoleAdmin:
+	case models.RoleManager:
+		filter.ManagerID = reviewer.ID
+	default:
+		return nil, 0, ErrForbidden
+	}
+	return s.repo.GetCorrectionList(filter, pagination)
+}
+
+func (s *attendanceCorrectionService) GetCorrectionByID(id uint) (*models.AttendanceCorrection, error) {
+	return s.repo.GetCorrectionByID(id)
+}
+
+func (s *attendanceCorrectionService) RequestCorrection(ctx context.Context, att *models.Attendance, req *models.AttendanceCorrectionRequest) (*models.AttendanceCorrection, error) {
+	if req.CheckIn == nil && req.CheckOut == nil {
+		return nil, errors.New("either check_in or check_out must be provided")
+	}
+
+	// Validate the times the attendance would end up with
+	checkIn, checkOut := att.CheckIn, att.CheckOut
+	if req.CheckIn != nil {
+		checkIn = req.CheckIn
+	}
+	if req.CheckOut != nil {
+		checkOut = req.CheckOut
+	}
+	if checkIn != nil && checkOut != nil && !checkOut.After(*checkIn) {
+		return nil, errors.New("check-out must be after check-in")
+	}
+	now := time.Now()
+	if (req.CheckIn != nil && req.CheckIn.After(now)) || (req.CheckOut != nil && req.CheckOut.After(now)) {
+		return nil, errors.New("corrected time cannot be in the future")
+	}
+
+	if err := ensureUnlocked(s.payrollPeriodRepo, att.Date); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.HasPendingCorrection(att.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("a correction for this attendance is already pending")
+	}
+
+	return s.repo.CreateCorrection(ctx, &models.AttendanceCorrection{
+		AttendanceID: att.ID,
+		UserID:       att.UserID,
+		CheckIn:      req.CheckIn,
+		CheckOut:     req.CheckOut,
+		Reason:       req.Reason,
+		Status:       models.CorrectionStatusPending,
+	})
+}
+
+func (s *attendanceCorrectionService) ApproveCorrection(ctx context.Context, correction *models.AttendanceCorrection, reviewerID uint, note *string) (*models.AttendanceCorrection, error) {
+	if correction.Status != models.CorrectionStatusPending {
+		return nil, errors.New("correction has already been reviewed")
+	}
+	if err := s.checkReviewer(reviewerID, correction.UserID); err != nil {
+		return nil, err
+	}
+
+	att, err := s.attendanceRepo.GetAttendanceByID(correction.AttendanceID)
+	if err != nil {
+		return nil, err
+	}
+	// The period may have been processed while the correction was pending
+	if err := ensureUnlocked(s.payrollPeriodRepo, att.Date); err != nil {
+		return nil, err
+	}
+	return s.review(ctx, correction, models.CorrectionStatusApproved, reviewerID, note, func(repo repositories.AttendanceRepository) error {
+		_, err := s.attendanceService.ApplyCorrection(ctx, repo, att, correction.CheckIn, correction.CheckOut)
+		return err
+	})
+}
+
+func (s *attendanceCorrectionService) RejectCorrection(ctx context.Context, correction *models.AttendanceCorrection, reviewerID uint, note *string) (*models.AttendanceCorrection, error) {
+	if correction.Status != models.CorrectionStatusPending {
+		return nil, errors.New("correction has already been reviewed")
+	}
+	if err := s.checkReviewer(reviewerID, correction.UserID); err != nil {
+		return nil, err
+	}
+	return s.review(ctx, correction, models.CorrectionStatusRejected, reviewerID, note, nil)
+}
+
+// review records the decision on a correction, applying it to the attendance in the same
+// transaction when apply is given, and notifies the employee.
+func (s *attendanceCorrectionService) review(ctx context.Context, correction *models.AttendanceCorrection, status string, reviewerID uint, note *string, apply func(repo repositories.AttendanceRepository) error) (*models.AttendanceCorrection, error) {
+	now := time.Now()
+	correction.Status = status
+	correction.ReviewedBy = &reviewerID
+	correction.ReviewedAt = &now
+	correction.ReviewNote = note
+
+	updated, err := s.repo.ReviewCorrection(ctx, correction, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.notificationService.Notify(ctx, correction.UserID,
+		"Attendance correction "+status,
+		fmt.Sprintf("Your attendance correction #%d has been %s.", correction.ID, status))
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// checkReviewer allows admins to review any correction and managers to review
+// the corrections of their direct reports, but never their own.
+func (s *attendanceCorrectionService) checkReviewer(reviewerID, employeeID uint) error {
+	if reviewerID == employeeID {
+		return ErrForbidden
+	}
+	reviewer, err := s.userRepo.FindByID(reviewerID)
+	if err != nil {
+		return err
+	}
+	if reviewer.Role.Name == models.RoleAdmin {
+		return nil
+	}
+	if reviewer.Role.Name != models.RoleManager {
+		return ErrForbidden
+	}
+
+	employee, err := s.userRepo.FindByID(employeeID)
+	if err != nil {
+		return err
+	}
+	if employee.ManagerID == nil || *employee.ManagerID != reviewer.ID {
+		return ErrForbidden
+	}
+	return nil
+}
\ No newline at end of file
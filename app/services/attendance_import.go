@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const maxImportRows = 5000
+
+// importColumns are the columns expected in the header of an attendance CSV, in any order.
+var importColumns = []string{"user_id", "date", "check_in", "check_out"}
+
+type AttendanceImportService interface {
+	UpsertAttendance(ctx context.Context, req *models.AttendanceEntryRequest) (*models.Attendance, error)
+	ImportAttendances(ctx context.Context, r io.Reader, dryRun, allowWeekends bool) (*models.AttendanceImportResult, error)
+}
+
+type attendanceImportService struct {
+	attendanceRepo    repositories.AttendanceRepository
+	shiftRepo         repositories.ShiftRepository
+	userRepo          repositories.UserRepository
+	payrollPeriodRepo repositories.PayrollPeriodRepository
+	attendanceService AttendanceService
+}
+
+func NewAttendanceImportService(
+	attendanceRepo repositories.AttendanceRepository,
+	shiftRepo repositories.ShiftRepository,
+	userRepo repositories.UserRepository,
+	payrollPeriodRepo repositories.PayrollPeriodRepository,
+	attendanceService AttendanceService) AttendanceImportService {
+	return &attendanceImportService{
+		attendanceRepo:    attendanceRepo,
+		shiftRepo:         shiftRepo,
+		userRepo:          userRepo,
+		payrollPeriodRepo: payrollPeriodRepo,
+		attendanceService: attendanceService,
+	}
+}
+
+func (s *attendanceImportService) UpsertAttendance(ctx context.Context, req *models.AttendanceEntryRequest) (*models.Attendance, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("date must be in YYYY-MM-DD format")
+	}
+	user, err := s.findUser(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.validateEntry(user, date, req.CheckIn, req.CheckOut, req.AllowWeekend); err != nil {
+		return nil, err
+	}
+	return s.attendanceService.RecordAttendance(ctx, user, date, req.CheckIn, req.CheckOut)
+}
+
+// ImportAttendances reads a CSV with user_id, date, check_in and check_out columns, as exported
+// by fingerprint machines. Times are HH:MM or HH:MM:SS in the employee's timezone; a check-out
+// earlier than the check-in belongs to the next day. Every row is validated on its own and
+// invalid rows are reported without stopping the import. On a dry run nothing is written.
+func (s *attendanceImportService) ImportAttendances(ctx context.Context, r io.Reader, dryRun, allowWeekends bool) (*models.AttendanceImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("the CSV file is empty or malformed")
+	}
+	columns, err := importColumnIndexes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.AttendanceImportResult{DryRun: dryRun}
+	seen := map[string]int{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if result.Total == maxImportRows {
+			return nil, fmt.Errorf("the CSV file may contain at most %d rows", maxImportRows)
+		}
+		result.Total++
+
+		row := models.AttendanceImportRow{Line: line, Action: models.ImportActionSkip}
+		if err != nil {
+			row.Error = err.Error()
+		} else if err := s.importRow(ctx, &row, record, columns, seen, dryRun, allowWeekends); err != nil {
+			row.Action = models.ImportActionSkip
+			row.Error = err.Error()
+		}
+
+		switch {
+		case row.Error != "":
+			result.Failed++
+		case row.Action == models.ImportActionCreate:
+			result.Created++
+		case row.Action == models.ImportActionUpdate:
+			result.Updated++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, nil
+}
+
+func (s *attendanceImportService) importRow(ctx context.Context, row *models.AttendanceImportRow, record []string, columns map[string]int, seen map[string]int, dryRun, allowWeekends bool) error {
+	field := func(name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	userID, err := strconv.ParseUint(field("user_id"), 10, 64)
+	if err != nil || userID == 0 {
+		return errors.New("user_id must be a positive number")
+	}
+	row.UserID = uint(userID)
+	row.Date = field("date")
+	date, err := time.Parse("2006-01-02", row.Date)
+	if err != nil {
+		return errors.New("date must be in YYYY-MM-DD format")
+	}
+
+	// Clock times are in the employee's timezone
+	user, err := s.findUser(row.UserID)
+	if err != nil {
+		return err
+	}
+	loc := utils.LocationOrDefault(user.Timezone)
+
+	row.CheckIn, err = parseImportTime(date, field("check_in"), loc)
+	if err != nil {
+		return fmt.Errorf("invalid check_in: %w", err)
+	}
+	row.CheckOut, err = parseImportTime(date, field("check_out"), loc)
+	if err != nil {
+		return fmt.Errorf("invalid check_out: %w", err)
+	}
+	if row.CheckIn != nil && row.CheckOut != nil && row.CheckOut.Before(*row.CheckIn) {
+		nextDay := row.CheckOut.AddDate(0, 0, 1)
+		row.CheckOut = &nextDay
+	}
+
+	key := fmt.Sprintf("%d|%s", row.UserID, row.Date)
+	if first, ok := seen[key]; ok {
+		return fmt.Errorf("duplicate of line %d for the same user and date", first)
+	}
+	seen[key] = row.Line
+
+	row.Action, err = s.validateEntry(user, date, row.CheckIn, row.CheckOut, allowWeekends)
+	if err != nil || dryRun {
+		return err
+	}
+
+	rowCtx := utils.WithFreshRequestID(ctx)
+	_, err = s.attendanceService.RecordAttendance(rowCtx, user, date, row.CheckIn, row.CheckOut)
+	return err
+}
+
+func (s *attendanceImportService) findUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("user not found")
+	}
+	return user, err
+}
+
+// validateEntry checks an admin entry and returns whether it would create or update the day.
+func (s *attendanceImportService) validateEntry(user *models.User, date time.Time, checkIn, checkOut *time.Time, allowWeekend bool) (string, error) {
+	if checkIn == nil && checkOut == nil {
+		return "", errors.New("either check_in or check_out must be provided")
+	}
+	if checkIn != nil && checkOut != nil && !checkOut.After(*checkIn) {
+		return "", errors.New("check-out must be after check-in")
+	}
+	now := time.Now()
+	if (checkIn != nil && checkIn.After(now)) || (checkOut != nil && checkOut.After(now)) {
+		return "", errors.New("attendance cannot be recorded in the future")
+	}
+
+	if err := ensureUnlocked(s.payrollPeriodRepo, date); err != nil {
+		return "", err
+	}
+
+	userID := user.ID
+	attDate := date.Format("2006-01-02")
+
+	if utils.IsWeekend(date) && !allowWeekend {
+		assignment, err := s.shiftRepo.GetAssignmentByUserAndDate(userID, attDate)
+		if err != nil {
+			return "", err
+		}
+		if assignment == nil {
+			return "", errors.New("date falls on a weekend and the user has no shift rostered")
+		}
+	}
+
+	existing, err := s.attendanceRepo.GetAttendanceByUserAndDate(userID, attDate)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+		if checkIn == nil {
+			return "", errors.New("check-in is required for a new attendance")
+		}
+		return models.ImportActionCreate, nil
+	}
+	if checkOut != nil && checkIn == nil && existing.CheckIn != nil && !checkOut.After(*existing.CheckIn) {
+		return "", errors.New("check-out must be after check-in")
+	}
+	return models.ImportActionUpdate, nil
+}
+
+func importColumnIndexes(header []string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("the CSV header must contain the columns %s", strings.Join(importColumns, ", "))
+		}
+	}
+	return columns, nil
+}
+
+// parseImportTime combines an attendance date with a clock time in loc. An empty value is nil.
+func parseImportTime(date time.Time, value string, loc *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		clock, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		t := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+		return &t, nil
+	}
+	return nil, errors.New("time must be in HH:MM or HH:MM:SS format")
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type DashboardService interface {
+	GetDailyBoard(viewerID, managerID uint, date time.Time) (*models.DailyBoard, error)
+	GetMonthlyMatrix(viewerID, managerID uint, month time.Time) (*models.MonthlyMatrix, error)
+}
+
+type dashboardService struct {
+	repo     repositories.DashboardRepository
+	userRepo repositories.UserRepository
+	config   config.AttendanceConfig
+}
+
+func NewDashboardService(repo repositories.DashboardRepository, userRepo repositories.UserRepository, cfg config.AttendanceConfig) DashboardService {
+	return &dashboardService{
+		repo:     repo,
+		userRepo: userRepo,
+		config:   cfg,
+	}
+}
+
+// teamCalendar indexes a team's attendances, leaves and rostered shifts by user and date.
+type teamCalendar struct {
+	attendances map[string]*models.TeamAttendance
+	leaves      map[uint][]*models.Leave
+	shifts      map[string]*models.Shift
+}
+
+func calendarKey(userID uint, date time.Time) string {
+	return fmt.Sprintf("%d|%s", userID, date.Format("2006-01-02"))
+}
+
+func (s *dashboardService) GetDailyBoard(viewerID, managerID uint, date time.Time) (*models.DailyBoard, error) {
+	date = utils.CivilDate(date)
+	managerID, err := s.teamManager(viewerID, managerID)
+	if err != nil {
+		return nil, err
+	}
+	members, calendar, err := s.loadTeam(managerID, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &models.DailyBoard{
+		Date:    date.Format("2006-01-02"),
+		Summary: map[string]int{},
+		Rows:    make([]*models.DailyBoardRow, 0, len(members)),
+	}
+	now := time.Now()
+	for _, member := range members {
+		row := &models.DailyBoardRow{TeamMember: *member}
+		row.Status, row.Attendance, row.LeaveType = s.dayStatus(member, date, now, calendar)
+		board.Summary[row.Status]++
+		board.Rows = append(board.Rows, row)
+	}
+	return board, nil
+}
+
+func (s *dashboardService) GetMonthlyMatrix(viewerID, managerID uint, month time.Time) (*models.MonthlyMatrix, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, -1)
+	managerID, err := s.teamManager(viewerID, managerID)
+	if err != nil {
+		return nil, err
+	}
+	members, calendar, err := s.loadTeam(managerID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &models.MonthlyMatrix{
+		Month: start.Format("2006-01"),
+		Rows:  make([]*models.MonthlyMatrixRow, 0, len(members)),
+	}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		matrix.Dates = append(matrix.Dates, d.Format("2006-01-02"))
+	}
+	now := time.Now()
+	for _, member := range members {
+		row := &models.MonthlyMatrixRow{TeamMember: *member, Summary: map[string]int{}}
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			status, _, _ := s.dayStatus(member, d, now, calendar)
+			row.Days = append(row.Days, status)
+			row.Summary[status]++
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+	return matrix, nil
+}
+
+// teamManager decides whose reports the viewer may see. Admins see everyone, or the
+// reports of the requested manager (users whose manager_id points at them); managers
+// only ever see their own direct reports.
+func (s *dashboardService) teamManager(viewerID, managerID uint) (uint, error) {
+	viewer, err := s.userRepo.FindByID(viewerID)
+	if err != nil {
+		return 0, err
+	}
+	switch viewer.Role.Name {
+	case models.RoleAdmin:
+		if managerID != 0 {
+			manager, err := s.userRepo.FindByID(managerID)
+			if err != nil {
+				return 0, err
+			}
+			if manager == nil {
+				return 0, gorm.ErrRecordNotFound
+			}
+		}
+		return managerID, nil
+	case models.RoleManager:
+		if managerID != 0 && managerID != viewer.ID {
+			return 0, ErrForbidden
+		}
+		return viewer.ID, nil
+	default:
+		return 0, ErrForbidden
+	}
+}
+
+// loadTeam reads everything the dashboard needs for the date range in a fixed number of queries.
+func (s *dashboardService) loadTeam(managerID uint, start, end time.Time) ([]*models.TeamMember, *teamCalendar, error) {
+	startDate, endDate := start.Format("2006-01-02"), end.Format("2006-01-02")
+	members, err := s.repo.GetTeamMembers(managerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attendances, err := s.repo.GetTeamAttendances(managerID, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	leaves, err := s.repo.GetTeamLeaves(managerID, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	assignments, err := s.repo.GetTeamAssignments(managerID, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	calendar := &teamCalendar{
+		attendances: make(map[string]*models.TeamAttendance, len(attendances)),
+		leaves:      make(map[uint][]*models.Leave),
+		shifts:      make(map[string]*models.Shift, len(assignments)),
+	}
+	for _, att := range attendances {
+		calendar.attendances[calendarKey(att.UserID, att.Date)] = att
+	}
+	for _, leave := range leaves {
+		calendar.leaves[leave.UserID] = append(calendar.leaves[leave.UserID], leave)
+	}
+	for _, assignment := range assignments {
+		shift := assignment.Shift
+		calendar.shifts[calendarKey(assignment.UserID, assignment.Date)] = &shift
+	}
+	return members, calendar, nil
+}
+
+// dayStatus classifies one member's day. A recorded attendance wins over leave, and a day
+// without either is only absent once the scheduled shift has ended.
+func (s *dashboardService) dayStatus(member *models.TeamMember, date, now time.Time, calendar *teamCalendar) (string, *models.TeamAttendance, string) {
+	if att, ok := calendar.attendances[calendarKey(member.ID, date)]; ok {
+		if att.LateMinutes > 0 {
+			return models.DashboardLate, att, ""
+		}
+		return models.DashboardPresent, att, ""
+	}
+	for _, leave := range calendar.leaves[member.ID] {
+		if !date.Before(leave.StartDate) && !date.After(leave.EndDate) {
+			return models.DashboardOnLeave, nil, leave.Type
+		}
+	}
+
+	shift, rostered := calendar.shifts[calendarKey(member.ID, date)]
+	if !rostered {
+		if utils.IsWeekend(date) {
+			return models.DashboardOff, nil, ""
+		}
+		shift = s.defaultShift()
+	}
+
+	loc := utils.LocationOrDefault(member.Timezone)
+	if date.After(utils.DateIn(now, loc)) {
+		return models.DashboardUpcoming, nil, ""
+	}
+	if _, end, err := shift.Window(date, loc); err == nil && now.Before(end) {
+		return models.DashboardNotCheckedIn, nil, ""
+	}
+	return models.DashboardAbsent, nil, ""
+}
+
+func (s *dashboardService) defaultShift() *models.Shift {
+	shift := &models.Shift{
+		StartTime: s.config.DefaultShiftStart,
+		EndTime:   s.config.DefaultShiftEnd,
+	}
+	shift.CrossesMidnight = shift.EndTime <= shift.StartTime
+	return shift
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+)
+
+type DeviceService interface {
+	GetDeviceList(pagination utils.Pagination) ([]*models.Device, int64, error)
+	GetDeviceByID(id uint) (*models.Device, error)
+	CreateDevice(ctx context.Context, req *models.DeviceRequest) (*models.DeviceWithKey, error)
+	UpdateDevice(ctx context.Context, id uint, req *models.DeviceRequest) (*models.Device, error)
+	DeleteDevice(id uint) error
+	RotateAPIKey(ctx context.Context, id uint) (*models.DeviceWithKey, error)
+}
+
+type deviceService struct {
+	repo repositories.DeviceRepository
+}
+
+func NewDeviceService(repo repositories.DeviceRepository) DeviceService {
+	return &deviceService{
+		repo: repo,
+	}
+}
+
+func (s *deviceService) GetDeviceList(pagination utils.Pagination) ([]*models.Device, int64, error) {
+	return s.repo.GetDeviceList(pagination)
+}
+
+func (s *deviceService) GetDeviceByID(id uint) (*models.Device, error) {
+	return s.repo.GetDeviceByID(id)
+}
+
+func (s *deviceService) CreateDevice(ctx context.Context, req *models.DeviceRequest) (*models.DeviceWithKey, error) {
+	device := &models.Device{IsActive: true}
+	applyDeviceRequest(device, req)
+	apiKey, err := issueAPIKey(device)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.CreateDevice(ctx, device); err != nil {
+		return nil, err
+	}
+	return &models.DeviceWithKey{Device: device, APIKey: apiKey}, nil
+}
+
+func (s *deviceService) UpdateDevice(ctx context.Context, id uint, req *models.DeviceRequest) (*models.Device, error) {
+	device, err := s.repo.GetDeviceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	applyDeviceRequest(device, req)
+	return s.repo.UpdateDevice(ctx, device)
+}
+
+func (s *deviceService) DeleteDevice(id uint) error {
+	return s.repo.DeleteDevice(id)
+}
+
+// RotateAPIKey replaces the key of a device. The old key stops working immediately.
+func (s *deviceService) RotateAPIKey(ctx context.Context, id uint) (*models.DeviceWithKey, error) {
+	device, err := s.repo.GetDeviceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := issueAPIKey(device)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.UpdateDevice(ctx, device); err != nil {
+		return nil, err
+	}
+	return &models.DeviceWithKey{Device: device, APIKey: apiKey}, nil
+}
+
+func applyDeviceRequest(device *models.Device, req *models.DeviceRequest) {
+	device.Name = req.Name
+	device.Code = req.Code
+	device.SiteID = req.SiteID
+	if req.IsActive != nil {
+		device.IsActive = *req.IsActive
+	}
+}
+
+func issueAPIKey(device *models.Device) (string, error) {
+	apiKey, err := utils.GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	device.APIKeyHash = utils.HashAPIKey(apiKey)
+	device.APIKeyPrefix = apiKey[:8]
+	return apiKey, nil
+}
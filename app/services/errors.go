@@ -0,0 +1,9 @@
+package services
+
+import "errors"
+
+// ErrForbidden is returned when the caller may not act on a record.
+var ErrForbidden = errors.New("you are not allowed to perform this action")
+
+// ErrPeriodLocked is returned when a change touches a date in an already processed payroll period.
+var ErrPeriodLocked = errors.New("the payroll period of this date has already been processed")
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+)
+
+type HolidayService interface {
+	GetHolidayList(year int) ([]*models.Holiday, error)
+	CreateHoliday(ctx context.Context, req *models.HolidayRequest) (*models.Holiday, error)
+	DeleteHoliday(id uint) error
+}
+
+type holidayService struct {
+	repo repositories.HolidayRepository
+}
+
+func NewHolidayService(repo repositories.HolidayRepository) HolidayService {
+	return &holidayService{
+		repo: repo,
+	}
+}
+
+func (s *holidayService) GetHolidayList(year int) ([]*models.Holiday, error) {
+	return s.repo.GetHolidayList(fmt.Sprintf("%04d-01-01", year), fmt.Sprintf("%04d-12-31", year))
+}
+
+func (s *holidayService) CreateHoliday(ctx context.Context, req *models.HolidayRequest) (*models.Holiday, error) {
+	date := utils.CivilDate(req.Date)
+	exists, err := s.repo.IsHoliday(date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("a holiday is already declared on this date")
+	}
+	return s.repo.CreateHoliday(ctx, &models.Holiday{Date: date, Name: req.Name})
+}
+
+func (s *holidayService) DeleteHoliday(id uint) error {
+	return s.repo.DeleteHoliday(id)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+)
+
+type KioskService interface {
+	IssueToken(device *models.Device) (*models.KioskToken, error)
+	CheckIn(ctx context.Context, userID uint, token string) (*models.Attendance, error)
+}
+
+type kioskService struct {
+	deviceRepo        repositories.DeviceRepository
+	siteRepo          repositories.SiteRepository
+	attendanceService AttendanceService
+	config            config.AttendanceConfig
+}
+
+func NewKioskService(
+	deviceRepo repositories.DeviceRepository,
+	siteRepo repositories.SiteRepository,
+	attendanceService AttendanceService,
+	cfg config.AttendanceConfig) KioskService {
+	return &kioskService{
+		deviceRepo:        deviceRepo,
+		siteRepo:          siteRepo,
+		attendanceService: attendanceService,
+		config:            cfg,
+	}
+}
+
+// IssueToken signs a fresh QR token for a kiosk. Kiosks poll for a new token before the
+// current one expires, so a photographed code is useless shortly after.
+func (s *kioskService) IssueToken(device *models.Device) (*models.KioskToken, error) {
+	if device.SiteID == nil {
+		return nil, errors.New("kiosk is not assigned to a site")
+	}
+	token, expiresAt, err := utils.GenerateKioskToken(device.ID, *device.SiteID, s.config.KioskTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &models.KioskToken{Token: token, SiteID: *device.SiteID, ExpiresAt: expiresAt}, nil
+}
+
+// CheckIn validates a scanned token and checks the user in at the kiosk's site. The kiosk
+// must still be active and assigned to the site the token was issued for.
+func (s *kioskService) CheckIn(ctx context.Context, userID uint, token string) (*models.Attendance, error) {
+	claims, err := utils.ParseKioskToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := s.deviceRepo.GetDeviceByID(claims.DeviceID)
+	if err != nil || !device.IsActive {
+		return nil, errors.New("kiosk is no longer active")
+	}
+	if device.SiteID == nil || *device.SiteID != claims.SiteID {
+		return nil, errors.New("kiosk token does not match the kiosk's site")
+	}
+	site, err := s.siteRepo.GetSiteByID(claims.SiteID)
+	if err != nil || !site.IsActive {
+		return nil, errors.New("kiosk site is no longer active")
+	}
+
+	return s.attendanceService.KioskCheckIn(ctx, userID, site)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type LeaveService interface {
+	GetLeaveList(userID uint, startDate, endDate string) ([]*models.Leave, error)
+	CreateLeave(ctx context.Context, req *models.LeaveRequest) (*models.Leave, error)
+	DeleteLeave(id uint) error
+}
+
+type leaveService struct {
+	repo     repositories.LeaveRepository
+	userRepo repositories.UserRepository
+	cache    *redis.Client
+}
+
+func NewLeaveService(repo repositories.LeaveRepository, userRepo repositories.UserRepository, cache *redis.Client) LeaveService {
+	return &leaveService{
+		repo:     repo,
+		userRepo: userRepo,
+		cache:    cache,
+	}
+}
+
+func (s *leaveService) GetLeaveList(userID uint, startDate, endDate string) ([]*models.Leave, error) {
+	return s.repo.GetLeaveList(userID, startDate, endDate)
+}
+
+func (s *leaveService) CreateLeave(ctx context.Context, req *models.LeaveRequest) (*models.Leave, error) {
+	startDate, endDate := utils.CivilDate(req.StartDate), utils.CivilDate(req.EndDate)
+	if endDate.Before(startDate) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+	if _, err := s.userRepo.FindByID(req.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	overlaps, err := s.repo.HasOverlappingLeave(req.UserID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, errors.New("the user already has leave within this date range")
+	}
+
+	leave := &models.Leave{
+		UserID:    req.UserID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Type:      req.Type,
+		Note:      req.Note,
+	}
+	created, err := s.repo.CreateLeave(ctx, leave)
+	if err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportAttendance)
+	return created, nil
+}
+
+func (s *leaveService) DeleteLeave(id uint) error {
+	if _, err := s.repo.GetLeaveByID(id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteLeave(id); err != nil {
+		return err
+	}
+	invalidateReports(context.Background(), s.cache, reportAttendance)
+	return nil
+}
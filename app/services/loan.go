@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// ErrLoanRepaymentStarted is returned when a loan with paid installments would be deleted.
+var ErrLoanRepaymentStarted = errors.New("loan already has paid installments")
+
+type LoanService interface {
+	GetLoanList(userID uint, pagination utils.Pagination) ([]*models.Loan, int64, error)
+	GetLoanByID(viewerID uint, id uint) (*models.Loan, error)
+	CreateLoan(ctx context.Context, req *models.LoanRequest) (*models.Loan, error)
+	DeleteLoan(id uint) error
+}
+
+type loanService struct {
+	repo     repositories.LoanRepository
+	userRepo repositories.UserRepository
+}
+
+func NewLoanService(repo repositories.LoanRepository, userRepo repositories.UserRepository) LoanService {
+	return &loanService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *loanService) GetLoanList(userID uint, pagination utils.Pagination) ([]*models.Loan, int64, error) {
+	return s.repo.GetLoanList(userID, pagination)
+}
+
+func (s *loanService) GetLoanByID(viewerID uint, id uint) (*models.Loan, error) {
+	loan, err := s.repo.GetLoanByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(s.userRepo, viewerID, loan.UserID); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+// CreateLoan records the loan with its monthly installment schedule. The principal is split
+// evenly in cents and the leftover cents go to the last installments, one each, so every
+// installment is at least 0.01.
+func (s *loanService) CreateLoan(ctx context.Context, req *models.LoanRequest) (*models.Loan, error) {
+	cents := int64(math.Round(req.Principal * 100))
+	installments := int64(req.Installments)
+	if cents < installments {
+		return nil, fmt.Errorf("principal must be at least %.2f to spread over %d installments", float64(installments)/100, req.Installments)
+	}
+	if _, err := s.userRepo.FindByID(req.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user %d not found", req.UserID)
+		}
+		return nil, err
+	}
+
+	loan, err := s.repo.CreateLoan(ctx, &models.Loan{
+		UserID:           req.UserID,
+		Type:             req.Type,
+		Principal:        req.Principal,
+		RemainingBalance: req.Principal,
+		Note:             req.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	firstDueDate := utils.CivilDate(req.FirstDueDate)
+	base, remainder := cents/installments, cents%installments
+	for i := 0; i < req.Installments; i++ {
+		amount := base
+		if int64(i) >= installments-remainder {
+			amount++
+		}
+		installment := models.LoanInstallment{
+			LoanID:   loan.ID,
+			Sequence: i + 1,
+			DueDate:  addMonths(firstDueDate, i),
+			Amount:   float64(amount) / 100,
+		}
+		installmentCtx := utils.WithFreshRequestID(ctx)
+		if err := s.repo.CreateInstallment(installmentCtx, &installment); err != nil {
+			return nil, fmt.Errorf("failed to create installment %d: %w", installment.Sequence, err)
+		}
+		loan.Installments = append(loan.Installments, installment)
+	}
+	return loan, nil
+}
+
+func (s *loanService) DeleteLoan(id uint) error {
+	loan, err := s.repo.GetLoanByID(id)
+	if err != nil {
+		return err
+	}
+	for _, installment := range loan.Installments {
+		if installment.IsPaid {
+			return ErrLoanRepaymentStarted
+		}
+	}
+	return s.repo.DeleteLoan(id)
+}
+
+// addMonths moves the date by whole months, keeping the day of the month where possible and
+// falling back to the month's last day, so a schedule starting on the 31st stays at month end.
+func addMonths(date time.Time, months int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
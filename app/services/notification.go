@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+)
+
+type NotificationService interface {
+	GetNotificationList(userID uint, pagination utils.Pagination) ([]*models.Notification, int64, error)
+	GetNotificationByID(id uint) (*models.Notification, error)
+	Notify(ctx context.Context, userID uint, title, message string) (*models.Notification, error)
+	MarkAsRead(id uint) error
+}
+
+type notificationService struct {
+	repo repositories.NotificationRepository
+}
+
+func NewNotificationService(repo repositories.NotificationRepository) NotificationService {
+	return &notificationService{
+		repo: repo,
+	}
+}
+
+func (s *notificationService) GetNotificationList(userID uint, pagination utils.Pagination) ([]*models.Notification, int64, error) {
+	return s.repo.GetNotificationList(userID, pagination)
+}
+
+func (s *notificationService) GetNotificationByID(id uint) (*models.Notification, error) {
+	return s.repo.GetNotificationByID(id)
+}
+
+func (s *notificationService) Notify(ctx context.Context, userID uint, title, message string) (*models.Notification, error) {
+	return s.repo.CreateNotification(ctx, &models.Notification{
+		UserID:  userID,
+		Title:   title,
+		Message: message,
+	})
+}
+
+func (s *notificationService) MarkAsRead(id uint) error {
+	return s.repo.MarkAsRead(id)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// ErrOffCycleProcessed is returned when a processed off-cycle run would be changed.
+var ErrOffCycleProcessed = errors.New("off-cycle run has already been processed")
+
+type OffCycleService interface {
+	GetRunList(pagination utils.Pagination) ([]*models.OffCycleRun, int64, error)
+	GetRunByID(id uint) (*models.OffCycleRun, error)
+	CreateRun(ctx context.Context, req *models.OffCycleRunRequest) (*models.OffCycleRun, error)
+	ProcessRun(ctx context.Context, id uint) (*models.OffCycleRun, error)
+	DeleteRun(id uint) error
+	GetMyPayslips(userID uint) ([]*models.OffCyclePayslip, error)
+}
+
+type offCycleService struct {
+	repo     repositories.OffCycleRepository
+	userRepo repositories.UserRepository
+}
+
+func NewOffCycleService(repo repositories.OffCycleRepository, userRepo repositories.UserRepository) OffCycleService {
+	return &offCycleService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *offCycleService) GetRunList(pagination utils.Pagination) ([]*models.OffCycleRun, int64, error) {
+	return s.repo.GetRunList(pagination)
+}
+
+func (s *offCycleService) GetRunByID(id uint) (*models.OffCycleRun, error) {
+	return s.repo.GetRunByID(id)
+}
+
+// CreateRun drafts an off-cycle run with one payslip per chosen employee, totalling the entered
+// earnings lines. The run can be reviewed and deleted until it is processed.
+func (s *offCycleService) CreateRun(ctx context.Context, req *models.OffCycleRunRequest) (*models.OffCycleRun, error) {
+	payslips := make([]models.OffCyclePayslip, 0, len(req.Employees))
+	seen := map[uint]bool{}
+	for _, employee := range req.Employees {
+		if seen[employee.UserID] {
+			return nil, fmt.Errorf("user %d is listed more than once", employee.UserID)
+		}
+		seen[employee.UserID] = true
+		if _, err := s.userRepo.FindByID(employee.UserID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("user %d not found", employee.UserID)
+			}
+			return nil, err
+		}
+		if req.Type == models.OffCycleTypeFinalSettlement {
+			settled, err := s.repo.HasFinalSettlement(employee.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if settled {
+				return nil, fmt.Errorf("user %d already has a final settlement", employee.UserID)
+			}
+		}
+
+		total := 0.0
+		for _, line := range employee.Lines {
+			total += line.Amount
+		}
+		payslips = append(payslips, models.OffCyclePayslip{
+			UserID:        employee.UserID,
+			Lines:         employee.Lines,
+			TotalEarnings: math.Round(total*100) / 100,
+		})
+	}
+
+	return s.repo.CreateRun(ctx, &models.OffCycleRun{
+		Type:     req.Type,
+		PayDate:  utils.CivilDate(req.PayDate),
+		Note:     req.Note,
+		Payslips: payslips,
+	})
+}
+
+// ProcessRun finalises the run so its payslips become visible to the employees.
+func (s *offCycleService) ProcessRun(ctx context.Context, id uint) (*models.OffCycleRun, error) {
+	run, err := s.repo.GetRunByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if run.IsProcessed {
+		return nil, ErrOffCycleProcessed
+	}
+	if err := s.repo.MarkRunAsProcessed(id, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRunByID(id)
+}
+
+func (s *offCycleService) DeleteRun(id uint) error {
+	run, err := s.repo.GetRunByID(id)
+	if err != nil {
+		return err
+	}
+	if run.IsProcessed {
+		return ErrOffCycleProcessed
+	}
+	return s.repo.DeleteRun(id)
+}
+
+func (s *offCycleService) GetMyPayslips(userID uint) ([]*models.OffCyclePayslip, error) {
+	return s.repo.GetProcessedPayslipsByUser(userID)
+}
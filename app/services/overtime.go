@@ -3,39 +3,50 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/config"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
-const maxHourLimit = 3
-
 type OvertimeService interface {
 	GetOvertimeList(userID uint, pagination utils.Pagination) ([]*models.Overtime, int64, error)
-	GetOvertimeByID(id uint) (*models.Overtime, error)
+	GetOvertimeByID(viewerID, id uint) (*models.Overtime, error)
 	SubmitOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error)
-	UpdateOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error)
-	DeleteOvertime(id uint) error
+	UpdateOvertime(ctx context.Context, viewerID uint, overtime *models.Overtime) (*models.Overtime, error)
+	DeleteOvertime(viewerID, id uint) error
+	ConfirmOvertime(ctx context.Context, viewerID uint, overtime *models.Overtime) (*models.Overtime, error)
+	DeriveOvertime(ctx context.Context, attendance *models.Attendance, hours int) (*models.Overtime, error)
 }
 
 type overtimeService struct {
-	repo  repositories.OvertimeRepository
-	cache *redis.Client
+	repo              repositories.OvertimeRepository
+	attendanceRepo    repositories.AttendanceRepository
+	payrollPeriodRepo repositories.PayrollPeriodRepository
+	userRepo          repositories.UserRepository
+	cache             *redis.Client
+	config            config.OvertimeConfig
 }
 
-func NewOvertimeService(repo repositories.OvertimeRepository, cache *redis.Client) OvertimeService {
+func NewOvertimeService(repo repositories.OvertimeRepository, attendanceRepo repositories.AttendanceRepository, payrollPeriodRepo repositories.PayrollPeriodRepository, userRepo repositories.UserRepository, cache *redis.Client, cfg config.OvertimeConfig) OvertimeService {
 	return &overtimeService{
-		repo:  repo,
-		cache: cache,
+		repo:              repo,
+		attendanceRepo:    attendanceRepo,
+		payrollPeriodRepo: payrollPeriodRepo,
+		userRepo:          userRepo,
+		cache:             cache,
+		config:            cfg,
 	}
 }
 
 func (s *overtimeService) GetOvertimeList(userID uint, pagination utils.Pagination) ([]*models.Overtime, int64, error) {
 	ctx := context.Background()
-	cacheKey := utils.BuildKey("overtime", pagination.Page, pagination.Limit)
+	cacheKey := utils.BuildKey("overtime", userID, pagination.Page, pagination.Limit)
 
 	if cached, err := utils.GetCache[models.OvertimeCache](ctx, s.cache, cacheKey); err == nil {
 		return cached.OvertimeList, cached.Total, nil
@@ -56,27 +67,30 @@ func (s *overtimeService) GetOvertimeList(userID uint, pagination utils.Paginati
 	return overtimeList, total, nil
 }
 
-func (s *overtimeService) GetOvertimeByID(id uint) (*models.Overtime, error) {
+func (s *overtimeService) GetOvertimeByID(viewerID, id uint) (*models.Overtime, error) {
 	ctx := context.Background()
 	cacheKey := utils.BuildKey("overtime", id)
 
-	if cached, err := utils.GetCache[models.Overtime](ctx, s.cache, cacheKey); err == nil {
-		return cached, nil
+	overtime, err := utils.GetCache[models.Overtime](ctx, s.cache, cacheKey)
+	if err != nil {
+		// Fallback to DB
+		overtime, err = s.repo.GetOvertimeByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Save to cache
+		utils.SetCache(ctx, s.cache, cacheKey, overtime, 10*time.Minute)
 	}
 
-	// Fallback to DB
-	overtime, err := s.repo.GetOvertimeByID(id)
-	if err != nil {
+	if err := authorizeOwner(s.userRepo, viewerID, overtime.UserID); err != nil {
 		return nil, err
 	}
-
-	// Save to cache
-	utils.SetCache(ctx, s.cache, cacheKey, overtime, 10*time.Minute)
-
 	return overtime, nil
 }
 
 func (s *overtimeService) SubmitOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error) {
+	overtime.Date = utils.CivilDate(overtime.Date)
 	exists, err := s.repo.GetOvertimeByUserAndDate(overtime.UserID, overtime.Date)
 	if err != nil {
 		return nil, err
@@ -84,23 +98,52 @@ func (s *overtimeService) SubmitOvertime(ctx context.Context, overtime *models.O
 	if exists != nil {
 		return nil, errors.New("overtime already exists for today")
 	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, overtime.Date); err != nil {
+		return nil, err
+	}
 
-	if overtime.Hours > maxHourLimit {
-		return nil, errors.New("overtime hours cannot exceed 3 hours per day")
+	if err := s.checkCaps(overtime); err != nil {
+		return nil, err
 	}
+	if err := s.checkPresence(overtime); err != nil {
+		return nil, err
+	}
+	overtime.Source = models.OvertimeSourceDeclared
+	overtime.Status = models.OvertimeStatusSubmitted
+
 	// Remove cache key if exists
 	err = utils.DeleteCacheByPattern(ctx, s.cache, "overtime:*")
 	if err != nil {
 		return nil, err
 	}
-	return s.repo.CreateOvertime(ctx, overtime)
+	created, err := s.repo.CreateOvertime(ctx, overtime)
+	if err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportOvertime)
+	return created, nil
 }
 
-func (s *overtimeService) UpdateOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error) {
-	cacheKey := utils.BuildKey("overtime", overtime.ID)
+func (s *overtimeService) UpdateOvertime(ctx context.Context, viewerID uint, overtime *models.Overtime) (*models.Overtime, error) {
+	overtime.Date = utils.CivilDate(overtime.Date)
+	// Neither the current nor the new date may be in a processed period
+	existing, err := s.repo.GetOvertimeByID(overtime.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(s.userRepo, viewerID, existing.UserID); err != nil {
+		return nil, err
+	}
+	overtime.UserID = existing.UserID
+	if err := ensureUnlocked(s.payrollPeriodRepo, existing.Date, overtime.Date); err != nil {
+		return nil, err
+	}
 
-	if overtime.Hours > maxHourLimit {
-		return nil, errors.New("overtime hours cannot exceed 3 hours per day")
+	if err := s.checkCaps(overtime); err != nil {
+		return nil, err
+	}
+	if err := s.checkPresence(overtime); err != nil {
+		return nil, err
 	}
 
 	// Update in DB
@@ -109,23 +152,231 @@ func (s *overtimeService) UpdateOvertime(ctx context.Context, overtime *models.O
 		return nil, err
 	}
 
-	// Invalidate cache
-	s.cache.Del(ctx, cacheKey)
+	// Invalidate the overtime and the lists it appears in
+	if err := utils.DeleteCacheByPattern(ctx, s.cache, "overtime:*"); err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportOvertime)
 
 	return updatedOvertime, nil
 }
 
-func (s *overtimeService) DeleteOvertime(id uint) error {
+func (s *overtimeService) DeleteOvertime(viewerID, id uint) error {
 	ctx := context.Background()
-	cacheKey := utils.BuildKey("overtime", id)
+
+	existing, err := s.repo.GetOvertimeByID(id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeOwner(s.userRepo, viewerID, existing.UserID); err != nil {
+		return err
+	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, existing.Date); err != nil {
+		return err
+	}
 
 	// Delete from DB
 	if err := s.repo.DeleteOvertime(id); err != nil {
 		return err
 	}
 
+	// Invalidate the overtime and the lists it appears in
+	err = utils.DeleteCacheByPattern(ctx, s.cache, "overtime:*")
+	invalidateReports(ctx, s.cache, reportOvertime)
+	return err
+}
+
+// ConfirmOvertime submits a proposed overtime. The overtime is read again rather than trusting
+// the (possibly cached) copy passed in, and only moves on while it is still proposed, so two
+// concurrent confirmations cannot both count towards the caps.
+func (s *overtimeService) ConfirmOvertime(ctx context.Context, viewerID uint, overtime *models.Overtime) (*models.Overtime, error) {
+	current, err := s.repo.GetOvertimeByID(overtime.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(s.userRepo, viewerID, current.UserID); err != nil {
+		return nil, err
+	}
+	if current.Status != models.OvertimeStatusProposed {
+		return nil, errors.New("only proposed overtime can be confirmed")
+	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, current.Date); err != nil {
+		return nil, err
+	}
+	// Confirmed overtime starts counting towards the caps
+	if err := s.checkCaps(current); err != nil {
+		return nil, err
+	}
+
+	confirmed, err := s.repo.ConfirmProposedOvertime(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, errors.New("only proposed overtime can be confirmed")
+	}
+	current.Status = models.OvertimeStatusSubmitted
+
 	// Invalidate cache
-	s.cache.Del(ctx, cacheKey)
+	if err := utils.DeleteCacheByPattern(ctx, s.cache, "overtime:*"); err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportOvertime)
+
+	return current, nil
+}
+
+// DeriveOvertime records the hours worked beyond the schedule of a checked-out attendance.
+// Depending on the configured mode the overtime waits for the employee to confirm it
+// or is submitted directly. Declared or already confirmed overtime is never overwritten.
+func (s *overtimeService) DeriveOvertime(ctx context.Context, attendance *models.Attendance, hours int) (*models.Overtime, error) {
+	if s.config.DeriveMode == config.OvertimeDeriveOff {
+		return nil, nil
+	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, attendance.Date); err != nil {
+		return nil, err
+	}
+	if s.config.MaxDailyHours > 0 && hours > s.config.MaxDailyHours {
+		hours = s.config.MaxDailyHours
+	}
+
+	existing, err := s.repo.GetOvertimeByUserAndDate(attendance.UserID, attendance.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	var overtime *models.Overtime
+	if existing != nil {
+		if existing.Source != models.OvertimeSourceAttendance || existing.Status != models.OvertimeStatusProposed {
+			return existing, nil
+		}
+		existing.Hours = hours
+		overtime, err = s.repo.UpdateOvertime(ctx, existing)
+	} else {
+		status := models.OvertimeStatusProposed
+		if s.config.DeriveMode == config.OvertimeDeriveSubmit {
+			status = models.OvertimeStatusSubmitted
+			// Submitted straight away, so only what the weekly and monthly caps still allow
+			usage, err := s.capUsage(attendance.UserID, attendance.Date, 0)
+			if err != nil {
+				return nil, err
+			}
+			if usage != nil && hours > usage.left {
+				hours = usage.left
+			}
+			if hours <= 0 {
+				return nil, nil
+			}
+		}
+		overtime, err = s.repo.CreateOvertime(ctx, &models.Overtime{
+			UserID:       attendance.UserID,
+			Date:         attendance.Date,
+			Hours:        hours,
+			AttendanceID: &attendance.ID,
+			Source:       models.OvertimeSourceAttendance,
+			Status:       status,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	if err := utils.DeleteCacheByPattern(ctx, s.cache, "overtime:*"); err != nil {
+		return nil, err
+	}
+	invalidateReports(ctx, s.cache, reportOvertime)
+	return overtime, nil
+}
 
+// overtimeCapUsage is how many hours are left under the tightest weekly or monthly cap.
+type overtimeCapUsage struct {
+	period string
+	limit  int
+	left   int
+}
+
+// checkCaps rejects overtime exceeding the daily cap or what is left under the weekly and
+// monthly caps. The overtime itself is left out of the totals, so updates don't count twice.
+func (s *overtimeService) checkCaps(overtime *models.Overtime) error {
+	if s.config.MaxDailyHours > 0 && overtime.Hours > s.config.MaxDailyHours {
+		return fmt.Errorf("overtime hours cannot exceed %d hours per day", s.config.MaxDailyHours)
+	}
+	usage, err := s.capUsage(overtime.UserID, overtime.Date, overtime.ID)
+	if err != nil {
+		return err
+	}
+	if usage != nil && overtime.Hours > usage.left {
+		return fmt.Errorf("overtime exceeds the %s cap of %d hours, %d hours left", usage.period, usage.limit, max(usage.left, 0))
+	}
 	return nil
 }
+
+// capUsage sums the submitted overtime in the week (Monday to Sunday) and calendar month of
+// the date, except the overtime with excludeID. It returns nil when neither cap is set.
+func (s *overtimeService) capUsage(userID uint, date time.Time, excludeID uint) (*overtimeCapUsage, error) {
+	if s.config.MaxWeeklyHours <= 0 && s.config.MaxMonthlyHours <= 0 {
+		return nil, nil
+	}
+	weekStart := date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, -1)
+
+	from, to := monthStart, monthEnd
+	if weekStart.Before(from) {
+		from = weekStart
+	}
+	if weekEnd.After(to) {
+		to = weekEnd
+	}
+	overtimes, err := s.repo.GetSubmittedOvertimes(userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	caps := []struct {
+		period     string
+		limit      int
+		start, end time.Time
+	}{
+		{"weekly", s.config.MaxWeeklyHours, weekStart, weekEnd},
+		{"monthly", s.config.MaxMonthlyHours, monthStart, monthEnd},
+	}
+	var tightest *overtimeCapUsage
+	for _, c := range caps {
+		if c.limit <= 0 {
+			continue
+		}
+		used := 0
+		for _, overtime := range overtimes {
+			if overtime.ID != excludeID && !overtime.Date.Before(c.start) && !overtime.Date.After(c.end) {
+				used += overtime.Hours
+			}
+		}
+		if tightest == nil || c.limit-used < tightest.left {
+			tightest = &overtimeCapUsage{period: c.period, limit: c.limit, left: c.limit - used}
+		}
+	}
+	return tightest, nil
+}
+
+// checkPresence rejects overtime that exceeds the time actually recorded beyond the
+// schedule on the attendance of that date, and links the overtime to the attendance.
+func (s *overtimeService) checkPresence(overtime *models.Overtime) error {
+	att, err := s.attendanceRepo.GetAttendanceByUserAndDate(overtime.UserID, overtime.Date.Format("2006-01-02"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("no attendance recorded for the overtime date")
+	}
+	if err != nil {
+		return err
+	}
+	if att.CheckOut == nil {
+		return errors.New("attendance for the overtime date has not been checked out")
+	}
+	if overtime.Hours*60 > att.OvertimeMinutes {
+		return fmt.Errorf("overtime hours exceed recorded presence beyond the schedule (%d minutes)", att.OvertimeMinutes)
+	}
+	overtime.AttendanceID = &att.ID
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+)
+
+// authorizeOwner lets the viewer act on a record only when it belongs to them, unless the viewer is
+// an admin. It fails with ErrForbidden otherwise.
+func authorizeOwner(userRepo repositories.UserRepository, viewerID, ownerID uint) error {
+	if viewerID != 0 && viewerID == ownerID {
+		return nil
+	}
+	viewer, err := userRepo.FindByID(viewerID)
+	if err != nil {
+		return err
+	}
+	if viewer.Role.Name != models.RoleAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type PayrollCalendarService interface {
+	GeneratePayrollPeriods(ctx context.Context, now time.Time) ([]*models.PayrollPeriod, error)
+}
+
+type payrollCalendarService struct {
+	repo   repositories.PayrollPeriodRepository
+	cache  *redis.Client
+	config config.PayrollCalendarConfig
+}
+
+func NewPayrollCalendarService(repo repositories.PayrollPeriodRepository, cache *redis.Client, cfg config.PayrollCalendarConfig) PayrollCalendarService {
+	return &payrollCalendarService{
+		repo:   repo,
+		cache:  cache,
+		config: cfg,
+	}
+}
+
+// GeneratePayrollPeriods creates the periods of the payroll calendar up to the configured number
+// of periods after the current one. New periods continue from the latest existing period, so
+// hand-made periods that don't follow the calendar are realigned without gaps or overlaps, but
+// never from further back than the configured number of periods before the current one.
+func (s *payrollCalendarService) GeneratePayrollPeriods(ctx context.Context, now time.Time) ([]*models.PayrollPeriod, error) {
+	today := utils.DateIn(now, utils.CompanyLocation())
+	_, horizon := s.periodContaining(today)
+	for i := 0; i < s.config.PeriodsAhead; i++ {
+		_, horizon = s.periodContaining(horizon.AddDate(0, 0, 1))
+	}
+
+	latest, err := s.repo.FindLatest()
+	if err != nil {
+		return nil, err
+	}
+	earliest, _ := s.periodContaining(today)
+	for i := 0; i < s.config.PeriodsBehind; i++ {
+		earliest, _ = s.periodContaining(earliest.AddDate(0, 0, -1))
+	}
+	next, _ := s.periodContaining(today)
+	if latest != nil {
+		next = latest.EndDate.AddDate(0, 0, 1)
+		if next.Before(earliest) {
+			next = earliest
+		}
+	}
+
+	var created []*models.PayrollPeriod
+	for !next.After(horizon) {
+		_, end := s.periodContaining(next)
+		periodCtx := utils.WithFreshRequestID(ctx)
+		period, err := s.repo.Create(periodCtx, &models.PayrollPeriod{StartDate: next, EndDate: end})
+		if err != nil {
+			return created, fmt.Errorf("failed to create payroll period %s: %w", next.Format("2006-01-02"), err)
+		}
+		created = append(created, period)
+		next = end.AddDate(0, 0, 1)
+	}
+
+	if len(created) > 0 {
+		if err := utils.DeleteCacheByPattern(ctx, s.cache, "payroll:*"); err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+// periodContaining returns the first and last day of the calendar period that includes date.
+func (s *payrollCalendarService) periodContaining(date time.Time) (time.Time, time.Time) {
+	date = utils.CivilDate(date)
+	year, month, day := date.Date()
+	switch s.config.Frequency {
+	case config.PayrollFrequencyBiWeekly:
+		anchor := utils.CivilDate(s.config.AnchorDate)
+		offset := int(date.Sub(anchor).Hours() / 24)
+		// Round towards the earlier period for dates before the anchor
+		periods := offset / 14
+		if offset < 0 && offset%14 != 0 {
+			periods--
+		}
+		start := anchor.AddDate(0, 0, periods*14)
+		return start, start.AddDate(0, 0, 13)
+	case config.PayrollFrequencySemiMonthly:
+		cutoff := s.config.CutoffDay
+		if cutoff <= 0 {
+			cutoff = 15
+		}
+		mid := cutoffDate(year, month, cutoff)
+		if day <= mid.Day() {
+			return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), mid
+		}
+		return mid.AddDate(0, 0, 1), cutoffDate(year, month, 0)
+	default:
+		end := cutoffDate(year, month, s.config.CutoffDay)
+		if day > end.Day() {
+			end = cutoffDate(year, month+1, s.config.CutoffDay)
+		}
+		previous := cutoffDate(end.Year(), end.Month()-1, s.config.CutoffDay)
+		return previous.AddDate(0, 0, 1), end
+	}
+}
+
+// cutoffDate returns the cutoff day of the month, clamped to the month's last day.
+// A cutoff of zero means the last day of the month.
+func cutoffDate(year int, month time.Month, cutoff int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	if cutoff <= 0 || cutoff > last.Day() {
+		return last
+	}
+	return time.Date(year, month, cutoff, 0, 0, 0, 0, time.UTC)
+}
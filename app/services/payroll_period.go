@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"math"
 
 	"time"
 
@@ -21,22 +23,37 @@ type PayrollPeriodService interface {
 	UpdatePayrollPeriod(ctx context.Context, period *models.PayrollPeriod) (*models.PayrollPeriod, error)
 	DeletePayrollPeriod(id uint) error
 	RunPayroll(ctx context.Context, periodID uint) error
+	ReopenPayrollPeriod(ctx context.Context, periodID uint) error
+	PreviewPayroll(periodID uint, swingThreshold float64) (*models.PayrollPreview, error)
 }
 
 type payrollPeriodService struct {
 	repo  repositories.PayrollPeriodRepository
 	userRepo repositories.UserRepository
 	payslipService PayslipService
+	deliveryService PayslipDeliveryService
 	cache *redis.Client
 }
 
 const chunkSize = 20
 
-func NewPayrollPeriodService(repo repositories.PayrollPeriodRepository, userRepo repositories.UserRepository, payslipService PayslipService, cache *redis.Client) PayrollPeriodService {
+var (
+	// ErrInvalidPeriodRange is returned when a payroll period ends before it starts.
+	ErrInvalidPeriodRange = errors.New("end_date must not be before start_date")
+	// ErrPeriodOverlap is returned when a payroll period shares a date with another period.
+	ErrPeriodOverlap = errors.New("payroll period overlaps an existing period")
+	// ErrPeriodGap is returned when a new payroll period does not start right after the latest one.
+	ErrPeriodGap = errors.New("payroll period must start the day after the latest period ends")
+	// ErrPeriodNotProcessed is returned when reopening a payroll period that has not been run.
+	ErrPeriodNotProcessed = errors.New("payroll period has not been processed")
+)
+
+func NewPayrollPeriodService(repo repositories.PayrollPeriodRepository, userRepo repositories.UserRepository, payslipService PayslipService, deliveryService PayslipDeliveryService, cache *redis.Client) PayrollPeriodService {
 	return &payrollPeriodService{
 		repo:  repo,
 		userRepo: userRepo,
 		payslipService: payslipService,
+		deliveryService: deliveryService,
 		cache: cache,
 	}
 }
@@ -86,6 +103,18 @@ func (s *payrollPeriodService) CreatePayrollPeriod(ctx context.Context, period *
 	if period == nil {
 		return nil, errors.New("payroll period cannot be nil")
 	}
+	normalizePeriodDates(period)
+	if err := validatePeriod(s.repo, period); err != nil {
+		return nil, err
+	}
+	// Periods added after the latest one must continue it without leaving days uncovered
+	latest, err := s.repo.FindLatest()
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && period.StartDate.After(latest.EndDate.AddDate(0, 0, 1)) {
+		return nil, fmt.Errorf("%w (%s)", ErrPeriodGap, latest.EndDate.Format("2006-01-02"))
+	}
 
 	createdPeriod, err := s.repo.Create(ctx, period)
 	if err != nil {
@@ -99,6 +128,10 @@ func (s *payrollPeriodService) UpdatePayrollPeriod(ctx context.Context, period *
 	if period == nil {
 		return nil, errors.New("payroll period cannot be nil")
 	}
+	normalizePeriodDates(period)
+	if err := validatePeriod(s.repo, period); err != nil {
+		return nil, err
+	}
 
 	updatedPeriod, err := s.repo.Update(ctx, period)
 	if err != nil {
@@ -107,14 +140,20 @@ func (s *payrollPeriodService) UpdatePayrollPeriod(ctx context.Context, period *
 	
 	cacheKey := utils.BuildKey("payroll", period.ID)
 	s.cache.Del(ctx, cacheKey) // Remove cache if exists
+	invalidateReports(ctx, s.cache, reportPayroll)
 
 	return updatedPeriod, nil
 }
 
+// DeletePayrollPeriod removes the period. Deleting a processed period voids its payslips and
+// releases the loan installments they deducted.
 func (s *payrollPeriodService) DeletePayrollPeriod(id uint) error {
 	if id == 0 {
 		return errors.New("invalid payroll period ID")
 	}
+	if _, err := s.repo.FindByID(id); err != nil {
+		return err
+	}
 
 	if err := s.repo.Delete(id); err != nil {
 		return err
@@ -123,6 +162,7 @@ func (s *payrollPeriodService) DeletePayrollPeriod(id uint) error {
 	ctx := context.Background()
 	cacheKey := utils.BuildKey("payroll", id)
 	s.cache.Del(ctx, cacheKey) // Invalidate cache after deletion
+	invalidateReports(ctx, s.cache, reportPayroll)
 
 	return nil
 }
@@ -162,5 +202,151 @@ func (s *payrollPeriodService) RunPayroll(ctx context.Context, periodID uint) er
 
 		offset += chunkSize
 	}
-	return s.repo.MarkAsProcessed(periodID)
-}
\ No newline at end of file
+	if err := s.repo.MarkAsProcessed(periodID); err != nil {
+		return err
+	}
+	invalidateReports(ctx, s.cache, reportPayroll)
+
+	// Emailing the payslips must not fail a run that is already saved
+	if _, err := s.deliveryService.QueuePeriod(ctx, periodID); err != nil {
+		log.Printf("failed to queue payslip emails for payroll period %d: %v\n", periodID, err)
+	}
+	return nil
+}
+
+// ReopenPayrollPeriod undoes a payroll run: the payslips and their emails are voided, their loan
+// installments released and the period unlocked, so it can be corrected and run again.
+func (s *payrollPeriodService) ReopenPayrollPeriod(ctx context.Context, periodID uint) error {
+	period, err := s.repo.FindByID(periodID)
+	if err != nil {
+		return err
+	}
+	if !period.IsProcessed {
+		return ErrPeriodNotProcessed
+	}
+
+	if err := s.repo.Reopen(periodID); err != nil {
+		return fmt.Errorf("failed to void payslips of payroll period %d: %w", periodID, err)
+	}
+	s.cache.Del(ctx, utils.BuildKey("payroll", periodID))
+	invalidateReports(ctx, s.cache, reportPayroll)
+	return nil
+}
+
+// PreviewPayroll calculates every employee's payslip for the period in memory and compares it with
+// the previous period. Rows are flagged when the salary is missing, nobody attended, the take-home
+// pay is negative or it moved by more than swingThreshold percent. Nothing is saved.
+func (s *payrollPeriodService) PreviewPayroll(periodID uint, swingThreshold float64) (*models.PayrollPreview, error) {
+	period, err := s.repo.FindByID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payroll period: %w", err)
+	}
+	if period.IsProcessed {
+		return nil, fmt.Errorf("payroll period %d is already processed", periodID)
+	}
+
+	preview := &models.PayrollPreview{
+		PayrollPeriodID:       period.ID,
+		SwingThresholdPercent: swingThreshold,
+		Anomalies:             map[string]int{},
+		Rows:                  []*models.PayrollPreviewRow{},
+	}
+	previousPay := map[uint]float64{}
+	previous, err := s.repo.FindPrevious(period.StartDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		preview.PreviousPayrollPeriodID = &previous.ID
+		summary, total, err := s.payslipService.GetSummary(previous.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range summary.Items {
+			previousPay[item.UserID] = item.TakeHomePay
+		}
+		preview.PreviousTotalTakeHomePay = total
+	}
+
+	for offset := 0; ; offset += chunkSize {
+		employees, err := s.userRepo.GetAllEmployee(offset, chunkSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get employee for payroll period %d: %w", periodID, err)
+		}
+		if len(employees) == 0 {
+			break
+		}
+
+		for _, employee := range employees {
+			row := &models.PayrollPreviewRow{UserID: employee.ID, Name: employee.Name, Anomalies: []string{}}
+			if pay, ok := previousPay[employee.ID]; ok {
+				row.PreviousTakeHomePay = &pay
+			}
+			if employee.MonthlySalary == nil || *employee.MonthlySalary <= 0 {
+				row.Anomalies = append(row.Anomalies, models.AnomalyMissingSalary)
+			} else {
+				payslip, err := s.payslipService.PreviewPayslip(employee.ID, period, *employee.MonthlySalary)
+				if err != nil {
+					return nil, fmt.Errorf("failed to preview payslip for employee %d: %w", employee.ID, err)
+				}
+				row.Payslip = payslip
+				preview.TotalTakeHomePay += payslip.TakeHomePay
+				row.Anomalies = append(row.Anomalies, payslipAnomalies(row, swingThreshold)...)
+			}
+			for _, anomaly := range row.Anomalies {
+				preview.Anomalies[anomaly]++
+			}
+			preview.Rows = append(preview.Rows, row)
+		}
+	}
+	preview.Employees = len(preview.Rows)
+	preview.TotalTakeHomePay = math.Round(preview.TotalTakeHomePay*100) / 100
+	return preview, nil
+}
+
+// payslipAnomalies fills in the change against the previous period and returns what looks off.
+func payslipAnomalies(row *models.PayrollPreviewRow, swingThreshold float64) []string {
+	var anomalies []string
+	if row.Payslip.AttendanceDays == 0 {
+		anomalies = append(anomalies, models.AnomalyZeroAttendance)
+	}
+	if row.Payslip.TakeHomePay < 0 {
+		anomalies = append(anomalies, models.AnomalyNegativeTakeHome)
+	}
+	if row.PreviousTakeHomePay == nil {
+		return anomalies
+	}
+	change := math.Round((row.Payslip.TakeHomePay-*row.PreviousTakeHomePay)*100) / 100
+	row.TakeHomePayChange = &change
+	if *row.PreviousTakeHomePay == 0 {
+		return anomalies
+	}
+	percent := math.Round(change / math.Abs(*row.PreviousTakeHomePay) * 10000) / 100
+	row.TakeHomePayChangePct = &percent
+	if math.Abs(percent) > swingThreshold {
+		anomalies = append(anomalies, models.AnomalyTakeHomeSwing)
+	}
+	return anomalies
+}
+
+// normalizePeriodDates keeps only the calendar dates of a period, as written by the client,
+// so date comparisons don't shift with the timezone of the server or database.
+func normalizePeriodDates(period *models.PayrollPeriod) {
+	period.StartDate = utils.CivilDate(period.StartDate)
+	period.EndDate = utils.CivilDate(period.EndDate)
+}
+
+// validatePeriod rejects reversed ranges and periods that share a date with another period.
+func validatePeriod(repo repositories.PayrollPeriodRepository, period *models.PayrollPeriod) error {
+	if period.EndDate.Before(period.StartDate) {
+		return ErrInvalidPeriodRange
+	}
+	overlaps, err := repo.HasOverlappingPeriod(period.StartDate.Format("2006-01-02"), period.EndDate.Format("2006-01-02"), period.ID)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return ErrPeriodOverlap
+	}
+	return nil
+}
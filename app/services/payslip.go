@@ -3,18 +3,24 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/config"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
 )
 
 type PayslipService interface {
 	GeneratePayslip(ctx context.Context, userID uint, periodID uint, monthlySalary float64) error
+	PreviewPayslip(userID uint, period *models.PayrollPeriod, monthlySalary float64) (*models.Payslip, error)
 	GetSummary(periodID uint) (*models.PayslipSummary, float64, error)
 	GetPayslipByUserAndPeriod(userID uint, periodID uint) (*models.Payslip, error)
+	GetPayslipHistory(userID uint, pagination utils.Pagination) ([]*models.Payslip, int64, error)
+	GetEarningsStatement(userID uint, year int) (*models.EarningsStatement, error)
 }
 
 type payslipService struct {
@@ -24,6 +30,12 @@ type payslipService struct {
 	reimbursementRepo repositories.ReimbursementRepository
 	periodRepo repositories.PayrollPeriodRepository
 	userRepo repositories.UserRepository
+	shiftRepo repositories.ShiftRepository
+	holidayRepo repositories.HolidayRepository
+	offCycleRepo repositories.OffCycleRepository
+	loanRepo repositories.LoanRepository
+	allowanceRepo repositories.AllowanceRepository
+	config config.PayrollConfig
 }
 
 func NewPayslipService(
@@ -32,13 +44,26 @@ func NewPayslipService(
 	overtimeRepo repositories.OvertimeRepository,
 	reimbursementRepo repositories.ReimbursementRepository,
 	periodRepo repositories.PayrollPeriodRepository,
-	userRepo repositories.UserRepository) PayslipService {
+	userRepo repositories.UserRepository,
+	shiftRepo repositories.ShiftRepository,
+	holidayRepo repositories.HolidayRepository,
+	offCycleRepo repositories.OffCycleRepository,
+	loanRepo repositories.LoanRepository,
+	allowanceRepo repositories.AllowanceRepository,
+	cfg config.PayrollConfig) PayslipService {
 	return &payslipService{
 		repo: repo,
 		attendanceRepo: attendanceRepo,
 		overtimeRepo: overtimeRepo,
 		reimbursementRepo: reimbursementRepo,
 		periodRepo: periodRepo,
+		userRepo: userRepo,
+		shiftRepo: shiftRepo,
+		holidayRepo: holidayRepo,
+		offCycleRepo: offCycleRepo,
+		loanRepo: loanRepo,
+		allowanceRepo: allowanceRepo,
+		config: cfg,
 	}
 }
 
@@ -52,36 +77,220 @@ func (s *payslipService) GeneratePayslip(ctx context.Context, userID uint, perio
 		return err
 	}
 	
-	start := period.StartDate.Format("2006-01-02")
-	end := period.EndDate.Format("2006-01-02")
-	workdays := utils.CountWorkingDays(period.StartDate, period.EndDate)
+	payslip, err := s.calculatePayslip(userID, period, monthlySalary)
+	if err != nil {
+		return err
+	}
+	payslip.GeneratedAt = time.Now()
+	return s.repo.Create(ctx, payslip, period.EndDate.UTC().Format("2006-01-02"))
+}
+
+// PreviewPayslip calculates a payslip exactly like GeneratePayslip, without saving it.
+func (s *payslipService) PreviewPayslip(userID uint, period *models.PayrollPeriod, monthlySalary float64) (*models.Payslip, error) {
+	return s.calculatePayslip(userID, period, monthlySalary)
+}
+
+func (s *payslipService) calculatePayslip(userID uint, period *models.PayrollPeriod, monthlySalary float64) (*models.Payslip, error) {
+	start := period.StartDate.UTC().Format("2006-01-02")
+	end := period.EndDate.UTC().Format("2006-01-02")
+	workdays := utils.CountWorkingDays(period.StartDate.UTC(), period.EndDate.UTC())
+	if workdays == 0 {
+		return nil, errors.New("payroll period has no working days")
+	}
 	attended, _ := s.attendanceRepo.CountWorkingDays(userID, start, end)
-	overtimeHours, _ := s.overtimeRepo.CountOvertimeHours(userID, start, end)
 	reimbursements, _ := s.reimbursementRepo.SumReimbursement(userID, start, end)
+	penalties, err := s.attendanceRepo.SumPenalties(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
 	
 	dailySalary := monthlySalary / float64(workdays)
 	dailySalary = math.Round(dailySalary * 100) / 100
-	overtimePay := overtimeHours * (dailySalary / 8) * 2
+	overtimeHours, weightedOvertime, err := s.weightOvertime(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	overtimePay := weightedOvertime * (dailySalary / 8)
 	overtimePay = math.Round(overtimePay * 100) / 100
-	total := float64(attended) * dailySalary + overtimePay + reimbursements
+	lateDeduction := float64(penalties.LateMinutes) * s.config.LatePenaltyPerMinute +
+		float64(penalties.LateDays) * s.config.LatePenaltyPerOccurrence
+	lateDeduction = math.Round(lateDeduction * 100) / 100
+	undertimeDeduction := 0.0
+	if s.config.DeductUndertime {
+		undertimeDeduction = float64(penalties.UndertimeMinutes) / 60 * (dailySalary / 8)
+		undertimeDeduction = math.Round(undertimeDeduction * 100) / 100
+	}
+	installments, err := s.loanRepo.GetDueInstallments(userID, end)
+	if err != nil {
+		return nil, err
+	}
+	loanDeduction := 0.0
+	for _, installment := range installments {
+		loanDeduction += installment.Amount
+	}
+	loanDeduction = math.Round(loanDeduction * 100) / 100
+	lines, err := s.payslipLines(userID, period, start, end)
+	if err != nil {
+		return nil, err
+	}
+	total := float64(attended) * dailySalary + overtimePay + reimbursements - lateDeduction - undertimeDeduction - loanDeduction
+	for _, line := range lines {
+		total += line.Amount
+	}
 	total = math.Round(total * 100) / 100
 	
-	now := time.Now()
 	payslip := &models.Payslip{
 		UserID: userID,
-		PayrollPeriodID: periodID,
-		GeneratedAt: now,
+		PayrollPeriodID: period.ID,
 		AttendanceDays: int(attended),
 		AttendanceEarnings: dailySalary,
 		OvertimeHours: overtimeHours,
 		OvertimeEarnings: overtimePay,
 		TotalReimbursement: reimbursements,
+		LateDays: int(penalties.LateDays),
+		LateMinutes: int(penalties.LateMinutes),
+		LateDeduction: lateDeduction,
+		UndertimeMinutes: int(penalties.UndertimeMinutes),
+		UndertimeDeduction: undertimeDeduction,
+		LoanDeduction: loanDeduction,
 		TakeHomePay: total,
+		Lines: lines,
 	}
-	if err := s.repo.Create(ctx, payslip); err != nil {
-		return err
+	return payslip, nil
+}
+
+// payslipLines lists the daily meal and transport allowances, the allowances effective during the
+// period, prorated to the days they cover, and the user's one-time adjustments of the period.
+func (s *payslipService) payslipLines(userID uint, period *models.PayrollPeriod, start, end string) ([]models.PayslipLine, error) {
+	lines, err := s.dailyAllowanceLines(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.allowanceRepo.GetActiveAssignments(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	adjustments, err := s.allowanceRepo.GetAdjustmentList(period.ID, userID)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	periodStart, periodEnd := utils.CivilDate(period.StartDate.UTC()), utils.CivilDate(period.EndDate.UTC())
+	periodDays := int(periodEnd.Sub(periodStart).Hours()/24) + 1
+	for _, assignment := range assignments {
+		from, to := periodStart, periodEnd
+		if startDate := utils.CivilDate(assignment.StartDate.UTC()); startDate.After(from) {
+			from = startDate
+		}
+		if assignment.EndDate != nil {
+			if endDate := utils.CivilDate(assignment.EndDate.UTC()); endDate.Before(to) {
+				to = endDate
+			}
+		}
+		description := assignment.Name
+		if days := int(to.Sub(from).Hours()/24) + 1; days < periodDays {
+			description = fmt.Sprintf("%s (%d of %d days)", assignment.Name, days, periodDays)
+		}
+		lines = append(lines, models.PayslipLine{Type: models.PayslipLineAllowance, Description: description, Amount: prorateMonthly(assignment.Amount, from, to)})
+	}
+	for _, adjustment := range adjustments {
+		lines = append(lines, models.PayslipLine{Type: models.PayslipLineAdjustment, Description: adjustment.Description, Amount: adjustment.Amount})
+	}
+	return lines, nil
+}
+
+// prorateMonthly pays the part of a monthly amount earned between two dates, inclusive. Each
+// calendar month contributes the share of its days in the range, so semi-monthly, bi-weekly and
+// monthly periods all add up to the monthly amount over a whole month.
+func prorateMonthly(amount float64, from, to time.Time) float64 {
+	share := 0.0
+	for day := from; !day.After(to); {
+		monthEnd := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		last := monthEnd
+		if to.Before(last) {
+			last = to
+		}
+		share += (last.Sub(day).Hours()/24 + 1) / float64(monthEnd.Day())
+		day = monthEnd.AddDate(0, 0, 1)
+	}
+	return math.Round(amount*share*100) / 100
+}
+
+// dailyAllowanceLines pays the configured meal and transport allowances for every attended day
+// that meets the eligibility rule.
+func (s *payslipService) dailyAllowanceLines(userID uint, start, end string) ([]models.PayslipLine, error) {
+	lines := []models.PayslipLine{}
+	if s.config.MealAllowancePerDay <= 0 && s.config.TransportAllowancePerDay <= 0 {
+		return lines, nil
+	}
+	days, err := s.attendanceRepo.CountAllowanceDays(userID, start, end, models.DailyAllowanceRule{
+		MaxLateMinutes:   s.config.DailyAllowanceMaxLateMinutes,
+		RequireFullHours: s.config.DailyAllowanceRequireFullHours,
+		RequireOnSite:    s.config.DailyAllowanceRequireOnSite,
+	})
+	if err != nil || days == 0 {
+		return lines, err
+	}
+
+	rates := []struct {
+		description string
+		perDay      float64
+	}{
+		{"Meal allowance", s.config.MealAllowancePerDay},
+		{"Transport allowance", s.config.TransportAllowancePerDay},
+	}
+	for _, rate := range rates {
+		if rate.perDay <= 0 {
+			continue
+		}
+		lines = append(lines, models.PayslipLine{
+			Type:        models.PayslipLineDailyAllowance,
+			Description: fmt.Sprintf("%s (%d days)", rate.description, days),
+			Amount:      math.Round(float64(days)*rate.perDay*100) / 100,
+		})
+	}
+	return lines, nil
+}
+
+// weightOvertime totals the submitted overtime hours of a period and the same hours weighted
+// by the tiered multipliers of each day: holiday, rest day (a weekend without a rostered
+// shift) or work day.
+func (s *payslipService) weightOvertime(userID uint, start, end string) (float64, float64, error) {
+	overtimes, err := s.overtimeRepo.GetSubmittedOvertimes(userID, start, end)
+	if err != nil || len(overtimes) == 0 {
+		return 0, 0, err
+	}
+	holidays, err := s.holidayRepo.GetHolidayList(start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+	assignments, err := s.shiftRepo.GetAssignmentList(userID, start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+	isHoliday := map[string]bool{}
+	for _, holiday := range holidays {
+		isHoliday[holiday.Date.Format("2006-01-02")] = true
+	}
+	isRostered := map[string]bool{}
+	for _, assignment := range assignments {
+		isRostered[assignment.Date.Format("2006-01-02")] = true
+	}
+
+	hours, weighted := 0.0, 0.0
+	for _, overtime := range overtimes {
+		date := overtime.Date.Format("2006-01-02")
+		tiers := s.config.OvertimeWeekdayTiers
+		switch {
+		case isHoliday[date]:
+			tiers = s.config.OvertimeHolidayTiers
+		case utils.IsWeekend(overtime.Date) && !isRostered[date]:
+			tiers = s.config.OvertimeRestDayTiers
+		}
+		hours += float64(overtime.Hours)
+		weighted += utils.WeightedOvertimeHours(float64(overtime.Hours), tiers)
+	}
+	return hours, weighted, nil
 }
 
 func (s *payslipService) GetSummary(periodID uint) (*models.PayslipSummary, float64, error) {
@@ -116,4 +325,116 @@ func (s *payslipService) GetPayslipByUserAndPeriod(userID uint, periodID uint) (
 		return nil, errors.New("payslip not found")
 	}
 	return payslip, nil
-}
\ No newline at end of file
+}
+
+// GetPayslipHistory lists the user's payslips, latest period first, each with the year-to-date
+// totals of the calendar year its period ends in.
+func (s *payslipService) GetPayslipHistory(userID uint, pagination utils.Pagination) ([]*models.Payslip, int64, error) {
+	payslips, total, err := s.repo.GetHistoryByUser(userID, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statements := map[int]*models.EarningsStatement{}
+	for _, payslip := range payslips {
+		if payslip.PayrollPeriod == nil {
+			continue
+		}
+		endDate := payslip.PayrollPeriod.EndDate.UTC()
+		statement, ok := statements[endDate.Year()]
+		if !ok {
+			statement, err = s.GetEarningsStatement(userID, endDate.Year())
+			if err != nil {
+				return nil, 0, err
+			}
+			statements[endDate.Year()] = statement
+		}
+
+		yearToDate := models.PayslipTotals{}
+		for _, line := range statement.Lines {
+			if line.Date.After(endDate) {
+				break
+			}
+			yearToDate.Add(line.Gross, line.Deductions, line.Net)
+		}
+		payslip.YearToDate = &yearToDate
+	}
+	return payslips, total, nil
+}
+
+// GetEarningsStatement totals the user's regular payslips of the periods ending in the year and
+// the processed off-cycle payslips paid in it. Off-cycle payslips carry no deductions.
+func (s *payslipService) GetEarningsStatement(userID uint, year int) (*models.EarningsStatement, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	startDate := fmt.Sprintf("%04d-01-01", year)
+	endDate := fmt.Sprintf("%04d-12-31", year)
+	payslips, err := s.repo.GetByUserBetween(userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	offCyclePayslips, err := s.offCycleRepo.GetProcessedPayslipsBetween(userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &models.EarningsStatement{
+		UserID: userID,
+		Name:   user.Name,
+		Year:   year,
+		Lines:  []*models.EarningsStatementLine{},
+	}
+	for _, payslip := range payslips {
+		statement.AttendanceEarnings += float64(payslip.AttendanceDays) * payslip.AttendanceEarnings
+		statement.OvertimeEarnings += payslip.OvertimeEarnings
+		statement.Reimbursements += payslip.TotalReimbursement
+		for _, payslipLine := range payslip.Lines {
+			if payslipLine.Type == models.PayslipLineAdjustment {
+				statement.Adjustments += payslipLine.Amount
+			} else {
+				statement.Allowances += payslipLine.Amount
+			}
+		}
+		line := &models.EarningsStatementLine{
+			Type:            "payroll",
+			PayrollPeriodID: &payslip.PayrollPeriodID,
+			PayslipTotals:   models.PayslipTotals{Gross: payslip.GrossPay(), Deductions: payslip.Deductions(), Net: payslip.TakeHomePay},
+		}
+		if payslip.PayrollPeriod != nil {
+			line.Date = payslip.PayrollPeriod.EndDate.UTC()
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	for _, payslip := range offCyclePayslips {
+		statement.OffCycleEarnings += payslip.TotalEarnings
+		line := &models.EarningsStatementLine{
+			OffCycleRunID: &payslip.OffCycleRunID,
+			PayslipTotals: models.PayslipTotals{Gross: payslip.TotalEarnings, Net: payslip.TotalEarnings},
+		}
+		if payslip.Run != nil {
+			line.Type = payslip.Run.Type
+			line.Date = payslip.Run.PayDate.UTC()
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	sort.SliceStable(statement.Lines, func(i, j int) bool {
+		return statement.Lines[i].Date.Before(statement.Lines[j].Date)
+	})
+
+	statement.AttendanceEarnings = math.Round(statement.AttendanceEarnings*100) / 100
+	statement.OvertimeEarnings = math.Round(statement.OvertimeEarnings*100) / 100
+	statement.Reimbursements = math.Round(statement.Reimbursements*100) / 100
+	statement.OffCycleEarnings = math.Round(statement.OffCycleEarnings*100) / 100
+	statement.Allowances = math.Round(statement.Allowances*100) / 100
+	statement.Adjustments = math.Round(statement.Adjustments*100) / 100
+	for _, line := range statement.Lines {
+		statement.Totals.Add(line.Gross, line.Deductions, line.Net)
+	}
+	return statement, nil
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/mailer"
+	"github.com/galiherlangga/go-attendance/pkg/pdf"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type PayslipDeliveryService interface {
+	GetDeliveryList(periodID uint, status string, pagination utils.Pagination) ([]*models.PayslipDelivery, int64, error)
+	QueuePeriod(ctx context.Context, periodID uint) (int, error)
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+	Resend(ctx context.Context, id uint) (*models.PayslipDelivery, error)
+	PDFPassword(userID uint) (string, error)
+}
+
+type payslipDeliveryService struct {
+	repo        repositories.PayslipDeliveryRepository
+	payslipRepo repositories.PayslipRepository
+	userRepo    repositories.UserRepository
+	periodRepo  repositories.PayrollPeriodRepository
+	mailer      mailer.Mailer
+	cfg         config.PayslipEmailConfig
+}
+
+const deliveryBatchSize = 50
+
+// deliveryClaimTimeout is how long a claimed delivery waits before another run may take it
+// over, in case the instance sending it stopped.
+const deliveryClaimTimeout = 15 * time.Minute
+
+// ErrPDFProtectionOff is returned for a PDF password while emailed payslips are not protected.
+var ErrPDFProtectionOff = errors.New("payslip PDFs are not password protected")
+
+func NewPayslipDeliveryService(repo repositories.PayslipDeliveryRepository, payslipRepo repositories.PayslipRepository, userRepo repositories.UserRepository, periodRepo repositories.PayrollPeriodRepository, mailer mailer.Mailer, cfg config.PayslipEmailConfig) PayslipDeliveryService {
+	return &payslipDeliveryService{
+		repo:        repo,
+		payslipRepo: payslipRepo,
+		userRepo:    userRepo,
+		periodRepo:  periodRepo,
+		mailer:      mailer,
+		cfg:         cfg,
+	}
+}
+
+func (s *payslipDeliveryService) GetDeliveryList(periodID uint, status string, pagination utils.Pagination) ([]*models.PayslipDelivery, int64, error) {
+	return s.repo.GetDeliveryList(periodID, status, pagination)
+}
+
+// QueuePeriod queues an email for every payslip of the period, skipping employees whose payslip
+// is already waiting, sent or failed; sending those again takes an explicit Resend. Nothing is
+// queued while payslip emails are turned off.
+func (s *payslipDeliveryService) QueuePeriod(ctx context.Context, periodID uint) (int, error) {
+	if !s.cfg.Enabled {
+		return 0, nil
+	}
+	payslips, err := s.payslipRepo.GetByPeriod(periodID)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, payslip := range payslips {
+		if payslip.User.Email == "" {
+			continue
+		}
+		queuedBefore, err := s.repo.HasDelivery(payslip.UserID, periodID)
+		if err != nil {
+			return queued, err
+		}
+		if queuedBefore {
+			continue
+		}
+		ctx := utils.WithFreshRequestID(ctx)
+		if _, err := s.repo.CreateDelivery(ctx, &models.PayslipDelivery{
+			UserID:          payslip.UserID,
+			PayrollPeriodID: periodID,
+			Recipient:       payslip.User.Email,
+			Status:          models.PayslipDeliveryPending,
+		}); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// DeliverDue sends the pending emails whose next attempt is due and reports how many were sent.
+// The deliveries are claimed first, so two instances never send the same email. A failed email
+// is retried with a doubling delay until it runs out of attempts.
+func (s *payslipDeliveryService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(now, now.Add(deliveryClaimTimeout), deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, delivery := range deliveries {
+		delivery.Attempts++
+		sendErr := s.send(ctx, delivery)
+		switch {
+		case sendErr == nil:
+			sentAt := now
+			delivery.Status = models.PayslipDeliverySent
+			delivery.SentAt = &sentAt
+			delivery.NextAttemptAt = nil
+			delivery.LastError = nil
+			sent++
+		case errors.Is(sendErr, gorm.ErrRecordNotFound), delivery.Attempts >= s.cfg.MaxAttempts:
+			// A voided payslip or a deleted user will not come back by retrying
+			message := sendErr.Error()
+			delivery.Status = models.PayslipDeliveryFailed
+			delivery.NextAttemptAt = nil
+			delivery.LastError = &message
+		default:
+			message := sendErr.Error()
+			nextAttemptAt := now.Add(s.cfg.RetryInterval * time.Duration(math.Pow(2, float64(delivery.Attempts-1))))
+			delivery.Status = models.PayslipDeliveryPending
+			delivery.NextAttemptAt = &nextAttemptAt
+			delivery.LastError = &message
+		}
+		if _, err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// Resend queues the payslip again, to the employee's current email address. The earlier
+// delivery is kept as it is; a delivery that is still pending or being sent is returned instead.
+func (s *payslipDeliveryService) Resend(ctx context.Context, id uint) (*models.PayslipDelivery, error) {
+	delivery, err := s.repo.GetDeliveryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == models.PayslipDeliveryPending || delivery.Status == models.PayslipDeliverySending {
+		return delivery, nil
+	}
+	if _, err := s.payslipRepo.GetByUserAndPeriod(delivery.UserID, delivery.PayrollPeriodID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(delivery.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return s.repo.CreateDelivery(ctx, &models.PayslipDelivery{
+		UserID:          delivery.UserID,
+		PayrollPeriodID: delivery.PayrollPeriodID,
+		Recipient:       user.Email,
+		Status:          models.PayslipDeliveryPending,
+	})
+}
+
+// PDFPassword returns the password that opens the user's emailed payslip PDFs.
+func (s *payslipDeliveryService) PDFPassword(userID uint) (string, error) {
+	if !s.protectsPDF() {
+		return "", ErrPDFProtectionOff
+	}
+	return utils.PayslipPDFPassword(s.cfg.PDFSecret, userID), nil
+}
+
+func (s *payslipDeliveryService) protectsPDF() bool {
+	return s.cfg.AttachPDF && s.cfg.ProtectPDF && s.cfg.PDFSecret != ""
+}
+
+func (s *payslipDeliveryService) send(ctx context.Context, delivery *models.PayslipDelivery) error {
+	payslip, err := s.payslipRepo.GetByUserAndPeriod(delivery.UserID, delivery.PayrollPeriodID)
+	if err != nil {
+		return fmt.Errorf("payslip not found: %w", err)
+	}
+	user, err := s.userRepo.FindByID(delivery.UserID)
+	if err == nil && user == nil {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	period, err := s.periodRepo.FindByID(delivery.PayrollPeriodID)
+	if err != nil {
+		return fmt.Errorf("payroll period not found: %w", err)
+	}
+
+	msg, err := s.composeMessage(user, period, payslip)
+	if err != nil {
+		return err
+	}
+	msg.To = delivery.Recipient
+	return s.mailer.Send(ctx, msg)
+}
+
+// composeMessage puts the payslip in the body, or in a PDF attachment when configured. A
+// protected PDF opens with the employee's password from the app; when protection is on but no
+// secret is configured, employees are only told that their payslip is ready.
+func (s *payslipDeliveryService) composeMessage(user *models.User, period *models.PayrollPeriod, payslip *models.Payslip) (mailer.Message, error) {
+	periodLabel := fmt.Sprintf("%s - %s", period.StartDate.Format("02 Jan 2006"), period.EndDate.Format("02 Jan 2006"))
+	msg := mailer.Message{Subject: "Your payslip for " + periodLabel}
+	greeting := fmt.Sprintf("Hello %s,\n\n", user.Name)
+	lines := payslipStatementLines(user, periodLabel, payslip)
+
+	if !s.cfg.AttachPDF {
+		msg.Body = greeting + strings.Join(lines, "\n") + "\n"
+		return msg, nil
+	}
+
+	password := ""
+	if s.cfg.ProtectPDF {
+		if !s.protectsPDF() {
+			msg.Body = greeting + fmt.Sprintf("Your payslip for %s is ready. Please log in to view it.\n", periodLabel)
+			return msg, nil
+		}
+		password = utils.PayslipPDFPassword(s.cfg.PDFSecret, user.ID)
+	}
+	document, err := pdf.Render(lines, password)
+	if err != nil {
+		return msg, err
+	}
+	msg.Body = greeting + fmt.Sprintf("Your payslip for %s is attached.\n", periodLabel)
+	if password != "" {
+		msg.Body += "The PDF is protected; its password is shown under your payslips in the app.\n"
+	}
+	msg.Attachments = []mailer.Attachment{{
+		Filename:    fmt.Sprintf("payslip-%s.pdf", period.EndDate.Format("2006-01-02")),
+		ContentType: "application/pdf",
+		Data:        document,
+	}}
+	return msg, nil
+}
+
+// payslipStatementLines lays the payslip out as fixed-width text rows, shared by the email
+// body and the PDF.
+func payslipStatementLines(user *models.User, periodLabel string, payslip *models.Payslip) []string {
+	row := func(label string, amount float64) string {
+		return fmt.Sprintf("  %-44s %18s", label, formatAmount(amount))
+	}
+	employee := user.Name
+	if user.EmployeeCode != nil && *user.EmployeeCode != "" {
+		employee += " (" + *user.EmployeeCode + ")"
+	}
+
+	lines := []string{
+		"PAYSLIP",
+		"Employee: " + employee,
+		"Period:   " + periodLabel,
+		"",
+		"Earnings",
+		row(fmt.Sprintf("Attendance (%d days x %s)", payslip.AttendanceDays, formatAmount(payslip.AttendanceEarnings)),
+			float64(payslip.AttendanceDays)*payslip.AttendanceEarnings),
+	}
+	if payslip.OvertimeEarnings != 0 {
+		lines = append(lines, row(fmt.Sprintf("Overtime (%g hours)", payslip.OvertimeHours), payslip.OvertimeEarnings))
+	}
+	if payslip.TotalReimbursement != 0 {
+		lines = append(lines, row("Reimbursements", payslip.TotalReimbursement))
+	}
+	for _, line := range payslip.Lines {
+		if line.Amount > 0 {
+			lines = append(lines, row(line.Description, line.Amount))
+		}
+	}
+
+	lines = append(lines, "", "Deductions")
+	if payslip.LateDeduction != 0 {
+		lines = append(lines, row(fmt.Sprintf("Late (%d days, %d minutes)", payslip.LateDays, payslip.LateMinutes), payslip.LateDeduction))
+	}
+	if payslip.UndertimeDeduction != 0 {
+		lines = append(lines, row(fmt.Sprintf("Undertime (%d minutes)", payslip.UndertimeMinutes), payslip.UndertimeDeduction))
+	}
+	if payslip.LoanDeduction != 0 {
+		lines = append(lines, row("Loan installments", payslip.LoanDeduction))
+	}
+	for _, line := range payslip.Lines {
+		if line.Amount < 0 {
+			lines = append(lines, row(line.Description, -line.Amount))
+		}
+	}
+
+	return append(lines, "",
+		row("Gross pay", payslip.GrossPay()),
+		row("Total deductions", payslip.Deductions()),
+		row("Take-home pay", payslip.TakeHomePay),
+	)
+}
+
+// formatAmount writes the amount with two decimals and thousands separators, e.g. 4,350,000.00.
+func formatAmount(amount float64) string {
+	formatted := fmt.Sprintf("%.2f", math.Abs(amount))
+	whole, decimals := formatted[:len(formatted)-3], formatted[len(formatted)-3:]
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if amount < 0 {
+		return "-" + grouped.String() + decimals
+	}
+	return grouped.String() + decimals
+}
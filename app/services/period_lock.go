@@ -0,0 +1,24 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/repositories"
+)
+
+// ensureUnlocked is the guard every change to attendance, overtime and reimbursement data goes
+// through. It fails with ErrPeriodLocked when any of the dates falls in a processed payroll period.
+func ensureUnlocked(repo repositories.PayrollPeriodRepository, dates ...time.Time) error {
+	for _, date := range dates {
+		day := date.Format("2006-01-02")
+		isLocked, err := repo.IsDateLocked(day)
+		if err != nil {
+			return err
+		}
+		if isLocked {
+			return fmt.Errorf("%w (%s)", ErrPeriodLocked, day)
+		}
+	}
+	return nil
+}
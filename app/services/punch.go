@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type PunchService interface {
+	IngestPunches(ctx context.Context, device *models.Device, punches []models.PunchRequest) (*models.PunchIngestResult, error)
+}
+
+type punchService struct {
+	repo              repositories.PunchRepository
+	deviceRepo        repositories.DeviceRepository
+	userRepo          repositories.UserRepository
+	payrollPeriodRepo repositories.PayrollPeriodRepository
+	attendanceService AttendanceService
+}
+
+func NewPunchService(
+	repo repositories.PunchRepository,
+	deviceRepo repositories.DeviceRepository,
+	userRepo repositories.UserRepository,
+	payrollPeriodRepo repositories.PayrollPeriodRepository,
+	attendanceService AttendanceService) PunchService {
+	return &punchService{
+		repo:              repo,
+		deviceRepo:        deviceRepo,
+		userRepo:          userRepo,
+		payrollPeriodRepo: payrollPeriodRepo,
+		attendanceService: attendanceService,
+	}
+}
+
+type punchDay struct {
+	userID uint
+	date   time.Time
+}
+
+// IngestPunches stores a batch of punches from a device and re-pairs every attendance day
+// they touch. Punches already received are counted as duplicates, but their day is still
+// re-paired, so a batch retried after a failure is applied in full. Punches with an unknown
+// employee code or inside a locked payroll period are stored without touching attendance.
+func (s *punchService) IngestPunches(ctx context.Context, device *models.Device, punches []models.PunchRequest) (*models.PunchIngestResult, error) {
+	result := &models.PunchIngestResult{Received: len(punches)}
+	users := map[string]*models.User{}
+	days := map[punchDay]*models.User{}
+
+	for _, req := range punches {
+		// A key only speaks for its own device
+		if req.DeviceID != device.Code {
+			result.Rejected++
+			continue
+		}
+
+		punch := &models.Punch{
+			DeviceID:     device.ID,
+			EmployeeCode: req.EmployeeCode,
+			PunchedAt:    req.Timestamp.Truncate(time.Second),
+			Status:       models.PunchStatusProcessed,
+		}
+		user, err := s.findUser(users, req.EmployeeCode)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			punch.Status = models.PunchStatusUnmatched
+		} else {
+			date, err := s.attendanceService.ResolveAttendanceDate(user, punch.PunchedAt)
+			if err != nil {
+				return nil, err
+			}
+			if err := ensureUnlocked(s.payrollPeriodRepo, date); errors.Is(err, ErrPeriodLocked) {
+				punch.Status = models.PunchStatusLocked
+			} else if err != nil {
+				return nil, err
+			}
+			punch.UserID = &user.ID
+			punch.AttendanceDate = &date
+		}
+
+		punchCtx := utils.WithFreshRequestID(ctx)
+		inserted, err := s.repo.CreatePunch(punchCtx, punch)
+		if err != nil {
+			return nil, err
+		}
+		if punch.Status == models.PunchStatusProcessed {
+			days[punchDay{userID: *punch.UserID, date: *punch.AttendanceDate}] = user
+		}
+
+		switch {
+		case !inserted:
+			result.Duplicates++
+		case punch.Status == models.PunchStatusUnmatched:
+			result.Unmatched++
+		case punch.Status == models.PunchStatusLocked:
+			result.Locked++
+		default:
+			result.Accepted++
+		}
+	}
+
+	for day, user := range days {
+		dayPunches, err := s.repo.GetPunchesByUserAndDate(day.userID, day.date.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		if len(dayPunches) == 0 {
+			continue
+		}
+		dayCtx := utils.WithFreshRequestID(ctx)
+		if _, err := s.attendanceService.ApplyPunches(dayCtx, user, day.date, dayPunches); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.deviceRepo.TouchDevice(device.ID, time.Now()); err != nil {
+		log.Println("failed to update device last seen:", err)
+	}
+	return result, nil
+}
+
+// findUser looks up an employee code once per batch. Unknown codes are cached as nil.
+func (s *punchService) findUser(users map[string]*models.User, code string) (*models.User, error) {
+	if user, ok := users[code]; ok {
+		return user, nil
+	}
+	user, err := s.userRepo.FindByEmployeeCode(code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	users[code] = user
+	return user, nil
+}
@@ -3,31 +3,40 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/repositories"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
 type ReimbursementService interface {
 	GetReimbursementList(userID uint, pagination utils.Pagination) ([]*models.Reimbursement, int64, error)
-	GetReimbursementByID(id uint) (*models.Reimbursement, error)
+	GetReimbursementByID(viewerID, id uint) (*models.Reimbursement, error)
 	SubmitReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error)
-	UpdateReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error)
-	DeleteReimbursement(id uint) error
+	UpdateReimbursement(ctx context.Context, viewerID uint, reimbursement *models.Reimbursement) (*models.Reimbursement, error)
+	DeleteReimbursement(viewerID, id uint) error
+	GetBudgetUsage(viewerID, userID uint, date time.Time) ([]*models.ReimbursementBudget, error)
 }
 
 type reimbursementService struct {
 	repo              repositories.ReimbursementRepository
+	categoryRepo      repositories.ReimbursementCategoryRepository
 	payrollPeriodRepo repositories.PayrollPeriodRepository
+	userRepo          repositories.UserRepository
 	cache             *redis.Client
 }
 
-func NewReimbursementService(repo repositories.ReimbursementRepository, payrollPeriodRepo repositories.PayrollPeriodRepository, cache *redis.Client) ReimbursementService {
+func NewReimbursementService(repo repositories.ReimbursementRepository, categoryRepo repositories.ReimbursementCategoryRepository, payrollPeriodRepo repositories.PayrollPeriodRepository, userRepo repositories.UserRepository, cache *redis.Client) ReimbursementService {
 	return &reimbursementService{
 		repo:              repo,
+		categoryRepo:      categoryRepo,
 		payrollPeriodRepo: payrollPeriodRepo,
+		userRepo:          userRepo,
 		cache:             cache,
 	}
 }
@@ -56,41 +65,40 @@ func (s *reimbursementService) GetReimbursementList(userID uint, pagination util
 	return reimbursements, total, nil
 }
 
-func (s *reimbursementService) GetReimbursementByID(id uint) (*models.Reimbursement, error) {
+func (s *reimbursementService) GetReimbursementByID(viewerID, id uint) (*models.Reimbursement, error) {
 	ctx := context.Background()
 	cacheKey := utils.BuildKey("reimbursement", id)
 
-	if cached, err := utils.GetCache[models.Reimbursement](ctx, s.cache, cacheKey); err == nil {
-		return cached, nil
+	reimbursement, err := utils.GetCache[models.Reimbursement](ctx, s.cache, cacheKey)
+	if err != nil {
+		// Fallback to DB
+		reimbursement, err = s.repo.GetReimbursementByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Save to cache
+		utils.SetCache(ctx, s.cache, cacheKey, reimbursement, 10)
 	}
 
-	// Fallback to DB
-	reimbursement, err := s.repo.GetReimbursementByID(id)
-	if err != nil {
+	if err := authorizeOwner(s.userRepo, viewerID, reimbursement.UserID); err != nil {
 		return nil, err
 	}
-
-	// Save to cache
-	utils.SetCache(ctx, s.cache, cacheKey, reimbursement, 10)
-
 	return reimbursement, nil
 }
 
 func (s *reimbursementService) SubmitReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error) {
+	reimbursement.Date = utils.CivilDate(reimbursement.Date)
 	if reimbursement.Amount <= 0 {
 		return nil, errors.New("reimbursement amount must be greater than zero")
 	}
 
-	isLocked, err := s.payrollPeriodRepo.IsDateLocked(reimbursement.Date.Format("2006-01-02"))
-	if err != nil {
+	if err := ensureUnlocked(s.payrollPeriodRepo, reimbursement.Date); err != nil {
 		return nil, err
 	}
-	if isLocked {
-		return nil, errors.New("reimbursement cannot be submitted for a locked payroll period")
-	}
 
-	// Create reimbursement in DB
-	createdReimbursement, err := s.repo.CreateReimbursement(ctx, reimbursement)
+	// Create reimbursement in DB, checking the budget in the same transaction
+	createdReimbursement, err := s.repo.CreateReimbursement(ctx, reimbursement, s.budgetCheck(reimbursement))
 	if err != nil {
 		return nil, err
 	}
@@ -100,40 +108,170 @@ func (s *reimbursementService) SubmitReimbursement(ctx context.Context, reimburs
 	if err != nil {
 		return nil, err
 	}
+	invalidateReports(ctx, s.cache, reportReimbursement)
 
 	return createdReimbursement, nil
 }
 
-func (s *reimbursementService) UpdateReimbursement(ctx context.Context, reimbursement *models.Reimbursement) (*models.Reimbursement, error) {
-	isLocked, err := s.payrollPeriodRepo.IsDateLocked(reimbursement.Date.Format("2006-01-02"))
+func (s *reimbursementService) UpdateReimbursement(ctx context.Context, viewerID uint, reimbursement *models.Reimbursement) (*models.Reimbursement, error) {
+	reimbursement.Date = utils.CivilDate(reimbursement.Date)
+	// Neither the current nor the new date may be in a processed period
+	existing, err := s.repo.GetReimbursementByID(reimbursement.ID)
 	if err != nil {
 		return nil, err
 	}
-	if isLocked {
-		return nil, errors.New("reimbursement cannot be submitted for a locked payroll period")
+	if err := authorizeOwner(s.userRepo, viewerID, existing.UserID); err != nil {
+		return nil, err
+	}
+	reimbursement.UserID = existing.UserID
+	if err := ensureUnlocked(s.payrollPeriodRepo, existing.Date, reimbursement.Date); err != nil {
+		return nil, err
 	}
 
-	// Update reimbursement in DB
-	updatedReimbursement, err := s.repo.UpdateReimbursement(ctx, reimbursement)
+	// Update reimbursement in DB, checking the budget in the same transaction
+	updatedReimbursement, err := s.repo.UpdateReimbursement(ctx, reimbursement, s.budgetCheck(reimbursement))
 	if err != nil {
 		return nil, err
 	}
 
 	// Invalidate cache for the user
 	err = utils.DeleteCacheByPattern(ctx, s.cache, "reimbursement:*")
+	invalidateReports(ctx, s.cache, reportReimbursement)
 
 	return updatedReimbursement, nil
 }
 
-func (s *reimbursementService) DeleteReimbursement(id uint) error {
+func (s *reimbursementService) DeleteReimbursement(viewerID, id uint) error {
 	ctx := context.Background()
 
+	existing, err := s.repo.GetReimbursementByID(id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeOwner(s.userRepo, viewerID, existing.UserID); err != nil {
+		return err
+	}
+	if err := ensureUnlocked(s.payrollPeriodRepo, existing.Date); err != nil {
+		return err
+	}
+
 	// Delete reimbursement in DB
 	if err := s.repo.DeleteReimbursement(id); err != nil {
 		return err
 	}
 
 	// Invalidate cache for the user
-	err := utils.DeleteCacheByPattern(ctx, s.cache, "reimbursement:*")
+	err = utils.DeleteCacheByPattern(ctx, s.cache, "reimbursement:*")
+	invalidateReports(ctx, s.cache, reportReimbursement)
 	return err
 }
+
+// GetBudgetUsage reports the user's usage of every active category in the month and the year of
+// the date.
+func (s *reimbursementService) GetBudgetUsage(viewerID, userID uint, date time.Time) ([]*models.ReimbursementBudget, error) {
+	if err := authorizeOwner(s.userRepo, viewerID, userID); err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.GetCategoryList(true)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := make([]*models.ReimbursementBudget, 0, len(categories))
+	for _, category := range categories {
+		budget, err := categoryBudget(s.repo, category, user, utils.CivilDate(date), 0)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets, nil
+}
+
+// budgetCheck checks the claim against what is left of its category's caps, reading the usage
+// through the repository of the transaction saving it. On update the claim's previous amount is
+// not counted as used.
+func (s *reimbursementService) budgetCheck(reimbursement *models.Reimbursement) repositories.BudgetCheck {
+	return func(repo repositories.ReimbursementRepository) error {
+		return s.ensureWithinBudget(repo, reimbursement)
+	}
+}
+
+func (s *reimbursementService) ensureWithinBudget(repo repositories.ReimbursementRepository, reimbursement *models.Reimbursement) error {
+	if reimbursement.CategoryID == nil {
+		return fmt.Errorf("%w: category_id is required", ErrReimbursementCategoryInvalid)
+	}
+	category, err := s.categoryRepo.GetCategoryByID(*reimbursement.CategoryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !category.IsActive) {
+		return ErrReimbursementCategoryInvalid
+	}
+	if err != nil {
+		return err
+	}
+	user, err := s.findUser(reimbursement.UserID)
+	if err != nil {
+		return err
+	}
+
+	budget, err := categoryBudget(repo, category, user, reimbursement.Date, reimbursement.ID)
+	if err != nil {
+		return err
+	}
+	if budget.MonthlyRemaining != nil && reimbursement.Amount > *budget.MonthlyRemaining {
+		return fmt.Errorf("%w: %s has %.2f left for %s", ErrReimbursementBudgetExceeded,
+			category.Name, *budget.MonthlyRemaining, reimbursement.Date.Format("January 2006"))
+	}
+	if budget.YearlyRemaining != nil && reimbursement.Amount > *budget.YearlyRemaining {
+		return fmt.Errorf("%w: %s has %.2f left for %d", ErrReimbursementBudgetExceeded,
+			category.Name, *budget.YearlyRemaining, reimbursement.Date.Year())
+	}
+	return nil
+}
+
+func categoryBudget(repo repositories.ReimbursementRepository, category *models.ReimbursementCategory, user *models.User, date time.Time, excludeID uint) (*models.ReimbursementBudget, error) {
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthlyUsed, err := repo.SumCategoryUsage(user.ID, category.ID,
+		monthStart.Format("2006-01-02"), monthStart.AddDate(0, 1, -1).Format("2006-01-02"), excludeID)
+	if err != nil {
+		return nil, err
+	}
+	yearlyUsed, err := repo.SumCategoryUsage(user.ID, category.ID,
+		fmt.Sprintf("%04d-01-01", date.Year()), fmt.Sprintf("%04d-12-31", date.Year()), excludeID)
+	if err != nil {
+		return nil, err
+	}
+
+	monthlyCap, yearlyCap := category.CapsFor(user)
+	return &models.ReimbursementBudget{
+		CategoryID:       category.ID,
+		Category:         category.Name,
+		MonthlyCap:       monthlyCap,
+		MonthlyUsed:      monthlyUsed,
+		MonthlyRemaining: remainingBudget(monthlyCap, monthlyUsed),
+		YearlyCap:        yearlyCap,
+		YearlyUsed:       yearlyUsed,
+		YearlyRemaining:  remainingBudget(yearlyCap, yearlyUsed),
+	}, nil
+}
+
+func (s *reimbursementService) findUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user == nil) {
+		return nil, fmt.Errorf("user %d not found", userID)
+	}
+	return user, err
+}
+
+// remainingBudget is nil for an unlimited cap and never negative, even when a lowered cap is
+// already overspent.
+func remainingBudget(limit *float64, used float64) *float64 {
+	if limit == nil {
+		return nil
+	}
+	remaining := math.Max(math.Round((*limit-used)*100)/100, 0)
+	return &remaining
+}
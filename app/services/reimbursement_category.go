@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrReimbursementCategoryInvalid is returned when a claim names an unknown or inactive category.
+	ErrReimbursementCategoryInvalid = errors.New("reimbursement category not found or inactive")
+	// ErrReimbursementBudgetExceeded is returned when a claim is larger than what is left of its
+	// category's monthly or yearly cap.
+	ErrReimbursementBudgetExceeded = errors.New("reimbursement exceeds the remaining budget")
+)
+
+type ReimbursementCategoryService interface {
+	GetCategoryList(activeOnly bool) ([]*models.ReimbursementCategory, error)
+	CreateCategory(ctx context.Context, req *models.ReimbursementCategoryRequest) (*models.ReimbursementCategory, error)
+	UpdateCategory(ctx context.Context, id uint, req *models.ReimbursementCategoryRequest) (*models.ReimbursementCategory, error)
+	CreateLimit(ctx context.Context, categoryID uint, req *models.ReimbursementLimitRequest) (*models.ReimbursementLimit, error)
+	DeleteLimit(categoryID, limitID uint) error
+}
+
+type reimbursementCategoryService struct {
+	repo     repositories.ReimbursementCategoryRepository
+	userRepo repositories.UserRepository
+}
+
+func NewReimbursementCategoryService(repo repositories.ReimbursementCategoryRepository, userRepo repositories.UserRepository) ReimbursementCategoryService {
+	return &reimbursementCategoryService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *reimbursementCategoryService) GetCategoryList(activeOnly bool) ([]*models.ReimbursementCategory, error) {
+	return s.repo.GetCategoryList(activeOnly)
+}
+
+func (s *reimbursementCategoryService) CreateCategory(ctx context.Context, req *models.ReimbursementCategoryRequest) (*models.ReimbursementCategory, error) {
+	category := &models.ReimbursementCategory{IsActive: true}
+	applyCategoryRequest(category, req)
+	return s.repo.CreateCategory(ctx, category)
+}
+
+// UpdateCategory changes a category's default caps. Claims already submitted are kept even when
+// they no longer fit.
+func (s *reimbursementCategoryService) UpdateCategory(ctx context.Context, id uint, req *models.ReimbursementCategoryRequest) (*models.ReimbursementCategory, error) {
+	category, err := s.repo.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	applyCategoryRequest(category, req)
+	return s.repo.UpdateCategory(ctx, category)
+}
+
+// CreateLimit overrides the category's caps for a single employee or for a grade.
+func (s *reimbursementCategoryService) CreateLimit(ctx context.Context, categoryID uint, req *models.ReimbursementLimitRequest) (*models.ReimbursementLimit, error) {
+	if (req.UserID == nil) == (req.Grade == nil) {
+		return nil, errors.New("exactly one of user_id or grade is required")
+	}
+	category, err := s.repo.GetCategoryByID(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	for _, limit := range category.Limits {
+		if req.UserID != nil && limit.UserID != nil && *limit.UserID == *req.UserID {
+			return nil, fmt.Errorf("user %d already has a limit in this category", *req.UserID)
+		}
+		if req.Grade != nil && limit.Grade != nil && *limit.Grade == *req.Grade {
+			return nil, fmt.Errorf("grade %s already has a limit in this category", *req.Grade)
+		}
+	}
+	if req.UserID != nil {
+		user, err := s.userRepo.FindByID(*req.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user == nil) {
+			return nil, fmt.Errorf("user %d not found", *req.UserID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.CreateLimit(ctx, &models.ReimbursementLimit{
+		CategoryID: categoryID,
+		UserID:     req.UserID,
+		Grade:      req.Grade,
+		MonthlyCap: req.MonthlyCap,
+		YearlyCap:  req.YearlyCap,
+	})
+}
+
+func (s *reimbursementCategoryService) DeleteLimit(categoryID, limitID uint) error {
+	limit, err := s.repo.GetLimitByID(limitID)
+	if err != nil {
+		return err
+	}
+	if limit.CategoryID != categoryID {
+		return gorm.ErrRecordNotFound
+	}
+	return s.repo.DeleteLimit(limitID)
+}
+
+func applyCategoryRequest(category *models.ReimbursementCategory, req *models.ReimbursementCategoryRequest) {
+	category.Name = req.Name
+	category.MonthlyCap = req.MonthlyCap
+	category.YearlyCap = req.YearlyCap
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+}
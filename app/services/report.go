@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	maxReportDays  = 366
+	reportCacheTTL = 30 * time.Minute
+)
+
+// Cache key prefixes of the reports, one per kind of data they are built from.
+const (
+	reportAttendance    = "report:attendance"
+	reportOvertime      = "report:overtime"
+	reportPayroll       = "report:payroll"
+	reportReimbursement = "report:reimbursement"
+)
+
+// ErrInvalidReportRange is returned when a report date range is reversed or too long.
+var ErrInvalidReportRange = errors.New("end_date must not be before start_date and the range cannot exceed 366 days")
+
+// invalidateReports drops the cached reports built from data that just changed. Failures are
+// logged by the cache helpers and never fail the write itself.
+func invalidateReports(ctx context.Context, cache *redis.Client, reports ...string) {
+	for _, report := range reports {
+		_ = utils.DeleteCacheByPattern(ctx, cache, report+":*")
+	}
+}
+
+type ReportService interface {
+	GetAttendanceRateReport(startDate, endDate time.Time) (*models.AttendanceRateReport, error)
+	GetTopOvertimeUsers(startDate, endDate time.Time, limit int) ([]*models.OvertimeUserReport, error)
+	GetOvertimeCostTrend(startDate, endDate time.Time) ([]*models.OvertimeCostPoint, error)
+	GetPayrollCostByPeriod(startDate, endDate time.Time) ([]*models.PayrollPeriodCost, error)
+	GetReimbursementSpendByMonth(startDate, endDate time.Time) ([]*models.MonthlySpend, error)
+}
+
+type reportService struct {
+	repo      repositories.ReportRepository
+	leaveRepo repositories.LeaveRepository
+	cache     *redis.Client
+}
+
+func NewReportService(repo repositories.ReportRepository, leaveRepo repositories.LeaveRepository, cache *redis.Client) ReportService {
+	return &reportService{
+		repo:      repo,
+		leaveRepo: leaveRepo,
+		cache:     cache,
+	}
+}
+
+// GetAttendanceRateReport compares attended days with expected days per employee and per team.
+// Days after today are not expected yet, so the cached report is keyed by the last expected day
+// and a range reaching into the future is rebuilt once the day changes.
+func (s *reportService) GetAttendanceRateReport(startDate, endDate time.Time) (*models.AttendanceRateReport, error) {
+	start, end, err := reportRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	expectedEnd := utils.CivilDate(endDate)
+	if today := utils.DateIn(time.Now(), utils.CompanyLocation()); today.Before(expectedEnd) {
+		expectedEnd = today
+	}
+	ctx := context.Background()
+	cacheKey := utils.BuildKey(reportAttendance, start, end, expectedEnd.Format("2006-01-02"))
+	if cached, err := utils.GetCache[models.AttendanceRateReport](ctx, s.cache, cacheKey); err == nil {
+		return cached, nil
+	}
+
+	employees, err := s.repo.GetAttendanceCounts(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.AttendanceRateReport{StartDate: start, EndDate: end, Employees: employees}
+	if !expectedEnd.Before(utils.CivilDate(startDate)) {
+		if err := s.countExpectedDays(employees, utils.CivilDate(startDate), expectedEnd); err != nil {
+			return nil, err
+		}
+	}
+
+	teams := map[uint]*models.TeamAttendanceRate{}
+	var expected, attended int
+	for _, employee := range employees {
+		employee.AttendanceRate = attendanceRate(employee.AttendedDays, employee.ExpectedDays)
+		expected += employee.ExpectedDays
+		attended += employee.AttendedDays
+
+		var key uint
+		if employee.ManagerID != nil {
+			key = *employee.ManagerID
+		}
+		team, ok := teams[key]
+		if !ok {
+			team = &models.TeamAttendanceRate{ManagerID: employee.ManagerID, ManagerName: employee.ManagerName}
+			teams[key] = team
+			report.Teams = append(report.Teams, team)
+		}
+		team.Members++
+		team.ExpectedDays += employee.ExpectedDays
+		team.AttendedDays += employee.AttendedDays
+	}
+	for _, team := range report.Teams {
+		team.AttendanceRate = attendanceRate(team.AttendedDays, team.ExpectedDays)
+	}
+	report.AttendanceRate = attendanceRate(attended, expected)
+
+	utils.SetCache(ctx, s.cache, cacheKey, report, reportCacheTTL)
+	return report, nil
+}
+
+// countExpectedDays sets the weekdays plus rostered weekend shifts of each employee, minus leave taken on weekdays.
+func (s *reportService) countExpectedDays(employees []*models.EmployeeAttendanceRate, start, end time.Time) error {
+	startDate, endDate := start.Format("2006-01-02"), end.Format("2006-01-02")
+	weekendShifts, err := s.repo.GetWeekendShiftCounts(startDate, endDate)
+	if err != nil {
+		return err
+	}
+	leaves, err := s.leaveRepo.GetLeaveList(0, startDate, endDate)
+	if err != nil {
+		return err
+	}
+	leaveDays := map[uint]int{}
+	for _, leave := range leaves {
+		from, to := leave.StartDate, leave.EndDate
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		leaveDays[leave.UserID] += utils.CountWorkingDays(from, to)
+	}
+
+	workingDays := utils.CountWorkingDays(start, end)
+	for _, employee := range employees {
+		employee.LeaveDays = leaveDays[employee.UserID]
+		employee.ExpectedDays = max(workingDays+weekendShifts[employee.UserID]-employee.LeaveDays, 0)
+	}
+	return nil
+}
+
+func (s *reportService) GetTopOvertimeUsers(startDate, endDate time.Time, limit int) ([]*models.OvertimeUserReport, error) {
+	start, end, err := reportRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	cacheKey := utils.BuildKey(reportOvertime, start, end, limit)
+	if cached, err := utils.GetCache[[]*models.OvertimeUserReport](ctx, s.cache, cacheKey); err == nil {
+		return *cached, nil
+	}
+
+	users, err := s.repo.GetTopOvertimeUsers(start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetCache(ctx, s.cache, cacheKey, &users, reportCacheTTL)
+	return users, nil
+}
+
+// GetOvertimeCostTrend reports the overtime paid in each processed payroll period and how it changed.
+func (s *reportService) GetOvertimeCostTrend(startDate, endDate time.Time) ([]*models.OvertimeCostPoint, error) {
+	periods, err := s.GetPayrollCostByPeriod(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := make([]*models.OvertimeCostPoint, 0, len(periods))
+	for i, period := range periods {
+		point := &models.OvertimeCostPoint{
+			PayrollPeriodID: period.PayrollPeriodID,
+			StartDate:       period.StartDate,
+			EndDate:         period.EndDate,
+			OvertimeHours:   period.OvertimeHours,
+			OvertimeCost:    period.OvertimeEarnings,
+		}
+		if i > 0 && periods[i-1].OvertimeEarnings > 0 {
+			change := math.Round((period.OvertimeEarnings/periods[i-1].OvertimeEarnings-1)*10000) / 100
+			point.Change = &change
+		}
+		trend = append(trend, point)
+	}
+	return trend, nil
+}
+
+func (s *reportService) GetPayrollCostByPeriod(startDate, endDate time.Time) ([]*models.PayrollPeriodCost, error) {
+	start, end, err := reportRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	cacheKey := utils.BuildKey(reportPayroll, start, end)
+	if cached, err := utils.GetCache[[]*models.PayrollPeriodCost](ctx, s.cache, cacheKey); err == nil {
+		return *cached, nil
+	}
+
+	periods, err := s.repo.GetPayrollCostByPeriod(start, end)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetCache(ctx, s.cache, cacheKey, &periods, reportCacheTTL)
+	return periods, nil
+}
+
+func (s *reportService) GetReimbursementSpendByMonth(startDate, endDate time.Time) ([]*models.MonthlySpend, error) {
+	start, end, err := reportRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	cacheKey := utils.BuildKey(reportReimbursement, start, end)
+	if cached, err := utils.GetCache[[]*models.MonthlySpend](ctx, s.cache, cacheKey); err == nil {
+		return *cached, nil
+	}
+
+	spend, err := s.repo.GetReimbursementSpendByMonth(start, end)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetCache(ctx, s.cache, cacheKey, &spend, reportCacheTTL)
+	return spend, nil
+}
+
+// reportRange validates a report date range and formats it for the queries.
+func reportRange(startDate, endDate time.Time) (string, string, error) {
+	start, end := utils.CivilDate(startDate), utils.CivilDate(endDate)
+	if end.Before(start) || end.Sub(start) > maxReportDays*24*time.Hour {
+		return "", "", ErrInvalidReportRange
+	}
+	return start.Format("2006-01-02"), end.Format("2006-01-02"), nil
+}
+
+// attendanceRate is the attended share of the expected days, as a percentage.
+func attendanceRate(attended, expected int) float64 {
+	if expected == 0 {
+		return 0
+	}
+	return math.Round(float64(attended)/float64(expected)*10000) / 100
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+const maxRosterDays = 92
+
+type ShiftService interface {
+	GetShiftList(pagination utils.Pagination) ([]*models.Shift, int64, error)
+	GetShiftByID(id uint) (*models.Shift, error)
+	CreateShift(ctx context.Context, req *models.ShiftRequest) (*models.Shift, error)
+	UpdateShift(ctx context.Context, id uint, req *models.ShiftRequest) (*models.Shift, error)
+	DeleteShift(id uint) error
+	GetAssignmentList(userID uint, startDate, endDate string) ([]*models.ShiftAssignment, error)
+	AssignShift(ctx context.Context, req *models.ShiftAssignmentRequest) ([]*models.ShiftAssignment, error)
+	DeleteAssignment(id uint) error
+}
+
+type shiftService struct {
+	repo  repositories.ShiftRepository
+	cache *redis.Client
+}
+
+func NewShiftService(repo repositories.ShiftRepository, cache *redis.Client) ShiftService {
+	return &shiftService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+func (s *shiftService) GetShiftList(pagination utils.Pagination) ([]*models.Shift, int64, error) {
+	return s.repo.GetShiftList(pagination)
+}
+
+func (s *shiftService) GetShiftByID(id uint) (*models.Shift, error) {
+	return s.repo.GetShiftByID(id)
+}
+
+func (s *shiftService) CreateShift(ctx context.Context, req *models.ShiftRequest) (*models.Shift, error) {
+	shift := &models.Shift{}
+	if err := applyShiftRequest(shift, req); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateShift(ctx, shift)
+}
+
+func (s *shiftService) UpdateShift(ctx context.Context, id uint, req *models.ShiftRequest) (*models.Shift, error) {
+	shift, err := s.repo.GetShiftByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyShiftRequest(shift, req); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateShift(ctx, shift)
+}
+
+func (s *shiftService) DeleteShift(id uint) error {
+	return s.repo.DeleteShift(id)
+}
+
+func (s *shiftService) GetAssignmentList(userID uint, startDate, endDate string) ([]*models.ShiftAssignment, error) {
+	return s.repo.GetAssignmentList(userID, startDate, endDate)
+}
+
+func (s *shiftService) AssignShift(ctx context.Context, req *models.ShiftAssignmentRequest) ([]*models.ShiftAssignment, error) {
+	startDate, endDate := utils.CivilDate(req.StartDate), utils.CivilDate(req.EndDate)
+	if endDate.Before(startDate) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+	if endDate.Sub(startDate) > maxRosterDays*24*time.Hour {
+		return nil, errors.New("roster range cannot exceed 92 days")
+	}
+	if _, err := s.repo.GetShiftByID(req.ShiftID); err != nil {
+		return nil, errors.New("shift not found")
+	}
+
+	var assignments []*models.ShiftAssignment
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		if !req.IncludeWeekends && utils.IsWeekend(d) {
+			continue
+		}
+		rowCtx := utils.WithFreshRequestID(ctx)
+		assignment, err := s.repo.UpsertAssignment(rowCtx, &models.ShiftAssignment{
+			UserID:  req.UserID,
+			ShiftID: req.ShiftID,
+			Date:    d,
+		})
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+	// Weekend shifts count as expected days in the attendance reports
+	invalidateReports(ctx, s.cache, reportAttendance)
+	return assignments, nil
+}
+
+func (s *shiftService) DeleteAssignment(id uint) error {
+	if err := s.repo.DeleteAssignment(id); err != nil {
+		return err
+	}
+	invalidateReports(context.Background(), s.cache, reportAttendance)
+	return nil
+}
+
+func applyShiftRequest(shift *models.Shift, req *models.ShiftRequest) error {
+	start, err := time.Parse("15:04", req.StartTime)
+	if err != nil {
+		return errors.New("invalid start_time, expected HH:MM")
+	}
+	end, err := time.Parse("15:04", req.EndTime)
+	if err != nil {
+		return errors.New("invalid end_time, expected HH:MM")
+	}
+	if start.Equal(end) {
+		return errors.New("start_time and end_time cannot be equal")
+	}
+
+	shift.Name = req.Name
+	shift.StartTime = req.StartTime
+	shift.EndTime = req.EndTime
+	shift.GracePeriodMinutes = req.GracePeriodMinutes
+	shift.CrossesMidnight = end.Before(start)
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+)
+
+type SiteService interface {
+	GetSiteList(pagination utils.Pagination) ([]*models.Site, int64, error)
+	GetSiteByID(id uint) (*models.Site, error)
+	CreateSite(ctx context.Context, req *models.SiteRequest) (*models.Site, error)
+	UpdateSite(ctx context.Context, id uint, req *models.SiteRequest) (*models.Site, error)
+	DeleteSite(id uint) error
+}
+
+type siteService struct {
+	repo repositories.SiteRepository
+}
+
+func NewSiteService(repo repositories.SiteRepository) SiteService {
+	return &siteService{
+		repo: repo,
+	}
+}
+
+func (s *siteService) GetSiteList(pagination utils.Pagination) ([]*models.Site, int64, error) {
+	return s.repo.GetSiteList(pagination)
+}
+
+func (s *siteService) GetSiteByID(id uint) (*models.Site, error) {
+	return s.repo.GetSiteByID(id)
+}
+
+func (s *siteService) CreateSite(ctx context.Context, req *models.SiteRequest) (*models.Site, error) {
+	site := &models.Site{IsActive: true}
+	if err := applySiteRequest(site, req); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateSite(ctx, site)
+}
+
+func (s *siteService) UpdateSite(ctx context.Context, id uint, req *models.SiteRequest) (*models.Site, error) {
+	site, err := s.repo.GetSiteByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applySiteRequest(site, req); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateSite(ctx, site)
+}
+
+func (s *siteService) DeleteSite(id uint) error {
+	return s.repo.DeleteSite(id)
+}
+
+func applySiteRequest(site *models.Site, req *models.SiteRequest) error {
+	site.Name = req.Name
+	site.Type = req.Type
+	if req.Timezone != nil && *req.Timezone != "" {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return errors.New("invalid timezone")
+		}
+	}
+	site.Timezone = req.Timezone
+	if req.IsActive != nil {
+		site.IsActive = *req.IsActive
+	}
+
+	switch req.Type {
+	case models.GeofenceCircle:
+		if req.Latitude == nil || req.Longitude == nil || req.RadiusMeters == nil {
+			return errors.New("circle site requires latitude, longitude and radius_meters")
+		}
+		site.Latitude = req.Latitude
+		site.Longitude = req.Longitude
+		site.RadiusMeters = req.RadiusMeters
+		site.Polygon = nil
+	case models.GeofencePolygon:
+		if len(req.Polygon) < 3 {
+			return errors.New("polygon site requires at least 3 points")
+		}
+		encoded, err := json.Marshal(req.Polygon)
+		if err != nil {
+			return err
+		}
+		polygon := string(encoded)
+		site.Polygon = &polygon
+		site.Latitude = nil
+		site.Longitude = nil
+		site.RadiusMeters = nil
+	default:
+		return errors.New("invalid site type")
+	}
+	return nil
+}
+
+// matchSite returns the first active site containing the given coordinate, or nil if none does.
+func matchSite(sites []*models.Site, lat, lng float64) *models.Site {
+	for _, site := range sites {
+		switch site.Type {
+		case models.GeofenceCircle:
+			if site.Latitude == nil || site.Longitude == nil || site.RadiusMeters == nil {
+				continue
+			}
+			if utils.DistanceMeters(lat, lng, *site.Latitude, *site.Longitude) <= *site.RadiusMeters {
+				return site
+			}
+		case models.GeofencePolygon:
+			points, err := site.PolygonPoints()
+			if err != nil || len(points) < 3 {
+				continue
+			}
+			polygon := make([][2]float64, 0, len(points))
+			for _, p := range points {
+				polygon = append(polygon, [2]float64{p.Latitude, p.Longitude})
+			}
+			if utils.IsPointInPolygon(lat, lng, polygon) {
+				return site
+			}
+		}
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/galiherlangga/go-attendance/app/models"
 	"github.com/galiherlangga/go-attendance/app/repositories"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
@@ -10,6 +14,9 @@ import (
 type UserService interface {
 	LoginUser(input *models.LoginRequest) (string, string, error)
 	IsAdmin(userID uint) (bool, error)
+	AssignManager(ctx context.Context, id uint, managerID *uint) (*models.User, error)
+	SetTimezone(ctx context.Context, id uint, timezone *string) (*models.User, error)
+	SetGrade(ctx context.Context, id uint, grade *string) (*models.User, error)
 }
 
 type userService struct {
@@ -51,4 +58,58 @@ func (s *userService) IsAdmin(userID uint) (bool, error) {
 		return false, err // User not found or other error
 	}
 	return user.Role.Name == "admin", nil
-}
\ No newline at end of file
+}
+
+// AssignManager sets the manager of a user, or removes it when managerID is nil. The manager
+// must be another user with the manager role.
+func (s *userService) AssignManager(ctx context.Context, id uint, managerID *uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if managerID != nil {
+		if *managerID == user.ID {
+			return nil, errors.New("a user cannot be their own manager")
+		}
+		manager, err := s.userRepo.FindByID(*managerID)
+		if err != nil || manager == nil {
+			return nil, errors.New("manager not found")
+		}
+		if manager.Role.Name != models.RoleManager {
+			return nil, errors.New("manager must have the manager role")
+		}
+	}
+	user.ManagerID = managerID
+	return s.userRepo.UpdateUser(ctx, user)
+}
+
+// SetTimezone sets the IANA timezone of a user, or clears it when empty.
+func (s *userService) SetTimezone(ctx context.Context, id uint, timezone *string) (*models.User, error) {
+	if timezone != nil && *timezone == "" {
+		timezone = nil
+	}
+	if timezone != nil {
+		if _, err := time.LoadLocation(*timezone); err != nil {
+			return nil, errors.New("invalid timezone")
+		}
+	}
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	user.Timezone = timezone
+	return s.userRepo.UpdateUser(ctx, user)
+}
+
+// SetGrade sets the grade of a user, or clears it when empty.
+func (s *userService) SetGrade(ctx context.Context, id uint, grade *string) (*models.User, error) {
+	if grade != nil && *grade == "" {
+		grade = nil
+	}
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	user.Grade = grade
+	return s.userRepo.UpdateUser(ctx, user)
+}
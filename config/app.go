@@ -1,13 +1,26 @@
 package config
 
+import "time"
+
 type AppConfig struct {
 	Port string
 	Host string
+	// Timezone is the company timezone used to bucket attendance and payroll dates. When it is
+	// not set, the timezone of the server the app is deployed on is used.
+	Timezone string
 }
 
 func LoadAppConfig() AppConfig {
 	return AppConfig{
 		Port: GetEnv("APP_PORT", "8080"),
 		Host: GetEnv("APP_HOST", "0.0.0.0"),
+		Timezone: GetEnv("APP_TIMEZONE", ""),
+	}
+}
+
+func (c AppConfig) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
 	}
+	return time.LoadLocation(c.Timezone)
 }
\ No newline at end of file
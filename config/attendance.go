@@ -0,0 +1,55 @@
+package config
+
+import (
+	"time"
+)
+
+const (
+	GeofenceModeReject = "reject"
+	GeofenceModeFlag   = "flag"
+
+	CloseOutPolicyAutoClose  = "auto_close"
+	CloseOutPolicyIncomplete = "incomplete"
+	CloseOutPolicyNotify     = "notify"
+)
+
+type AttendanceConfig struct {
+	// GeofenceMode decides what happens when a check-in falls outside every site:
+	// "reject" refuses the check-in, "flag" records it with IsOutsideGeofence set.
+	GeofenceMode string
+	// GeofenceMaxAccuracy is the worst GPS accuracy (in meters) we still trust.
+	GeofenceMaxAccuracy float64
+	// ShiftEarlyCheckIn is how long before a shift starts an employee may check in to it.
+	ShiftEarlyCheckIn time.Duration
+	// ShiftLateCheckOut is how long after a shift ends an employee may still check out of it.
+	ShiftLateCheckOut time.Duration
+	// Default schedule used for lateness tracking when no shift is rostered.
+	DefaultShiftStart       string
+	DefaultShiftEnd         string
+	DefaultShiftGracePeriod int
+	// CloseOutPolicy decides how attendances left without a check-out are handled:
+	// "auto_close" checks them out at shift end, "incomplete" only marks them, and
+	// "notify" marks them and asks the employee to submit a correction.
+	CloseOutPolicy   string
+	CloseOutInterval time.Duration
+	// PunchDebounce ignores repeated terminal punches of the same employee within this window.
+	PunchDebounce time.Duration
+	// KioskTokenTTL is how long a QR token shown on a kiosk stays valid.
+	KioskTokenTTL time.Duration
+}
+
+func LoadAttendanceConfig() AttendanceConfig {
+	return AttendanceConfig{
+		GeofenceMode:            GetEnv("GEOFENCE_MODE", GeofenceModeReject),
+		GeofenceMaxAccuracy:     GetEnvFloat("GEOFENCE_MAX_ACCURACY", 100),
+		ShiftEarlyCheckIn:       time.Duration(GetEnvInt("SHIFT_EARLY_CHECK_IN_MINUTES", 120)) * time.Minute,
+		ShiftLateCheckOut:       time.Duration(GetEnvInt("SHIFT_LATE_CHECK_OUT_MINUTES", 240)) * time.Minute,
+		DefaultShiftStart:       GetEnv("DEFAULT_SHIFT_START", "09:00"),
+		DefaultShiftEnd:         GetEnv("DEFAULT_SHIFT_END", "17:00"),
+		DefaultShiftGracePeriod: GetEnvInt("DEFAULT_SHIFT_GRACE_MINUTES", 0),
+		CloseOutPolicy:          GetEnv("CLOSE_OUT_POLICY", CloseOutPolicyNotify),
+		CloseOutInterval:        time.Duration(GetEnvInt("CLOSE_OUT_INTERVAL_MINUTES", 60)) * time.Minute,
+		PunchDebounce:           time.Duration(GetEnvInt("PUNCH_DEBOUNCE_SECONDS", 60)) * time.Second,
+		KioskTokenTTL:           time.Duration(GetEnvInt("KIOSK_TOKEN_TTL_SECONDS", 30)) * time.Second,
+	}
+}
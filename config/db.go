@@ -10,8 +10,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// The database session runs in UTC: timestamps are stored as absolute instants and dates
+// as plain calendar dates, while the company timezone is applied in the application.
 func InitDB() (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai",
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
 		GetEnv("DB_HOST", "localhost"),
 		GetEnv("DB_USER", "gorm"),
 		GetEnv("DB_PASS", "gorm"),
@@ -35,7 +37,7 @@ func InitTestDB() (*gorm.DB, error) {
 		log.Printf("Failed to load .env file: %v\n", err)
 		return nil, err
 	}
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai",
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
 		GetEnv("TEST_DB_HOST", "localhost"),
 		GetEnv("TEST_DB_USER", "gorm"),
 		GetEnv("TEST_DB_PASS", "gorm"),
@@ -0,0 +1,55 @@
+package config
+
+import "time"
+
+type SMTPConfig struct {
+	Host string
+	Port string
+	// Username and Password are optional; local sinks such as MailHog accept mail without them.
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+func LoadSMTPConfig() SMTPConfig {
+	return SMTPConfig{
+		Host:     GetEnv("SMTP_HOST", "localhost"),
+		Port:     GetEnv("SMTP_PORT", "1025"),
+		Username: GetEnv("SMTP_USERNAME", ""),
+		Password: GetEnv("SMTP_PASSWORD", ""),
+		From:     GetEnv("SMTP_FROM", "payroll@localhost"),
+		Timeout:  time.Duration(GetEnvInt("SMTP_TIMEOUT_SECONDS", 10)) * time.Second,
+	}
+}
+
+type PayslipEmailConfig struct {
+	// Enabled emails every employee their payslip after a payroll period is run.
+	Enabled bool
+	// AttachPDF sends the payslip as a PDF attachment rather than in the message body. With
+	// ProtectPDF the attachment opens only with a password derived from PDFSecret and the
+	// employee's ID, which employees look up in the app. Without a secret no protected PDF can
+	// be made, so employees are asked to view the payslip in the app instead. Changing the
+	// secret changes every password.
+	AttachPDF  bool
+	ProtectPDF bool
+	PDFSecret  string
+	// A failed email is retried after RetryInterval, doubling every time, until it has been
+	// tried MaxAttempts times.
+	MaxAttempts   int
+	RetryInterval time.Duration
+	// SendInterval is how often queued emails are sent.
+	SendInterval time.Duration
+}
+
+func LoadPayslipEmailConfig() PayslipEmailConfig {
+	return PayslipEmailConfig{
+		Enabled:       GetEnvBool("PAYSLIP_EMAIL_ENABLED", false),
+		AttachPDF:     GetEnvBool("PAYSLIP_EMAIL_ATTACH_PDF", true),
+		ProtectPDF:    GetEnvBool("PAYSLIP_EMAIL_PROTECT_PDF", true),
+		PDFSecret:     GetEnv("PAYSLIP_EMAIL_PDF_SECRET", ""),
+		MaxAttempts:   GetEnvInt("PAYSLIP_EMAIL_MAX_ATTEMPTS", 5),
+		RetryInterval: time.Duration(GetEnvInt("PAYSLIP_EMAIL_RETRY_MINUTES", 5)) * time.Minute,
+		SendInterval:  time.Duration(GetEnvInt("PAYSLIP_EMAIL_SEND_INTERVAL_SECONDS", 60)) * time.Second,
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+const (
+	OvertimeDeriveOff     = "off"
+	OvertimeDerivePropose = "propose"
+	OvertimeDeriveSubmit  = "submit"
+)
+
+type OvertimeConfig struct {
+	// DeriveMode controls overtime derived from attendance at check-out:
+	// "off" disables it, "propose" waits for the employee to confirm,
+	// "submit" records it as submitted straight away.
+	DeriveMode string
+	// Caps on submitted overtime hours per day, per week (Monday to Sunday) and
+	// per calendar month. Zero disables the weekly or monthly cap.
+	MaxDailyHours   int
+	MaxWeeklyHours  int
+	MaxMonthlyHours int
+}
+
+func LoadOvertimeConfig() OvertimeConfig {
+	return OvertimeConfig{
+		DeriveMode:      GetEnv("OVERTIME_DERIVE_MODE", OvertimeDerivePropose),
+		MaxDailyHours:   GetEnvInt("OVERTIME_MAX_DAILY_HOURS", 3),
+		MaxWeeklyHours:  GetEnvInt("OVERTIME_MAX_WEEKLY_HOURS", 14),
+		MaxMonthlyHours: GetEnvInt("OVERTIME_MAX_MONTHLY_HOURS", 0),
+	}
+}
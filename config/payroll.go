@@ -0,0 +1,99 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+)
+
+const (
+	PayrollFrequencyMonthly     = "monthly"
+	PayrollFrequencySemiMonthly = "semi_monthly"
+	PayrollFrequencyBiWeekly    = "bi_weekly"
+)
+
+type PayrollConfig struct {
+	// LatePenaltyPerMinute is deducted for every minute an employee is late.
+	LatePenaltyPerMinute float64
+	// LatePenaltyPerOccurrence is a flat amount deducted for every late day.
+	LatePenaltyPerOccurrence float64
+	// DeductUndertime deducts unworked scheduled hours at the hourly rate.
+	DeductUndertime bool
+	// Overtime pay multipliers on work days, rest days (weekends without a rostered
+	// shift) and holidays, written as "hours:multiplier" tiers.
+	OvertimeWeekdayTiers []utils.OvertimeTier
+	OvertimeRestDayTiers []utils.OvertimeTier
+	OvertimeHolidayTiers []utils.OvertimeTier
+	// Meal and transport allowances paid per eligible attended day. Zero turns them off.
+	MealAllowancePerDay      float64
+	TransportAllowancePerDay float64
+	// A day earns the daily allowances only when the employee was late at most
+	// DailyAllowanceMaxLateMinutes, and, when required, worked the full scheduled hours and
+	// checked in at an office site, inside its geofence.
+	DailyAllowanceMaxLateMinutes   int
+	DailyAllowanceRequireFullHours bool
+	DailyAllowanceRequireOnSite    bool
+}
+
+func LoadPayrollConfig() PayrollConfig {
+	return PayrollConfig{
+		LatePenaltyPerMinute:           GetEnvFloat("LATE_PENALTY_PER_MINUTE", 0),
+		LatePenaltyPerOccurrence:       GetEnvFloat("LATE_PENALTY_PER_OCCURRENCE", 0),
+		DeductUndertime:                GetEnvBool("DEDUCT_UNDERTIME", false),
+		OvertimeWeekdayTiers:           GetEnvOvertimeTiers("OVERTIME_WEEKDAY_RATES", "1:1.5,0:2"),
+		OvertimeRestDayTiers:           GetEnvOvertimeTiers("OVERTIME_REST_DAY_RATES", "8:2,1:3,0:4"),
+		OvertimeHolidayTiers:           GetEnvOvertimeTiers("OVERTIME_HOLIDAY_RATES", "8:2,1:3,0:4"),
+		MealAllowancePerDay:            GetEnvFloat("MEAL_ALLOWANCE_PER_DAY", 0),
+		TransportAllowancePerDay:       GetEnvFloat("TRANSPORT_ALLOWANCE_PER_DAY", 0),
+		DailyAllowanceMaxLateMinutes:   GetEnvInt("DAILY_ALLOWANCE_MAX_LATE_MINUTES", 0),
+		DailyAllowanceRequireFullHours: GetEnvBool("DAILY_ALLOWANCE_REQUIRE_FULL_HOURS", true),
+		DailyAllowanceRequireOnSite:    GetEnvBool("DAILY_ALLOWANCE_REQUIRE_ON_SITE", true),
+	}
+}
+
+// GetEnvOvertimeTiers parses overtime tiers from the environment, falling back to the
+// default tiers when the value is missing or malformed.
+func GetEnvOvertimeTiers(key string, fallback string) []utils.OvertimeTier {
+	tiers, err := utils.ParseOvertimeTiers(GetEnv(key, fallback))
+	if err != nil {
+		log.Printf("Invalid %s, using %s: %v", key, fallback, err)
+		tiers, _ = utils.ParseOvertimeTiers(fallback)
+	}
+	return tiers
+}
+
+type PayrollCalendarConfig struct {
+	// Frequency is how often payroll runs: "monthly", "semi_monthly" or "bi_weekly".
+	Frequency string
+	// CutoffDay is the last day of a monthly period, or of the first half of a semi-monthly
+	// month. Zero means the end of the month for monthly and the 15th for semi-monthly.
+	CutoffDay int
+	// AnchorDate is the first day of one bi-weekly period; the others follow every 14 days.
+	AnchorDate time.Time
+	// PeriodsAhead is how many periods after the current one are generated in advance.
+	PeriodsAhead int
+	// PeriodsBehind caps how many periods before the current one are backfilled when the
+	// latest existing period ended long ago, so an old database does not get months of them.
+	PeriodsBehind int
+	// GenerateEnabled turns on the background job that generates the periods.
+	GenerateEnabled  bool
+	GenerateInterval time.Duration
+}
+
+func LoadPayrollCalendarConfig() PayrollCalendarConfig {
+	anchor, err := time.Parse("2006-01-02", GetEnv("PAYROLL_ANCHOR_DATE", "2024-01-01"))
+	if err != nil {
+		log.Printf("Invalid PAYROLL_ANCHOR_DATE, using 2024-01-01: %v", err)
+		anchor = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return PayrollCalendarConfig{
+		Frequency:        GetEnv("PAYROLL_FREQUENCY", PayrollFrequencyMonthly),
+		CutoffDay:        GetEnvInt("PAYROLL_CUTOFF_DAY", 0),
+		AnchorDate:       anchor,
+		PeriodsAhead:     GetEnvInt("PAYROLL_PERIODS_AHEAD", 1),
+		PeriodsBehind:    GetEnvInt("PAYROLL_PERIODS_BEHIND", 1),
+		GenerateEnabled:  GetEnvBool("PAYROLL_GENERATE_ENABLED", false),
+		GenerateInterval: time.Duration(GetEnvInt("PAYROLL_GENERATE_INTERVAL_HOURS", 24)) * time.Hour,
+	}
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/container"
+	"github.com/galiherlangga/go-attendance/pkg/jobs"
 	"github.com/galiherlangga/go-attendance/pkg/migrations"
 	"github.com/galiherlangga/go-attendance/pkg/seeders"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
 	"github.com/galiherlangga/go-attendance/routes"
 )
 
@@ -30,9 +34,18 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey DeviceKey
+// @in header
+// @name X-Device-Key
 func main() {
 	config.LoadEnv(".env")
 	appConfig := config.LoadAppConfig()
+	location, err := appConfig.Location()
+	if err != nil {
+		log.Fatalf("Invalid APP_TIMEZONE %q: %v", appConfig.Timezone, err)
+	}
+	utils.SetCompanyLocation(location)
 	
 	db, err := config.InitDB()
 	if err != nil {
@@ -43,8 +56,10 @@ func main() {
 	seeders.Seed(db)
 	
 	config.InitRedis()
+	app := container.New(db, config.RedisClient)
+	jobs.Start(context.Background(), app)
 	
-	r := routes.SetupRouter(db, config.RedisClient)
+	r := routes.SetupRouter(app)
 	
 	addr := fmt.Sprintf("%s:%s", appConfig.Host, appConfig.Port)
 	log.Printf("🚀 Server running at http://%s\n", addr)
@@ -0,0 +1,114 @@
+package container
+
+import (
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/mailer"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Container holds the services of the application, built once and shared by the HTTP
+// router and the background jobs, together with the repositories and configuration the
+// middlewares and job schedules need.
+type Container struct {
+	DB    *gorm.DB
+	Cache *redis.Client
+
+	AttendanceConfig      config.AttendanceConfig
+	PayrollCalendarConfig config.PayrollCalendarConfig
+	PayslipEmailConfig    config.PayslipEmailConfig
+
+	UserRepo   repositories.UserRepository
+	DeviceRepo repositories.DeviceRepository
+
+	UserService                  services.UserService
+	PayslipService               services.PayslipService
+	PayslipDeliveryService       services.PayslipDeliveryService
+	PayrollPeriodService         services.PayrollPeriodService
+	PayrollCalendarService       services.PayrollCalendarService
+	NotificationService          services.NotificationService
+	OvertimeService              services.OvertimeService
+	AttendanceService            services.AttendanceService
+	AttendanceCorrectionService  services.AttendanceCorrectionService
+	AttendanceImportService      services.AttendanceImportService
+	ReimbursementService         services.ReimbursementService
+	ReimbursementCategoryService services.ReimbursementCategoryService
+	SiteService                  services.SiteService
+	ShiftService                 services.ShiftService
+	DeviceService                services.DeviceService
+	KioskService                 services.KioskService
+	PunchService                 services.PunchService
+	LeaveService                 services.LeaveService
+	DashboardService             services.DashboardService
+	ReportService                services.ReportService
+	HolidayService               services.HolidayService
+	OffCycleService              services.OffCycleService
+	LoanService                  services.LoanService
+	AllowanceService             services.AllowanceService
+}
+
+// New wires the repositories and services of the application.
+func New(db *gorm.DB, cache *redis.Client) *Container {
+	c := &Container{
+		DB:                    db,
+		Cache:                 cache,
+		AttendanceConfig:      config.LoadAttendanceConfig(),
+		PayrollCalendarConfig: config.LoadPayrollCalendarConfig(),
+		PayslipEmailConfig:    config.LoadPayslipEmailConfig(),
+	}
+
+	// Init repositories
+	userRepo := repositories.NewUserRepository(db)
+	payslipRepo := repositories.NewPayslipRepository(db)
+	payrollPeriodRepo := repositories.NewPayrollPeriodRepository(db)
+	attendanceRepo := repositories.NewAttendanceRepository(db)
+	overtimeRepo := repositories.NewOvertimeRepository(db)
+	reimbursementRepo := repositories.NewReimbursementRepository(db)
+	reimbursementCategoryRepo := repositories.NewReimbursementCategoryRepository(db)
+	siteRepo := repositories.NewSiteRepository(db)
+	shiftRepo := repositories.NewShiftRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	attendanceCorrectionRepo := repositories.NewAttendanceCorrectionRepository(db)
+	deviceRepo := repositories.NewDeviceRepository(db)
+	punchRepo := repositories.NewPunchRepository(db)
+	leaveRepo := repositories.NewLeaveRepository(db)
+	dashboardRepo := repositories.NewDashboardRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+	holidayRepo := repositories.NewHolidayRepository(db)
+	offCycleRepo := repositories.NewOffCycleRepository(db)
+	loanRepo := repositories.NewLoanRepository(db)
+	allowanceRepo := repositories.NewAllowanceRepository(db)
+	payslipDeliveryRepo := repositories.NewPayslipDeliveryRepository(db)
+	c.UserRepo = userRepo
+	c.DeviceRepo = deviceRepo
+
+	// Init services
+	c.UserService = services.NewUserService(userRepo)
+	c.PayslipService = services.NewPayslipService(payslipRepo, attendanceRepo, overtimeRepo, reimbursementRepo, payrollPeriodRepo, userRepo, shiftRepo, holidayRepo, offCycleRepo, loanRepo, allowanceRepo, config.LoadPayrollConfig())
+	c.PayslipDeliveryService = services.NewPayslipDeliveryService(payslipDeliveryRepo, payslipRepo, userRepo, payrollPeriodRepo, mailer.NewSMTPMailer(config.LoadSMTPConfig()), c.PayslipEmailConfig)
+	c.PayrollPeriodService = services.NewPayrollPeriodService(payrollPeriodRepo, userRepo, c.PayslipService, c.PayslipDeliveryService, cache)
+	c.PayrollCalendarService = services.NewPayrollCalendarService(payrollPeriodRepo, cache, c.PayrollCalendarConfig)
+	c.NotificationService = services.NewNotificationService(notificationRepo)
+	c.OvertimeService = services.NewOvertimeService(overtimeRepo, attendanceRepo, payrollPeriodRepo, userRepo, cache, config.LoadOvertimeConfig())
+	c.AttendanceService = services.NewAttendanceService(attendanceRepo, siteRepo, shiftRepo, userRepo, payrollPeriodRepo, c.OvertimeService, c.NotificationService, cache, c.AttendanceConfig)
+	c.AttendanceCorrectionService = services.NewAttendanceCorrectionService(attendanceCorrectionRepo, attendanceRepo, userRepo, payrollPeriodRepo, c.AttendanceService, c.NotificationService)
+	c.AttendanceImportService = services.NewAttendanceImportService(attendanceRepo, shiftRepo, userRepo, payrollPeriodRepo, c.AttendanceService)
+	c.ReimbursementService = services.NewReimbursementService(reimbursementRepo, reimbursementCategoryRepo, payrollPeriodRepo, userRepo, cache)
+	c.ReimbursementCategoryService = services.NewReimbursementCategoryService(reimbursementCategoryRepo, userRepo)
+	c.SiteService = services.NewSiteService(siteRepo)
+	c.ShiftService = services.NewShiftService(shiftRepo, cache)
+	c.DeviceService = services.NewDeviceService(deviceRepo)
+	c.KioskService = services.NewKioskService(deviceRepo, siteRepo, c.AttendanceService, c.AttendanceConfig)
+	c.PunchService = services.NewPunchService(punchRepo, deviceRepo, userRepo, payrollPeriodRepo, c.AttendanceService)
+	c.LeaveService = services.NewLeaveService(leaveRepo, userRepo, cache)
+	c.DashboardService = services.NewDashboardService(dashboardRepo, userRepo, c.AttendanceConfig)
+	c.ReportService = services.NewReportService(reportRepo, leaveRepo, cache)
+	c.HolidayService = services.NewHolidayService(holidayRepo)
+	c.OffCycleService = services.NewOffCycleService(offCycleRepo, userRepo)
+	c.LoanService = services.NewLoanService(loanRepo, userRepo)
+	c.AllowanceService = services.NewAllowanceService(allowanceRepo, userRepo, payrollPeriodRepo)
+
+	return c
+}
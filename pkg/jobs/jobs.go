@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/pkg/container"
+)
+
+// Start schedules the background jobs on the services of the container.
+func Start(ctx context.Context, app *container.Container) {
+	Every(ctx, "attendance-close-out", app.AttendanceConfig.CloseOutInterval, func(ctx context.Context) error {
+		handled, err := app.AttendanceService.CloseOpenAttendances(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if handled > 0 {
+			log.Printf("Closed out %d open attendances\n", handled)
+		}
+		return nil
+	})
+
+	if app.PayrollCalendarConfig.GenerateEnabled {
+		Every(ctx, "payroll-period-generation", app.PayrollCalendarConfig.GenerateInterval, func(ctx context.Context) error {
+			periods, err := app.PayrollCalendarService.GeneratePayrollPeriods(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+			if len(periods) > 0 {
+				log.Printf("Generated %d payroll periods\n", len(periods))
+			}
+			return nil
+		})
+	}
+
+	if app.PayslipEmailConfig.Enabled {
+		Every(ctx, "payslip-email-delivery", app.PayslipEmailConfig.SendInterval, func(ctx context.Context) error {
+			sent, err := app.PayslipDeliveryService.DeliverDue(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+			if sent > 0 {
+				log.Printf("Emailed %d payslips\n", sent)
+			}
+			return nil
+		})
+	}
+}
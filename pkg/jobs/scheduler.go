@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs job immediately and then on every interval until ctx is cancelled.
+// Errors are logged so one failed run does not stop the schedule. A job whose interval is not
+// positive is not scheduled at all.
+func Every(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Printf("⚠️ Job %s not scheduled: interval must be positive, got %s\n", name, interval)
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := job(ctx); err != nil {
+				log.Printf("⚠️ Job %s failed: %v\n", name, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
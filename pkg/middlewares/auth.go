@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/galiherlangga/go-attendance/app/repositories"
 	"github.com/galiherlangga/go-attendance/pkg/utils"
@@ -80,3 +81,30 @@ func IsAdminMiddleware(userRepo repositories.UserRepository) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// HasRoleMiddleware only lets through users whose role is one of the given roles.
+func HasRoleMiddleware(userRepo repositories.UserRepository, roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, err := utils.GetUserFromContext(ctx)
+		if err != nil {
+			ctx.JSON(401, gin.H{"error": "Unauthorized"})
+			ctx.Abort()
+			return
+		}
+
+		user, err := userRepo.FindByID(userID)
+		if err != nil {
+			ctx.JSON(500, gin.H{"error": "Failed to fetch user"})
+			ctx.Abort()
+			return
+		}
+
+		if !slices.Contains(roles, user.Role.Name) {
+			ctx.JSON(403, gin.H{"error": "Forbidden – insufficient role"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
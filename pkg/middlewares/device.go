@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeviceAuthMiddleware authenticates attendance terminals by the API key in the X-Device-Key
+// header and stores the device in the context.
+func DeviceAuthMiddleware(deviceRepo repositories.DeviceRepository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		apiKey := ctx.GetHeader("X-Device-Key")
+		if apiKey == "" {
+			ctx.JSON(401, gin.H{"error": "Unauthorized – device key missing"})
+			ctx.Abort()
+			return
+		}
+
+		device, err := deviceRepo.GetDeviceByAPIKeyHash(utils.HashAPIKey(apiKey))
+		if err != nil || !device.IsActive {
+			ctx.JSON(401, gin.H{"error": "Unauthorized"})
+			ctx.Abort()
+			return
+		}
+
+		requestID := uuid.New().String()
+		ctx.Set("device", device)
+		ctx.Set("request_id", requestID)
+		ctx.Writer.Header().Set("X-Request-ID", requestID)
+		ctx.Next()
+	}
+}
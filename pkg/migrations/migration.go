@@ -12,14 +12,39 @@ func AutoMigrate(db *gorm.DB) {
 		&models.Role{},
 		&models.User{},
 		&models.PayrollPeriod{},
+		&models.Site{},
+		&models.Shift{},
+		&models.ShiftAssignment{},
 		&models.Attendance{},
+		&models.AttendanceSession{},
+		&models.Device{},
+		&models.Punch{},
+		&models.AttendanceCorrection{},
+		&models.Leave{},
+		&models.Holiday{},
+		&models.Notification{},
 		&models.Overtime{},
+		&models.ReimbursementCategory{},
+		&models.ReimbursementLimit{},
 		&models.Reimbursement{},
 		&models.Payslip{},
+		&models.OffCycleRun{},
+		&models.OffCyclePayslip{},
+		&models.Loan{},
+		&models.LoanInstallment{},
+		&models.AllowanceAssignment{},
+		&models.PayrollAdjustment{},
+		&models.PayslipDelivery{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
+	// Reimbursements used to be unique per user and day
+	if db.Migrator().HasIndex(&models.Reimbursement{}, "idx_user_date") {
+		if err := db.Migrator().DropIndex(&models.Reimbursement{}, "idx_user_date"); err != nil {
+			log.Fatalf("Failed to drop reimbursement index: %v", err)
+		}
+	}
 	models.RegisterCallbacks(db)
 	log.Println("Migrations completed successfully")
 }
\ No newline at end of file
@@ -0,0 +1,235 @@
+// Package pdf writes plain text documents in a monospaced font, optionally protected with a
+// password through the standard security handler (128-bit RC4), which every PDF reader opens.
+package pdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	pageWidth    = 595 // A4 in points
+	pageHeight   = 842
+	margin       = 50
+	fontSize     = 10
+	leading      = 14
+	linesPerPage = (pageHeight - 2*margin) / leading
+	// permissions allows printing only, see table 22 of ISO 32000-1.
+	permissions int32 = -1852
+)
+
+// winAnsi maps the punctuation WinAnsiEncoding places outside Latin-1.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// padding is the fixed string passwords are padded with before hashing.
+var padding = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// Render lays the lines out on as many A4 pages as they need. A non-empty password encrypts
+// the document so that it only opens with that password.
+func Render(lines []string, password string) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	var enc *encryption
+	if password != "" {
+		// Nobody needs the owner password, it only has to be unguessable
+		ownerPassword := make([]byte, 16)
+		if _, err := rand.Read(ownerPassword); err != nil {
+			return nil, err
+		}
+		enc = newEncryption(password, hex.EncodeToString(ownerPassword), id)
+	}
+
+	var pages [][]string
+	for start := 0; start < len(lines); start += linesPerPage {
+		pages = append(pages, lines[start:min(start+linesPerPage, len(lines))])
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+
+	// Objects 1 to 3 are the catalog, the page tree and the font; every page then takes two
+	// objects, the page itself and its content stream.
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	w.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	w.object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		pageNum, contentNum := 4+2*i, 5+2*i
+		w.object(pageNum, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, contentNum))
+		content := pageContent(page)
+		if enc != nil {
+			content = enc.encrypt(contentNum, content)
+		}
+		w.stream(contentNum, content)
+	}
+
+	size := 4 + 2*len(pages)
+	trailer := fmt.Sprintf("/Size %d /Root 1 0 R /ID [<%x> <%x>]", size, id, id)
+	if enc != nil {
+		w.object(size, fmt.Sprintf("<< /Filter /Standard /V 2 /R 3 /Length 128 /P %d /O <%x> /U <%x> >>", permissions, enc.owner, enc.user))
+		trailer = fmt.Sprintf("/Size %d /Root 1 0 R /Encrypt %d 0 R /ID [<%x> <%x>]", size+1, size, id, id)
+	}
+	w.finish(trailer)
+	return w.buf.Bytes(), nil
+}
+
+func pageContent(lines []string) []byte {
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin-fontSize)
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("T*\n")
+		}
+		fmt.Fprintf(&content, "(%s) Tj\n", escape(line))
+	}
+	content.WriteString("ET\n")
+	return content.Bytes()
+}
+
+// escape encodes the line for a literal string in WinAnsiEncoding. Characters the encoding
+// cannot show become question marks.
+func escape(line string) string {
+	var escaped strings.Builder
+	for _, r := range line {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r >= 0x20 && r < 0x7F:
+			escaped.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&escaped, "\\%03o", r)
+		case winAnsi[r] != 0:
+			fmt.Fprintf(&escaped, "\\%03o", winAnsi[r])
+		default:
+			escaped.WriteByte('?')
+		}
+	}
+	return escaped.String()
+}
+
+type writer struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (w *writer) object(num int, body string) {
+	w.begin(num)
+	fmt.Fprintf(&w.buf, "%s\nendobj\n", body)
+}
+
+func (w *writer) stream(num int, data []byte) {
+	w.begin(num)
+	fmt.Fprintf(&w.buf, "<< /Length %d >>\nstream\n", len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (w *writer) begin(num int) {
+	if w.offsets == nil {
+		w.offsets = map[int]int{}
+	}
+	w.offsets[num] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n", num)
+}
+
+// finish writes the cross-reference table, whose entries are exactly 20 bytes long, and the
+// trailer.
+func (w *writer) finish(trailer string) {
+	start := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for num := 1; num <= len(w.offsets); num++ {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", w.offsets[num])
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< %s >>\nstartxref\n%d\n%%%%EOF\n", trailer, start)
+}
+
+// encryption holds the revision 3 standard security handler values, computed with
+// algorithms 2, 3 and 5 of ISO 32000-1.
+type encryption struct {
+	key   []byte
+	owner []byte
+	user  []byte
+}
+
+func newEncryption(userPassword, ownerPassword string, id []byte) *encryption {
+	// Algorithm 3: the owner entry
+	ownerKey := md5.Sum(pad(ownerPassword))
+	for i := 0; i < 50; i++ {
+		ownerKey = md5.Sum(ownerKey[:])
+	}
+	owner := rc4Rounds(ownerKey[:], pad(userPassword))
+
+	// Algorithm 2: the document key
+	hash := md5.New()
+	hash.Write(pad(userPassword))
+	hash.Write(owner)
+	binary.Write(hash, binary.LittleEndian, permissions)
+	hash.Write(id)
+	var key [md5.Size]byte
+	copy(key[:], hash.Sum(nil))
+	for i := 0; i < 50; i++ {
+		key = md5.Sum(key[:])
+	}
+
+	// Algorithm 5: the user entry, padded to 32 bytes
+	hash = md5.New()
+	hash.Write(padding)
+	hash.Write(id)
+	user := append(rc4Rounds(key[:], hash.Sum(nil)), make([]byte, 16)...)
+
+	return &encryption{key: key[:], owner: owner, user: user}
+}
+
+// encrypt encrypts a string or stream of the object with its own key (algorithm 1).
+func (e *encryption) encrypt(objectNum int, data []byte) []byte {
+	hash := md5.New()
+	hash.Write(e.key)
+	hash.Write([]byte{byte(objectNum), byte(objectNum >> 8), byte(objectNum >> 16), 0, 0})
+	cipher, _ := rc4.NewCipher(hash.Sum(nil))
+	encrypted := make([]byte, len(data))
+	cipher.XORKeyStream(encrypted, data)
+	return encrypted
+}
+
+func pad(password string) []byte {
+	padded := []byte(password)
+	if len(padded) > 32 {
+		padded = padded[:32]
+	}
+	return append(padded, padding[:32-len(padded)]...)
+}
+
+// rc4Rounds encrypts the data with the key, then 19 more times with every byte of the key
+// XORed with the round number.
+func rc4Rounds(key, data []byte) []byte {
+	out := append([]byte(nil), data...)
+	roundKey := make([]byte, len(key))
+	for round := 0; round < 20; round++ {
+		for i := range key {
+			roundKey[i] = key[i] ^ byte(round)
+		}
+		cipher, _ := rc4.NewCipher(roundKey)
+		cipher.XORKeyStream(out, out)
+	}
+	return out
+}
@@ -28,6 +28,17 @@ func SeedAttendances(db *gorm.DB) {
 				Date:      date,
 				CheckIn:   &checkinTime,
 				CheckOut:  &checkoutTime,
+				WorkedHours: checkoutTime.Sub(checkinTime).Hours(),
+				Sessions: []models.AttendanceSession{
+					{
+						CheckIn:  &checkinTime,
+						CheckOut: &checkoutTime,
+						BaseModel: models.BaseModel{
+							CreatedBy: &user.ID,
+							UpdatedBy: &user.ID,
+						},
+					},
+				},
 				BaseModel: models.BaseModel{
 					CreatedBy: &user.ID,
 					UpdatedBy: &user.ID,
@@ -11,10 +11,12 @@ func SeedRoles(db *gorm.DB) {
 	var count int64
 	db.Model(&models.Role{}).Count(&count)
 	if count > 0 {
+		// Roles added after the initial seed still need to exist
+		db.Where(models.Role{Name: models.RoleManager}).FirstOrCreate(&models.Role{Name: models.RoleManager})
 		fmt.Println("Roles already seeded, skipping...")
 		return
 	}
-	roles := []string{"admin", "user"}
+	roles := []string{"admin", "user", "manager"}
 	
 	fmt.Println("Seeding roles...")
 	var roleModels []models.Role
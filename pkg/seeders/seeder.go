@@ -6,6 +6,7 @@ type Database interface {
 	Model(value interface{}) *gorm.DB
 	Count(count *int64) *gorm.DB
 	Create(value interface{}) *gorm.DB
+	Where(query interface{}, args ...interface{}) *gorm.DB
 }
 
 func Seed(db *gorm.DB) {
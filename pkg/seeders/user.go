@@ -34,15 +34,41 @@ func SeedUsers(db Database) {
 	}
 	fmt.Println("Admin user created successfully")
 
+	// Managers each lead a team of the users below. The manager role was added after the
+	// initial seed, so its ID depends on the database and is looked up by name.
+	managers := make([]models.User, 0, 5)
+	var managerRole models.Role
+	if err := db.Where(models.Role{Name: models.RoleManager}).First(&managerRole).Error; err != nil {
+		fmt.Printf("Error loading manager role, seeding users without managers: %v\n", err)
+	}
+	for i := 1; managerRole.ID != 0 && i <= 5; i++ {
+		manager := models.User{
+			Name:     gofakeit.Name(),
+			Email:    fmt.Sprintf("manager%d@example.com", i),
+			Password: string(password),
+			RoleID:   managerRole.ID,
+		}
+		if err := db.Create(&manager).Error; err != nil {
+			fmt.Printf("Error creating manager %d: %v\n", i, err)
+			continue
+		}
+		managers = append(managers, manager)
+	}
+
 	// Loop to create 100 users
 	for i := 1; i <= 100; i++ {
 		salary := float64(gofakeit.IntRange(10000, 100000))
+		employeeCode := fmt.Sprintf("EMP%04d", i)
 		user := models.User{
 			Name:     gofakeit.Name(),
 			Email:    gofakeit.Email(),
 			Password: string(password), // Use the same password for simplicity
 			RoleID:   2,                // Assuming role ID 2 is for regular users
 			MonthlySalary: &salary,
+			EmployeeCode:  &employeeCode,
+		}
+		if len(managers) > 0 {
+			user.ManagerID = &managers[(i-1)%len(managers)].ID
 		}
 		if err := db.Create(&user).Error; err != nil {
 			fmt.Printf("Error creating user %d: %v\n", i, err)
@@ -19,4 +19,42 @@ func CountWorkingDays(start, end time.Time) int {
 		}
 	}
 	return count
-}
\ No newline at end of file
+}
+
+// companyLocation is the timezone attendance and payroll dates are bucketed in
+// unless a user or site has its own.
+var companyLocation = time.UTC
+
+func SetCompanyLocation(loc *time.Location) {
+	companyLocation = loc
+}
+
+func CompanyLocation() *time.Location {
+	return companyLocation
+}
+
+// LocationOrDefault loads the first valid timezone name, falling back to the company timezone.
+func LocationOrDefault(names ...*string) *time.Location {
+	for _, name := range names {
+		if name == nil || *name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(*name); err == nil {
+			return loc
+		}
+	}
+	return companyLocation
+}
+
+// DateIn returns the calendar date of t as seen in loc. Dates are represented as midnight UTC,
+// which is how DATE columns are read and written.
+func DateIn(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// CivilDate keeps the calendar date of t as written, dropping the time and offset.
+func CivilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
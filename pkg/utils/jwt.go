@@ -68,3 +68,49 @@ func generateJWT(userID uint, duration time.Duration) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
 }
+
+const kioskTokenPurpose = "kiosk"
+
+// KioskClaims identify the kiosk that displayed a QR token and the site it stands at.
+type KioskClaims struct {
+	DeviceID uint
+	SiteID   uint
+}
+
+// GenerateKioskToken signs a short-lived QR token for a kiosk. It carries no user_id, so it
+// cannot be used as an access token.
+func GenerateKioskToken(deviceID, siteID uint, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := jwt.MapClaims{
+		"purpose":   kioskTokenPurpose,
+		"device_id": deviceID,
+		"site_id":   siteID,
+		"exp":       expiresAt.Unix(),
+		"iat":       now.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(jwtSecret)
+	return signed, expiresAt, err
+}
+
+// ParseKioskToken validates a kiosk QR token, including its expiry.
+func ParseKioskToken(tokenStr string) (*KioskClaims, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("invalid or expired kiosk token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != kioskTokenPurpose {
+		return nil, fmt.Errorf("invalid kiosk token")
+	}
+	deviceID, okDevice := claims["device_id"].(float64)
+	siteID, okSite := claims["site_id"].(float64)
+	if !okDevice || !okSite {
+		return nil, fmt.Errorf("invalid kiosk token")
+	}
+	return &KioskClaims{DeviceID: uint(deviceID), SiteID: uint(siteID)}, nil
+}
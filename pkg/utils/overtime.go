@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// OvertimeTier pays the next Hours of overtime at Multiplier times the hourly wage.
+// Zero Hours covers every remaining hour.
+type OvertimeTier struct {
+	Hours      float64
+	Multiplier float64
+}
+
+// ParseOvertimeTiers reads tiers written as comma separated "hours:multiplier" pairs,
+// e.g. "1:1.5,0:2" pays the first hour at 1.5× and the rest at 2×.
+func ParseOvertimeTiers(value string) ([]OvertimeTier, error) {
+	var tiers []OvertimeTier
+	for _, part := range strings.Split(value, ",") {
+		hours, multiplier, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, errors.New("overtime tiers must be hours:multiplier pairs")
+		}
+		tier := OvertimeTier{}
+		var err error
+		if tier.Hours, err = strconv.ParseFloat(hours, 64); err != nil || tier.Hours < 0 {
+			return nil, errors.New("overtime tier hours must be a non-negative number")
+		}
+		if tier.Multiplier, err = strconv.ParseFloat(multiplier, 64); err != nil || tier.Multiplier <= 0 {
+			return nil, errors.New("overtime tier multiplier must be a positive number")
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+// WeightedOvertimeHours multiplies the overtime hours of one day by their tiers, giving the
+// number of hourly wages to pay. Hours beyond the last tier are paid at its multiplier.
+func WeightedOvertimeHours(hours float64, tiers []OvertimeTier) float64 {
+	weighted := 0.0
+	for i, tier := range tiers {
+		if hours <= 0 {
+			break
+		}
+		portion := hours
+		if tier.Hours > 0 && i < len(tiers)-1 && portion > tier.Hours {
+			portion = tier.Hours
+		}
+		weighted += portion * tier.Multiplier
+		hours -= portion
+	}
+	return weighted
+}
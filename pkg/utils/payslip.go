@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// PayslipPDFPassword derives the password of a user's payslip PDFs from a server secret, so it
+// cannot be guessed from anything printed on the payslip or known to colleagues.
+func PayslipPDFPassword(secret string, userID uint) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatUint(uint64(userID), 10)))
+	return hex.EncodeToString(mac.Sum(nil))[:12]
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"sort"
+	"time"
+)
+
+// PunchPair is a check-in punch and the check-out punch that closes it, if any.
+type PunchPair struct {
+	In  time.Time
+	Out *time.Time
+}
+
+// PairPunches sorts punches and pairs them alternately into check-ins and check-outs.
+// A punch within the debounce window of the previous accepted punch is a repeated tap
+// and is ignored. An odd punch count leaves the last pair open.
+func PairPunches(punches []time.Time, debounce time.Duration) []PunchPair {
+	sorted := append([]time.Time(nil), punches...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var pairs []PunchPair
+	var last *time.Time
+	for i := range sorted {
+		punch := sorted[i]
+		if last != nil && punch.Sub(*last) < debounce {
+			continue
+		}
+		last = &punch
+
+		if n := len(pairs); n > 0 && pairs[n-1].Out == nil {
+			pairs[n-1].Out = &punch
+			continue
+		}
+		pairs = append(pairs, PunchPair{In: punch})
+	}
+	return pairs
+}
@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// WithFreshRequestID returns ctx with a new request_id. Audited rows keep a unique request_id,
+// so every row one request writes to the same table needs its own.
+func WithFreshRequestID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, "request_id", uuid.New().String())
+}
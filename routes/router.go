@@ -5,19 +5,18 @@ import (
 	"time"
 
 	"github.com/galiherlangga/go-attendance/app/handlers"
-	"github.com/galiherlangga/go-attendance/app/repositories"
-	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/pkg/container"
 	middleware "github.com/galiherlangga/go-attendance/pkg/middlewares"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"gorm.io/gorm"
 	_ "github.com/galiherlangga/go-attendance/docs"
 )
 
-func SetupRouter(db *gorm.DB, cache *redis.Client) *gin.Engine {
+// SetupRouter registers the routes of the application on the services of the container.
+func SetupRouter(app *container.Container) *gin.Engine {
 	router := gin.Default()
 
 	router.Use(cors.New(cors.Config{
@@ -29,33 +28,33 @@ func SetupRouter(db *gorm.DB, cache *redis.Client) *gin.Engine {
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// Init repositories
-	userRepo := repositories.NewUserRepository(db)
-	payslipRepo := repositories.NewPayslipRepository(db)
-	payrollPeriodRepo := repositories.NewPayrollPeriodRepository(db)
-	attendanceRepo := repositories.NewAttendanceRepository(db)
-	overtimeRepo := repositories.NewOvertimeRepository(db)
-	reimbursementRepo := repositories.NewReimbursementRepository(db)
-
-	// Init services
-	userService := services.NewUserService(userRepo)
-	payslipService := services.NewPayslipService(payslipRepo, attendanceRepo, overtimeRepo, reimbursementRepo, payrollPeriodRepo, userRepo)
-	payrollPeriodService := services.NewPayrollPeriodService(payrollPeriodRepo, userRepo, payslipService, cache)
-	attendanceService := services.NewAttendanceService(attendanceRepo)
-	overtimeService := services.NewOvertimeService(overtimeRepo, cache)
-	reimbursementService := services.NewReimbursementService(reimbursementRepo, payrollPeriodRepo, cache)
-
 	// Init handlers
-	userHandler := handlers.NewUserHandler(userService)
-	payslipHandler := handlers.NewPayslipHandler(payslipService, userService)
-	payrollPeriodHandler := handlers.NewPayrollPeriodHandler(payrollPeriodService)
-	attendanceHandler := handlers.NewAttendanceHandler(attendanceService, userService)
-	overtimeHandler := handlers.NewOvertimeHandler(overtimeService, userService)
-	reimbursementHandler := handlers.NewReimbursementHandler(reimbursementService, userService)
+	userHandler := handlers.NewUserHandler(app.UserService)
+	payslipHandler := handlers.NewPayslipHandler(app.PayslipService, app.UserService)
+	payrollPeriodHandler := handlers.NewPayrollPeriodHandler(app.PayrollPeriodService, app.PayrollCalendarService)
+	attendanceHandler := handlers.NewAttendanceHandler(app.AttendanceService, app.UserService)
+	overtimeHandler := handlers.NewOvertimeHandler(app.OvertimeService, app.UserService)
+	reimbursementHandler := handlers.NewReimbursementHandler(app.ReimbursementService, app.UserService)
+	reimbursementCategoryHandler := handlers.NewReimbursementCategoryHandler(app.ReimbursementCategoryService)
+	siteHandler := handlers.NewSiteHandler(app.SiteService)
+	shiftHandler := handlers.NewShiftHandler(app.ShiftService)
+	notificationHandler := handlers.NewNotificationHandler(app.NotificationService)
+	attendanceCorrectionHandler := handlers.NewAttendanceCorrectionHandler(app.AttendanceCorrectionService, app.AttendanceService)
+	attendanceImportHandler := handlers.NewAttendanceImportHandler(app.AttendanceImportService)
+	deviceHandler := handlers.NewDeviceHandler(app.DeviceService, app.PunchService)
+	kioskHandler := handlers.NewKioskHandler(app.KioskService)
+	leaveHandler := handlers.NewLeaveHandler(app.LeaveService)
+	dashboardHandler := handlers.NewDashboardHandler(app.DashboardService)
+	reportHandler := handlers.NewReportHandler(app.ReportService)
+	holidayHandler := handlers.NewHolidayHandler(app.HolidayService)
+	offCycleHandler := handlers.NewOffCycleHandler(app.OffCycleService)
+	loanHandler := handlers.NewLoanHandler(app.LoanService)
+	allowanceHandler := handlers.NewAllowanceHandler(app.AllowanceService)
+	payslipDeliveryHandler := handlers.NewPayslipDeliveryHandler(app.PayslipDeliveryService)
 
 	// Health check route
 	router.GET("/health", func(ctx *gin.Context) {
-		sqlDB, err := db.DB()
+		sqlDB, err := app.DB.DB()
 		if err != nil || sqlDB.Ping() != nil {
 			ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "unhealthy"})
 			return
@@ -69,16 +68,122 @@ func SetupRouter(db *gorm.DB, cache *redis.Client) *gin.Engine {
 		authGroup.POST("login", userHandler.Login)
 	}
 
+	// User routes
+	userGroup := router.Group("/users")
+	userGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		userGroup.PUT("/:id/manager", userHandler.UpdateUserManager)
+		userGroup.PUT("/:id/timezone", userHandler.UpdateUserTimezone)
+		userGroup.PUT("/:id/grade", userHandler.UpdateUserGrade)
+	}
+
 	// Payroll period routes
 	payrollPeriodGroup := router.Group("/payroll-periods")
-	payrollPeriodGroup.Use(middleware.IsAdminMiddleware(userRepo), middleware.AuditMiddleware())
+	payrollPeriodGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
 	{
 		payrollPeriodGroup.GET("", payrollPeriodHandler.GetPayrollPeriodList)
 		payrollPeriodGroup.GET("/:id", payrollPeriodHandler.GetPayrollPeriodByID)
 		payrollPeriodGroup.POST("", payrollPeriodHandler.CreatePayrollPeriod)
+		payrollPeriodGroup.POST("/generate", payrollPeriodHandler.GeneratePayrollPeriods)
 		payrollPeriodGroup.PUT("/:id", payrollPeriodHandler.UpdatePayrollPeriod)
 		payrollPeriodGroup.DELETE("/:id", payrollPeriodHandler.DeletePayrollPeriod)
+		payrollPeriodGroup.GET("/:id/preview", payrollPeriodHandler.PreviewPayrollPeriod)
 		payrollPeriodGroup.POST("/:id/run-payroll", payrollPeriodHandler.RunPayrollPeriod)
+		payrollPeriodGroup.POST("/:id/reopen", payrollPeriodHandler.ReopenPayrollPeriod)
+	}
+
+	// Off-cycle payroll run routes
+	offCycleGroup := router.Group("/off-cycle-runs")
+	offCycleGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		offCycleGroup.GET("", offCycleHandler.GetOffCycleRunList)
+		offCycleGroup.GET("/:id", offCycleHandler.GetOffCycleRunByID)
+		offCycleGroup.POST("", offCycleHandler.CreateOffCycleRun)
+		offCycleGroup.DELETE("/:id", offCycleHandler.DeleteOffCycleRun)
+		offCycleGroup.POST("/:id/process", offCycleHandler.ProcessOffCycleRun)
+	}
+
+	// Loan routes
+	loanGroup := router.Group("/loans")
+	loanGroup.Use(middleware.JWTAuthMiddleware(), middleware.AuditMiddleware())
+	{
+		loanGroup.GET("/me", loanHandler.GetMyLoanList)
+		loanGroup.GET("/:id", loanHandler.GetLoanByID)
+	}
+	loanAdminGroup := router.Group("/loans")
+	loanAdminGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		loanAdminGroup.GET("", loanHandler.GetLoanList)
+		loanAdminGroup.POST("", loanHandler.CreateLoan)
+		loanAdminGroup.DELETE("/:id", loanHandler.DeleteLoan)
+	}
+
+	// Allowance and payroll adjustment routes
+	allowanceGroup := router.Group("/allowances")
+	allowanceGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		allowanceGroup.GET("", allowanceHandler.GetAllowanceList)
+		allowanceGroup.POST("", allowanceHandler.CreateAllowance)
+		allowanceGroup.PUT("/:id", allowanceHandler.UpdateAllowance)
+		allowanceGroup.DELETE("/:id", allowanceHandler.DeleteAllowance)
+	}
+	adjustmentGroup := router.Group("/payroll-adjustments")
+	adjustmentGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		adjustmentGroup.GET("", allowanceHandler.GetAdjustmentList)
+		adjustmentGroup.POST("", allowanceHandler.CreateAdjustment)
+		adjustmentGroup.DELETE("/:id", allowanceHandler.DeleteAdjustment)
+	}
+
+	// Site routes
+	siteGroup := router.Group("/sites")
+	siteGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		siteGroup.GET("", siteHandler.GetSiteList)
+		siteGroup.GET("/:id", siteHandler.GetSiteByID)
+		siteGroup.POST("", siteHandler.CreateSite)
+		siteGroup.PUT("/:id", siteHandler.UpdateSite)
+		siteGroup.DELETE("/:id", siteHandler.DeleteSite)
+	}
+
+	// Shift routes
+	shiftGroup := router.Group("/shifts")
+	shiftGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		shiftGroup.GET("", shiftHandler.GetShiftList)
+		shiftGroup.GET("/:id", shiftHandler.GetShiftByID)
+		shiftGroup.POST("", shiftHandler.CreateShift)
+		shiftGroup.PUT("/:id", shiftHandler.UpdateShift)
+		shiftGroup.DELETE("/:id", shiftHandler.DeleteShift)
+		shiftGroup.GET("/assignments", shiftHandler.GetAssignmentList)
+		shiftGroup.POST("/assignments", shiftHandler.AssignShift)
+		shiftGroup.DELETE("/assignments/:id", shiftHandler.DeleteAssignment)
+	}
+
+	// Device routes
+	deviceGroup := router.Group("/devices")
+	deviceGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		deviceGroup.GET("", deviceHandler.GetDeviceList)
+		deviceGroup.GET("/:id", deviceHandler.GetDeviceByID)
+		deviceGroup.POST("", deviceHandler.CreateDevice)
+		deviceGroup.PUT("/:id", deviceHandler.UpdateDevice)
+		deviceGroup.DELETE("/:id", deviceHandler.DeleteDevice)
+		deviceGroup.POST("/:id/rotate-key", deviceHandler.RotateDeviceKey)
+	}
+
+	// Punch ingestion for attendance terminals, authenticated by device API key
+	punchGroup := router.Group("/punches")
+	punchGroup.Use(middleware.DeviceAuthMiddleware(app.DeviceRepo))
+	{
+		punchGroup.POST("", deviceHandler.IngestPunches)
+	}
+
+	// Kiosks display rotating QR tokens, authenticated by device API key
+	kioskGroup := router.Group("/kiosk")
+	kioskGroup.Use(middleware.DeviceAuthMiddleware(app.DeviceRepo))
+	{
+		kioskGroup.GET("/token", kioskHandler.GetKioskToken)
 	}
 
 	// Attendance routes
@@ -87,8 +192,71 @@ func SetupRouter(db *gorm.DB, cache *redis.Client) *gin.Engine {
 	{
 		attendanceGroup.POST("/check-in", attendanceHandler.CheckIn)
 		attendanceGroup.POST("/check-out", attendanceHandler.CheckOut)
+		attendanceGroup.POST("/kiosk-check-in", kioskHandler.KioskCheckIn)
 		attendanceGroup.GET("", attendanceHandler.GetAttendanceList)
+		attendanceGroup.GET("/corrections", attendanceCorrectionHandler.GetMyCorrectionList)
 		attendanceGroup.GET("/:id", attendanceHandler.RetrieveAttendance)
+		attendanceGroup.POST("/:id/corrections", attendanceCorrectionHandler.RequestCorrection)
+	}
+	attendanceAdminGroup := router.Group("/attendances")
+	attendanceAdminGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		attendanceAdminGroup.PUT("", attendanceImportHandler.UpsertAttendance)
+		attendanceAdminGroup.POST("/import", attendanceImportHandler.ImportAttendances)
+	}
+
+	// Attendance correction routes
+	attendanceCorrectionGroup := router.Group("/attendance-corrections")
+	attendanceCorrectionGroup.Use(middleware.HasRoleMiddleware(app.UserRepo, models.RoleAdmin, models.RoleManager), middleware.AuditMiddleware())
+	{
+		attendanceCorrectionGroup.GET("", attendanceCorrectionHandler.GetCorrectionList)
+		attendanceCorrectionGroup.POST("/:id/approve", attendanceCorrectionHandler.ApproveCorrection)
+		attendanceCorrectionGroup.POST("/:id/reject", attendanceCorrectionHandler.RejectCorrection)
+	}
+
+	// Holiday routes
+	holidayGroup := router.Group("/holidays")
+	holidayGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		holidayGroup.GET("", holidayHandler.GetHolidayList)
+		holidayGroup.POST("", holidayHandler.CreateHoliday)
+		holidayGroup.DELETE("/:id", holidayHandler.DeleteHoliday)
+	}
+
+	// Leave routes
+	leaveGroup := router.Group("/leaves")
+	leaveGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		leaveGroup.GET("", leaveHandler.GetLeaveList)
+		leaveGroup.POST("", leaveHandler.CreateLeave)
+		leaveGroup.DELETE("/:id", leaveHandler.DeleteLeave)
+	}
+
+	// Team dashboard routes
+	dashboardGroup := router.Group("/dashboard")
+	dashboardGroup.Use(middleware.HasRoleMiddleware(app.UserRepo, models.RoleAdmin, models.RoleManager), middleware.AuditMiddleware())
+	{
+		dashboardGroup.GET("/daily", dashboardHandler.GetDailyBoard)
+		dashboardGroup.GET("/monthly", dashboardHandler.GetMonthlyMatrix)
+	}
+
+	// Report routes
+	reportGroup := router.Group("/reports")
+	reportGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		reportGroup.GET("/attendance-rate", reportHandler.GetAttendanceRateReport)
+		reportGroup.GET("/top-overtime", reportHandler.GetTopOvertimeUsers)
+		reportGroup.GET("/overtime-cost", reportHandler.GetOvertimeCostTrend)
+		reportGroup.GET("/payroll-cost", reportHandler.GetPayrollCostByPeriod)
+		reportGroup.GET("/reimbursement-spend", reportHandler.GetReimbursementSpendByMonth)
+	}
+
+	// Notification routes
+	notificationGroup := router.Group("/notifications")
+	notificationGroup.Use(middleware.JWTAuthMiddleware(), middleware.AuditMiddleware())
+	{
+		notificationGroup.GET("", notificationHandler.GetNotificationList)
+		notificationGroup.POST("/:id/read", notificationHandler.MarkNotificationAsRead)
 	}
 
 	// Overtime routes
@@ -100,6 +268,7 @@ func SetupRouter(db *gorm.DB, cache *redis.Client) *gin.Engine {
 		overtimeGroup.POST("", overtimeHandler.CreateOvertime)
 		overtimeGroup.PUT("/:id", overtimeHandler.UpdateOvertime)
 		overtimeGroup.DELETE("/:id", overtimeHandler.DeleteOvertime)
+		overtimeGroup.POST("/:id/confirm", overtimeHandler.ConfirmOvertime)
 	}
 
 	// Reimbursement routes
@@ -107,24 +276,52 @@ func SetupRouter(db *gorm.DB, cache *redis.Client) *gin.Engine {
 	reimbursementGroup.Use(middleware.JWTAuthMiddleware(), middleware.AuditMiddleware())
 	{
 		reimbursementGroup.GET("", reimbursementHandler.GetReimbursementList)
+		reimbursementGroup.GET("/budget", reimbursementHandler.GetBudgetUsage)
 		reimbursementGroup.GET("/:id", reimbursementHandler.GetReimbursementByID)
 		reimbursementGroup.POST("", reimbursementHandler.CreateReimbursement)
 		reimbursementGroup.PUT("/:id", reimbursementHandler.UpdateReimbursement)
 		reimbursementGroup.DELETE("/:id", reimbursementHandler.DeleteReimbursement)
 	}
 
+	// Reimbursement category routes
+	reimbursementCategoryGroup := router.Group("/reimbursement-categories")
+	reimbursementCategoryGroup.Use(middleware.JWTAuthMiddleware(), middleware.AuditMiddleware())
+	{
+		reimbursementCategoryGroup.GET("", reimbursementCategoryHandler.GetCategoryList)
+	}
+	reimbursementCategoryAdminGroup := router.Group("/reimbursement-categories")
+	reimbursementCategoryAdminGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		reimbursementCategoryAdminGroup.POST("", reimbursementCategoryHandler.CreateCategory)
+		reimbursementCategoryAdminGroup.PUT("/:id", reimbursementCategoryHandler.UpdateCategory)
+		reimbursementCategoryAdminGroup.POST("/:id/limits", reimbursementCategoryHandler.CreateLimit)
+		reimbursementCategoryAdminGroup.DELETE("/:id/limits/:limit_id", reimbursementCategoryHandler.DeleteLimit)
+	}
+
 	// Payslip routes
 	payslipGroup := router.Group("/payslips")
 	payslipGroup.Use(middleware.JWTAuthMiddleware(), middleware.AuditMiddleware())
 	{
+		payslipGroup.GET("/off-cycle", offCycleHandler.GetMyOffCyclePayslips)
+		payslipGroup.GET("/history", payslipHandler.GetPayslipHistory)
+		payslipGroup.GET("/statement", payslipHandler.GetEarningsStatement)
+		payslipGroup.GET("/pdf-password", payslipDeliveryHandler.GetPDFPassword)
 		payslipGroup.GET("/:period_id", payslipHandler.GetPayslipByUserAndPeriod)
 	}
 	payslipAdminGroup := router.Group("/payslips")
-	payslipAdminGroup.Use(middleware.IsAdminMiddleware(userRepo), middleware.AuditMiddleware())
+	payslipAdminGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
 	{
 		payslipAdminGroup.GET("/summary/:period_id", payslipHandler.GetPayslipSummary)
 	}
 
+	// Payslip email delivery routes
+	payslipDeliveryGroup := router.Group("/payslip-deliveries")
+	payslipDeliveryGroup.Use(middleware.IsAdminMiddleware(app.UserRepo), middleware.AuditMiddleware())
+	{
+		payslipDeliveryGroup.GET("", payslipDeliveryHandler.GetDeliveryList)
+		payslipDeliveryGroup.POST("/:id/resend", payslipDeliveryHandler.ResendDelivery)
+	}
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	return router
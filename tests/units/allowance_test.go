@@ -0,0 +1,77 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPayslipAllowancesAndAdjustments(t *testing.T) {
+	ended := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	allowanceRepo := &stubAllowanceRepo{
+		assignments: []*models.AllowanceAssignment{
+			{Name: "Transport", Amount: 500000, StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Name: "Position", Amount: 1000000, StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: &ended},
+		},
+		adjustments: []*models.PayrollAdjustment{
+			{UserID: 1, PayrollPeriodID: 2, Description: "Back pay for January", Amount: 250000},
+			{UserID: 1, PayrollPeriodID: 2, Description: "Uniform", Amount: -100000},
+			{UserID: 2, PayrollPeriodID: 2, Description: "Another employee", Amount: 999},
+		},
+	}
+	loanRepo := &stubDueLoanRepo{due: []*models.LoanInstallment{{Amount: 300000}}}
+	service := services.NewPayslipService(nil, &stubPayslipAttendanceRepo{attended: 20}, &stubPayslipOvertimeRepo{},
+		&stubPayslipReimbursementRepo{}, nil, nil, nil, nil, nil, loanRepo, allowanceRepo, config.PayrollConfig{})
+	period := &models.PayrollPeriod{
+		BaseModel: models.BaseModel{Model: gorm.Model{ID: 2}},
+		StartDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	payslip, err := service.PreviewPayslip(1, period, 4000000)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PayslipLine{
+		{Type: models.PayslipLineAllowance, Description: "Transport", Amount: 500000},
+		{Type: models.PayslipLineAdjustment, Description: "Back pay for January", Amount: 250000},
+		{Type: models.PayslipLineAdjustment, Description: "Uniform", Amount: -100000},
+	}, payslip.Lines)
+	assert.Equal(t, 300000.0, payslip.LoanDeduction)
+	assert.Equal(t, 4750000.0, payslip.GrossPay())
+	assert.Equal(t, 400000.0, payslip.Deductions())
+	assert.Equal(t, 4350000.0, payslip.TakeHomePay)
+}
+
+func TestRecurringAllowanceProration(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	lines := func(t *testing.T, assignment *models.AllowanceAssignment, start, end time.Time) []models.PayslipLine {
+		service := services.NewPayslipService(nil, &stubPayslipAttendanceRepo{attended: 10}, &stubPayslipOvertimeRepo{},
+			&stubPayslipReimbursementRepo{}, nil, nil, nil, nil, nil, &stubDueLoanRepo{}, &stubAllowanceRepo{assignments: []*models.AllowanceAssignment{assignment}}, config.PayrollConfig{})
+		payslip, err := service.PreviewPayslip(1, &models.PayrollPeriod{StartDate: start, EndDate: end}, 4000000)
+		assert.NoError(t, err)
+		return payslip.Lines
+	}
+
+	t.Run("semi-monthly periods each pay half and add up to the monthly amount", func(t *testing.T) {
+		transport := &models.AllowanceAssignment{Name: "Transport", Amount: 500000, StartDate: day(1)}
+		first := lines(t, transport, day(1), day(15))
+		second := lines(t, transport, day(16), day(30))
+		assert.Equal(t, []models.PayslipLine{{Type: models.PayslipLineAllowance, Description: "Transport", Amount: 250000}}, first)
+		assert.Equal(t, []models.PayslipLine{{Type: models.PayslipLineAllowance, Description: "Transport", Amount: 250000}}, second)
+	})
+
+	t.Run("an allowance starting mid-period pays the days it covers", func(t *testing.T) {
+		position := &models.AllowanceAssignment{Name: "Position", Amount: 900000, StartDate: day(10)}
+		assert.Equal(t, []models.PayslipLine{{Type: models.PayslipLineAllowance, Description: "Position (21 of 30 days)", Amount: 630000}}, lines(t, position, day(1), day(30)))
+	})
+
+	t.Run("a period across two months pays each month's share", func(t *testing.T) {
+		meal := &models.AllowanceAssignment{Name: "Meal", Amount: 310000, StartDate: day(1)}
+		// 5 of June's 30 days and 15 of July's 31
+		assert.Equal(t, 201666.67, lines(t, meal, day(26), time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC))[0].Amount)
+	})
+}
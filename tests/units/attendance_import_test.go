@@ -0,0 +1,109 @@
+package units
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubNoShiftRepo struct {
+	repositories.ShiftRepository
+}
+
+func (r *stubNoShiftRepo) GetAssignmentByUserAndDate(userID uint, date string) (*models.ShiftAssignment, error) {
+	return nil, nil
+}
+
+func TestAttendanceImport(t *testing.T) {
+	jakarta := "Asia/Jakarta"
+	userRepo := &stubUserRepo{users: map[uint]*models.User{
+		1: {Model: gorm.Model{ID: 1}},
+		2: {Model: gorm.Model{ID: 2}, Timezone: &jakarta},
+	}}
+	existing := &models.Attendance{UserID: 2}
+	newService := func(attendance *models.Attendance) services.AttendanceImportService {
+		return services.NewAttendanceImportService(&stubSessionAttendanceRepo{attendance: attendance}, &stubNoShiftRepo{}, userRepo, &stubPayrollPeriodRepo{}, nil)
+	}
+	importCSV := func(t *testing.T, service services.AttendanceImportService, csv string) *models.AttendanceImportResult {
+		result, err := service.ImportAttendances(context.Background(), strings.NewReader(csv), true, false)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("header columns are matched by name in any order", func(t *testing.T) {
+		result := importCSV(t, newService(nil), "\ufeffCheck_Out, DATE ,note,User_ID,check_in\n17:00,2025-06-02,late bus,1,08:00:30\n")
+		assert.Equal(t, 1, result.Created)
+		row := result.Rows[0]
+		assert.Empty(t, row.Error)
+		assert.Equal(t, uint(1), row.UserID)
+		assert.Equal(t, time.Date(2025, 6, 2, 8, 0, 30, 0, time.UTC), *row.CheckIn)
+		assert.Equal(t, time.Date(2025, 6, 2, 17, 0, 0, 0, time.UTC), *row.CheckOut)
+	})
+
+	t.Run("a header missing a column is rejected", func(t *testing.T) {
+		_, err := newService(nil).ImportAttendances(context.Background(), strings.NewReader("user_id,date,check_in\n1,2025-06-02,08:00\n"), true, false)
+		assert.EqualError(t, err, "the CSV header must contain the columns user_id, date, check_in, check_out")
+	})
+
+	t.Run("times are read in the employee's timezone and overnight check-outs move to the next day", func(t *testing.T) {
+		result := importCSV(t, newService(nil), "user_id,date,check_in,check_out\n2,2025-06-02,22:00,06:00\n")
+		row := result.Rows[0]
+		assert.Empty(t, row.Error)
+		assert.Equal(t, "Asia/Jakarta", row.CheckIn.Location().String())
+		assert.Equal(t, time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC), row.CheckIn.UTC())
+		assert.Equal(t, time.Date(2025, 6, 2, 23, 0, 0, 0, time.UTC), row.CheckOut.UTC())
+	})
+
+	t.Run("invalid rows are reported without stopping the import", func(t *testing.T) {
+		csv := strings.Join([]string{
+			"user_id,date,check_in,check_out",
+			"1,2025-06-02,08:00,17:00",
+			"abc,2025-06-03,08:00,17:00",
+			"1,03/06/2025,08:00,17:00",
+			"1,2025-06-04,25:00,17:00",
+			"1,2025-06-05,08:00,5pm",
+			"9,2025-06-05,08:00,17:00",
+			"1,2025-06-02,09:00,18:00",
+			"1,2025-06-07,08:00,17:00",
+			"1,2025-06-09,,",
+			"1,2999-01-01,08:00,17:00",
+		}, "\n")
+		result := importCSV(t, newService(nil), csv)
+		assert.Equal(t, 10, result.Total)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 9, result.Failed)
+
+		errors := []string{}
+		for _, row := range result.Rows {
+			errors = append(errors, row.Error)
+		}
+		assert.Equal(t, []string{
+			"",
+			"user_id must be a positive number",
+			"date must be in YYYY-MM-DD format",
+			"invalid check_in: time must be in HH:MM or HH:MM:SS format",
+			"invalid check_out: time must be in HH:MM or HH:MM:SS format",
+			"user not found",
+			"duplicate of line 2 for the same user and date",
+			"date falls on a weekend and the user has no shift rostered",
+			"either check_in or check_out must be provided",
+			"attendance cannot be recorded in the future",
+		}, errors)
+	})
+
+	t.Run("an existing day is updated, a new one needs a check-in", func(t *testing.T) {
+		result := importCSV(t, newService(existing), "user_id,date,check_in,check_out\n2,2025-06-02,,17:00\n")
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, models.ImportActionUpdate, result.Rows[0].Action)
+
+		result = importCSV(t, newService(nil), "user_id,date,check_in,check_out\n2,2025-06-02,,17:00\n")
+		assert.Equal(t, "check-in is required for a new attendance", result.Rows[0].Error)
+	})
+}
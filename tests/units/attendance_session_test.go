@@ -0,0 +1,108 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	redismock "github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAttendanceSessions(t *testing.T) {
+	const userID uint = 1
+	ctx := context.Background()
+	newService := func(repo *stubSessionAttendanceRepo) services.AttendanceService {
+		cache, _ := redismock.NewClientMock()
+		userRepo := &stubUserRepo{users: map[uint]*models.User{userID: {Model: gorm.Model{ID: userID}}}}
+		return services.NewAttendanceService(repo, &stubNoSiteRepo{}, &stubAllDayShiftRepo{}, userRepo, &stubPayrollPeriodRepo{}, nil, nil, cache, config.AttendanceConfig{})
+	}
+
+	t.Run("first check-in opens the day with one session", func(t *testing.T) {
+		repo := &stubSessionAttendanceRepo{}
+		att, err := newService(repo).CheckIn(ctx, userID, nil)
+		assert.NoError(t, err)
+		assert.Len(t, att.Sessions, 1)
+		assert.NotNil(t, att.CheckIn)
+		assert.Nil(t, att.CheckOut)
+		assert.NotNil(t, att.OpenSession())
+	})
+
+	t.Run("checking in again while a session is open is rejected", func(t *testing.T) {
+		repo := &stubSessionAttendanceRepo{}
+		service := newService(repo)
+		_, err := service.CheckIn(ctx, userID, nil)
+		assert.NoError(t, err)
+
+		_, err = service.CheckIn(ctx, userID, nil)
+		assert.EqualError(t, err, "already checked in, check out before starting a new session")
+		assert.Empty(t, repo.created)
+		assert.Len(t, repo.attendance.Sessions, 1)
+	})
+
+	t.Run("check-out closes the open session", func(t *testing.T) {
+		repo := &stubSessionAttendanceRepo{}
+		service := newService(repo)
+		_, err := service.CheckIn(ctx, userID, nil)
+		assert.NoError(t, err)
+
+		att, err := service.CheckOut(ctx, userID, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, att.OpenSession())
+		assert.NotNil(t, att.CheckOut)
+		assert.Len(t, repo.updated, 1)
+		assert.NotNil(t, repo.updated[0].CheckOut)
+
+		_, err = service.CheckOut(ctx, userID, nil)
+		assert.EqualError(t, err, "no open session, check in before checking out")
+	})
+
+	t.Run("a new session reopens a checked out day", func(t *testing.T) {
+		repo := &stubSessionAttendanceRepo{}
+		service := newService(repo)
+		_, err := service.CheckIn(ctx, userID, nil)
+		assert.NoError(t, err)
+		_, err = service.CheckOut(ctx, userID, nil)
+		assert.NoError(t, err)
+
+		att, err := service.CheckIn(ctx, userID, nil)
+		assert.NoError(t, err)
+		assert.Len(t, att.Sessions, 2)
+		assert.Len(t, repo.created, 1)
+		assert.Equal(t, att.ID, repo.created[0].AttendanceID)
+		assert.Nil(t, att.CheckOut)
+		assert.Same(t, &att.Sessions[1], att.OpenSession())
+	})
+
+	t.Run("check-out without a check-in is rejected", func(t *testing.T) {
+		_, err := newService(&stubSessionAttendanceRepo{}).CheckOut(ctx, userID, nil)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("worked hours only count closed sessions", func(t *testing.T) {
+		date := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+		at := func(hour, minute int) *time.Time {
+			tm := time.Date(2025, 6, 2, hour, minute, 0, 0, time.UTC)
+			return &tm
+		}
+		att := &models.Attendance{
+			UserID: userID, Date: date, Timezone: "UTC", CheckIn: at(8, 0),
+			Sessions: []models.AttendanceSession{
+				{CheckIn: at(8, 0), CheckOut: at(12, 0)},
+				{CheckIn: at(13, 0), CheckOut: at(15, 30)},
+				{CheckIn: at(16, 0)},
+			},
+		}
+		service := services.NewAttendanceService(&stubAttendanceListRepo{attendances: []*models.Attendance{att}}, nil, nil, nil, nil, nil, nil, nil, config.AttendanceConfig{
+			DefaultShiftStart: "08:00", DefaultShiftEnd: "17:00",
+		})
+		attendances, totals, err := service.GetAttendanceList(userID, "2025-06-02", "2025-06-02")
+		assert.NoError(t, err)
+		assert.Equal(t, 6.5, attendances[0].WorkedHours)
+		assert.Equal(t, 3, totals.Sessions)
+	})
+}
@@ -0,0 +1,107 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubOpenAttendanceRepo struct {
+	repositories.AttendanceRepository
+	open    []*models.Attendance
+	updated []*models.Attendance
+}
+
+func (r *stubOpenAttendanceRepo) GetOpenAttendances(beforeDate string) ([]*models.Attendance, error) {
+	return r.open, nil
+}
+func (r *stubOpenAttendanceRepo) UpdateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error) {
+	return session, nil
+}
+func (r *stubOpenAttendanceRepo) UpdateAttendance(ctx context.Context, att *models.Attendance) (*models.Attendance, error) {
+	r.updated = append(r.updated, att)
+	return att, nil
+}
+
+func TestCloseOpenAttendances(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		tm := time.Date(2025, 6, day, hour, 0, 0, 0, time.UTC)
+		return &tm
+	}
+	openDay := func(id uint, day int, checkIn *time.Time, shift *models.Shift) *models.Attendance {
+		return &models.Attendance{
+			BaseModel: models.BaseModel{Model: gorm.Model{ID: id}},
+			UserID:    id,
+			Date:      time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			Timezone:  "UTC",
+			CheckIn:   checkIn,
+			Shift:     shift,
+			Sessions:  []models.AttendanceSession{{CheckIn: checkIn}},
+		}
+	}
+	overnight := &models.Shift{StartTime: "22:00", EndTime: "06:00", CrossesMidnight: true}
+	now := *at(3, 9)
+	cfg := config.AttendanceConfig{DefaultShiftStart: "09:00", DefaultShiftEnd: "17:00", ShiftLateCheckOut: 4 * time.Hour}
+
+	run := func(policy string, periodRepo repositories.PayrollPeriodRepository, open ...*models.Attendance) (*stubOpenAttendanceRepo, *stubNotificationService, int) {
+		repo := &stubOpenAttendanceRepo{open: open}
+		notifications := &stubNotificationService{}
+		cfg.CloseOutPolicy = policy
+		service := services.NewAttendanceService(repo, nil, nil, nil, periodRepo, nil, notifications, nil, cfg)
+		handled, err := service.CloseOpenAttendances(context.Background(), now)
+		assert.NoError(t, err)
+		return repo, notifications, handled
+	}
+
+	t.Run("auto close checks out at the end of the shift", func(t *testing.T) {
+		repo, notifications, handled := run(config.CloseOutPolicyAutoClose, &stubPayrollPeriodRepo{},
+			openDay(1, 2, at(2, 9), nil),
+			openDay(2, 2, at(2, 18), nil))
+		assert.Equal(t, 2, handled)
+		assert.Empty(t, notifications.notified)
+
+		assert.Equal(t, models.CloseOutAutoClosed, repo.updated[0].CloseOutStatus)
+		assert.Equal(t, *at(2, 17), *repo.updated[0].CheckOut)
+		assert.Equal(t, *at(2, 17), *repo.updated[0].Sessions[0].CheckOut)
+		assert.Equal(t, 8.0, repo.updated[0].WorkedHours)
+
+		// A session opened after the shift ended is closed where it started
+		assert.Equal(t, *at(2, 18), *repo.updated[1].CheckOut)
+		assert.Equal(t, 0.0, repo.updated[1].WorkedHours)
+	})
+
+	t.Run("notify flags the day and asks for a correction", func(t *testing.T) {
+		repo, notifications, handled := run(config.CloseOutPolicyNotify, &stubPayrollPeriodRepo{}, openDay(1, 2, at(2, 9), nil))
+		assert.Equal(t, 1, handled)
+		assert.Equal(t, []uint{1}, notifications.notified)
+		assert.Equal(t, models.CloseOutIncomplete, repo.updated[0].CloseOutStatus)
+		assert.Nil(t, repo.updated[0].CheckOut)
+	})
+
+	t.Run("incomplete only flags the day", func(t *testing.T) {
+		repo, notifications, handled := run(config.CloseOutPolicyIncomplete, &stubPayrollPeriodRepo{}, openDay(1, 2, at(2, 9), nil))
+		assert.Equal(t, 1, handled)
+		assert.Empty(t, notifications.notified)
+		assert.Equal(t, models.CloseOutIncomplete, repo.updated[0].CloseOutStatus)
+		assert.Nil(t, repo.updated[0].CheckOut)
+	})
+
+	t.Run("overnight shifts wait for their late check-out window", func(t *testing.T) {
+		repo, _, handled := run(config.CloseOutPolicyAutoClose, &stubPayrollPeriodRepo{}, openDay(1, 2, at(2, 22), overnight))
+		assert.Equal(t, 0, handled)
+		assert.Empty(t, repo.updated)
+	})
+
+	t.Run("days in a processed period are left alone", func(t *testing.T) {
+		repo, _, handled := run(config.CloseOutPolicyAutoClose, &stubPayrollPeriodRepo{lockedDate: "2025-06-02"}, openDay(1, 2, at(2, 9), nil))
+		assert.Equal(t, 0, handled)
+		assert.Empty(t, repo.updated)
+	})
+}
@@ -0,0 +1,59 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAllowanceDaysRepo struct {
+	stubPayslipAttendanceRepo
+	eligible int64
+	rule     *models.DailyAllowanceRule
+}
+
+func (r *stubAllowanceDaysRepo) CountAllowanceDays(userID uint, startDate, endDate string, rule models.DailyAllowanceRule) (int64, error) {
+	r.rule = &rule
+	return r.eligible, nil
+}
+
+func TestDailyAllowances(t *testing.T) {
+	period := &models.PayrollPeriod{
+		StartDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+	}
+	preview := func(attendanceRepo *stubAllowanceDaysRepo, cfg config.PayrollConfig) *models.Payslip {
+		service := services.NewPayslipService(nil, attendanceRepo, &stubPayslipOvertimeRepo{}, &stubPayslipReimbursementRepo{},
+			nil, nil, nil, nil, nil, &stubDueLoanRepo{}, &stubAllowanceRepo{}, cfg)
+		payslip, err := service.PreviewPayslip(1, period, 4000000)
+		assert.NoError(t, err)
+		return payslip
+	}
+
+	t.Run("pays each rate for the eligible days only", func(t *testing.T) {
+		attendanceRepo := &stubAllowanceDaysRepo{stubPayslipAttendanceRepo: stubPayslipAttendanceRepo{attended: 20}, eligible: 18}
+		payslip := preview(attendanceRepo, config.PayrollConfig{
+			MealAllowancePerDay:            25000,
+			TransportAllowancePerDay:       30000,
+			DailyAllowanceMaxLateMinutes:   15,
+			DailyAllowanceRequireFullHours: true,
+		})
+		assert.Equal(t, []models.PayslipLine{
+			{Type: models.PayslipLineDailyAllowance, Description: "Meal allowance (18 days)", Amount: 450000},
+			{Type: models.PayslipLineDailyAllowance, Description: "Transport allowance (18 days)", Amount: 540000},
+		}, payslip.Lines)
+		assert.Equal(t, models.DailyAllowanceRule{MaxLateMinutes: 15, RequireFullHours: true}, *attendanceRepo.rule)
+		assert.Equal(t, 4990000.0, payslip.TakeHomePay)
+	})
+
+	t.Run("is skipped when no rate is configured", func(t *testing.T) {
+		attendanceRepo := &stubAllowanceDaysRepo{stubPayslipAttendanceRepo: stubPayslipAttendanceRepo{attended: 20}, eligible: 18}
+		payslip := preview(attendanceRepo, config.PayrollConfig{})
+		assert.Empty(t, payslip.Lines)
+		assert.Nil(t, attendanceRepo.rule)
+	})
+}
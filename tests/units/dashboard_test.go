@@ -0,0 +1,91 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubDashboardRepo struct {
+	managerID   uint
+	members     []*models.TeamMember
+	attendances []*models.TeamAttendance
+	leaves      []*models.Leave
+}
+
+func (r *stubDashboardRepo) GetTeamMembers(managerID uint) ([]*models.TeamMember, error) {
+	r.managerID = managerID
+	return r.members, nil
+}
+func (r *stubDashboardRepo) GetTeamAttendances(managerID uint, startDate, endDate string) ([]*models.TeamAttendance, error) {
+	return r.attendances, nil
+}
+func (r *stubDashboardRepo) GetTeamLeaves(managerID uint, startDate, endDate string) ([]*models.Leave, error) {
+	return r.leaves, nil
+}
+func (r *stubDashboardRepo) GetTeamAssignments(managerID uint, startDate, endDate string) ([]*models.ShiftAssignment, error) {
+	return nil, nil
+}
+
+func TestDashboard(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	userRepo := &stubUserRepo{users: map[uint]*models.User{
+		1: {Role: models.Role{Name: models.RoleAdmin}},
+		2: {Role: models.Role{Name: models.RoleManager}},
+		3: {Role: models.Role{Name: models.RoleUser}},
+	}}
+	userRepo.users[2].ID = 2
+	repo := &stubDashboardRepo{
+		members: []*models.TeamMember{{ID: 10}, {ID: 11}, {ID: 12}, {ID: 13}},
+		attendances: []*models.TeamAttendance{
+			{UserID: 10, Date: day(10)},
+			{UserID: 11, Date: day(10), LateMinutes: 12},
+		},
+		leaves: []*models.Leave{{UserID: 12, StartDate: day(9), EndDate: day(11), Type: models.LeaveTypeSick}},
+	}
+	service := services.NewDashboardService(repo, userRepo, config.AttendanceConfig{DefaultShiftStart: "09:00", DefaultShiftEnd: "17:00"})
+
+	t.Run("classifies a past weekday", func(t *testing.T) {
+		board, err := service.GetDailyBoard(1, 0, day(10))
+		assert.NoError(t, err)
+		statuses := []string{}
+		for _, row := range board.Rows {
+			statuses = append(statuses, row.Status)
+		}
+		assert.Equal(t, []string{models.DashboardPresent, models.DashboardLate, models.DashboardOnLeave, models.DashboardAbsent}, statuses)
+		assert.Equal(t, models.LeaveTypeSick, board.Rows[2].LeaveType)
+	})
+
+	t.Run("monthly matrix has a day per date", func(t *testing.T) {
+		matrix, err := service.GetMonthlyMatrix(1, 0, day(1))
+		assert.NoError(t, err)
+		assert.Len(t, matrix.Dates, 30)
+		assert.Equal(t, models.DashboardOff, matrix.Rows[3].Days[13]) // Saturday 14 June
+		assert.Equal(t, 3, matrix.Rows[2].Summary[models.DashboardOnLeave])
+	})
+
+	t.Run("managers only see their own reports", func(t *testing.T) {
+		_, err := service.GetDailyBoard(2, 0, day(10))
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), repo.managerID)
+
+		_, err = service.GetDailyBoard(2, 5, day(10))
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		_, err = service.GetDailyBoard(3, 0, day(10))
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("admins can scope the board to a manager's reports", func(t *testing.T) {
+		_, err := service.GetDailyBoard(1, 2, day(10))
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), repo.managerID)
+
+		_, err = service.GetMonthlyMatrix(1, 99, day(1))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
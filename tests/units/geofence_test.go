@@ -0,0 +1,29 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceMeters(t *testing.T) {
+	// Monas to Bundaran HI, Jakarta is roughly 2.2 km
+	distance := utils.DistanceMeters(-6.175392, 106.827153, -6.195000, 106.823000)
+	assert.InDelta(t, 2220, distance, 100)
+
+	assert.Equal(t, 0.0, utils.DistanceMeters(-6.2, 106.8, -6.2, 106.8))
+}
+
+func TestIsPointInPolygon(t *testing.T) {
+	square := [][2]float64{
+		{-6.20, 106.80},
+		{-6.20, 106.82},
+		{-6.22, 106.82},
+		{-6.22, 106.80},
+	}
+
+	assert.True(t, utils.IsPointInPolygon(-6.21, 106.81, square))
+	assert.False(t, utils.IsPointInPolygon(-6.25, 106.81, square))
+	assert.False(t, utils.IsPointInPolygon(-6.21, 106.83, square))
+}
@@ -0,0 +1,42 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKioskToken(t *testing.T) {
+	t.Run("Fresh token carries the kiosk and site", func(t *testing.T) {
+		token, expiresAt, err := utils.GenerateKioskToken(3, 7, 30*time.Second)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(30*time.Second), expiresAt, time.Second)
+
+		claims, err := utils.ParseKioskToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), claims.DeviceID)
+		assert.Equal(t, uint(7), claims.SiteID)
+	})
+
+	t.Run("Expired token is rejected", func(t *testing.T) {
+		token, _, err := utils.GenerateKioskToken(3, 7, -time.Minute)
+		assert.NoError(t, err)
+
+		_, err = utils.ParseKioskToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("Tokens are not interchangeable with access tokens", func(t *testing.T) {
+		accessToken, err := utils.GenerateAccessToken(1)
+		assert.NoError(t, err)
+		_, err = utils.ParseKioskToken(accessToken)
+		assert.Error(t, err)
+
+		kioskToken, _, err := utils.GenerateKioskToken(3, 7, 30*time.Second)
+		assert.NoError(t, err)
+		_, err = utils.ParseJWT(kioskToken)
+		assert.Error(t, err)
+	})
+}
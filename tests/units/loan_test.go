@@ -0,0 +1,113 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	redismock "github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubLoanRepo struct {
+	repositories.LoanRepository
+	loans    map[uint]*models.Loan
+	requests map[string]bool
+}
+
+func (r *stubLoanRepo) GetLoanByID(id uint) (*models.Loan, error) {
+	if loan, ok := r.loans[id]; ok {
+		return loan, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *stubLoanRepo) CreateLoan(ctx context.Context, loan *models.Loan) (*models.Loan, error) {
+	loan.ID = uint(len(r.loans) + 1)
+	r.loans[loan.ID] = loan
+	return loan, nil
+}
+func (r *stubLoanRepo) CreateInstallment(ctx context.Context, installment *models.LoanInstallment) error {
+	requestID, _ := ctx.Value("request_id").(string)
+	r.requests[requestID] = true
+	return nil
+}
+func (r *stubLoanRepo) DeleteLoan(id uint) error {
+	delete(r.loans, id)
+	return nil
+}
+
+func TestLoanSchedule(t *testing.T) {
+	repo := &stubLoanRepo{loans: map[uint]*models.Loan{}, requests: map[string]bool{}}
+	userRepo := &stubUserRepo{users: map[uint]*models.User{2: {}}}
+	service := services.NewLoanService(repo, userRepo)
+
+	loan, err := service.CreateLoan(context.Background(), &models.LoanRequest{
+		UserID:       2,
+		Type:         models.LoanTypeLoan,
+		Principal:    1000000,
+		Installments: 3,
+		FirstDueDate: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1000000.0, loan.RemainingBalance)
+	assert.Len(t, repo.requests, 3)
+	var amounts []float64
+	var dueDates []string
+	for _, installment := range loan.Installments {
+		amounts = append(amounts, installment.Amount)
+		dueDates = append(dueDates, installment.DueDate.Format("2006-01-02"))
+	}
+	assert.Equal(t, []float64{333333.33, 333333.33, 333333.34}, amounts)
+	assert.Equal(t, []string{"2025-01-31", "2025-02-28", "2025-03-31"}, dueDates)
+
+	loan.Installments[0].IsPaid = true
+	assert.ErrorIs(t, service.DeleteLoan(loan.ID), services.ErrLoanRepaymentStarted)
+	assert.Contains(t, repo.loans, loan.ID)
+
+	t.Run("leftover cents are spread so no installment is zero or negative", func(t *testing.T) {
+		loan, err := service.CreateLoan(context.Background(), &models.LoanRequest{
+			UserID: 2, Type: models.LoanTypeLoan, Principal: 1.05, Installments: 4, FirstDueDate: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		})
+		assert.NoError(t, err)
+		var amounts []float64
+		for _, installment := range loan.Installments {
+			amounts = append(amounts, installment.Amount)
+		}
+		assert.Equal(t, []float64{0.26, 0.26, 0.26, 0.27}, amounts)
+
+		loan, err = service.CreateLoan(context.Background(), &models.LoanRequest{
+			UserID: 2, Type: models.LoanTypeLoan, Principal: 1.25, Installments: 120, FirstDueDate: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		})
+		assert.NoError(t, err)
+		total := 0.0
+		for _, installment := range loan.Installments {
+			assert.Greater(t, installment.Amount, 0.0)
+			total += installment.Amount
+		}
+		assert.InDelta(t, 1.25, total, 0.001)
+	})
+
+	t.Run("a principal below a cent per installment is rejected", func(t *testing.T) {
+		_, err := service.CreateLoan(context.Background(), &models.LoanRequest{
+			UserID: 2, Type: models.LoanTypeLoan, Principal: 1, Installments: 120, FirstDueDate: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		})
+		assert.EqualError(t, err, "principal must be at least 1.20 to spread over 120 installments")
+	})
+}
+
+func TestReopenPayrollPeriod(t *testing.T) {
+	cache, _ := redismock.NewClientMock()
+	repo := &stubReopenPeriodRepo{period: &models.PayrollPeriod{BaseModel: models.BaseModel{Model: gorm.Model{ID: 5}}, IsProcessed: true}}
+	service := services.NewPayrollPeriodService(repo, nil, nil, nil, cache)
+
+	assert.NoError(t, service.ReopenPayrollPeriod(context.Background(), 5))
+	assert.Equal(t, []uint{5}, repo.reopened)
+	assert.False(t, repo.period.IsProcessed)
+
+	assert.ErrorIs(t, service.ReopenPayrollPeriod(context.Background(), 5), services.ErrPeriodNotProcessed)
+	assert.Len(t, repo.reopened, 1)
+}
@@ -0,0 +1,86 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubOffCycleRepo struct {
+	repositories.OffCycleRepository
+	runs     map[uint]*models.OffCycleRun
+	payslips []*models.OffCyclePayslip
+	settled  map[uint]bool
+}
+
+func (r *stubOffCycleRepo) GetRunByID(id uint) (*models.OffCycleRun, error) {
+	if run, ok := r.runs[id]; ok {
+		return run, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *stubOffCycleRepo) CreateRun(ctx context.Context, run *models.OffCycleRun) (*models.OffCycleRun, error) {
+	run.ID = uint(len(r.runs) + 1)
+	r.runs[run.ID] = run
+	for i := range run.Payslips {
+		run.Payslips[i].OffCycleRunID = run.ID
+		r.payslips = append(r.payslips, &run.Payslips[i])
+	}
+	return run, nil
+}
+func (r *stubOffCycleRepo) HasFinalSettlement(userID uint) (bool, error) {
+	return r.settled[userID], nil
+}
+func (r *stubOffCycleRepo) DeleteRun(id uint) error {
+	delete(r.runs, id)
+	return nil
+}
+
+func TestOffCycleRun(t *testing.T) {
+	repo := &stubOffCycleRepo{runs: map[uint]*models.OffCycleRun{}, settled: map[uint]bool{3: true}}
+	userRepo := &stubUserRepo{users: map[uint]*models.User{1: {}, 2: {}, 3: {}}}
+	service := services.NewOffCycleService(repo, userRepo)
+	ctx := context.Background()
+	payDate := time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)
+
+	t.Run("creates one payslip per employee with the total of its lines", func(t *testing.T) {
+		run, err := service.CreateRun(ctx, &models.OffCycleRunRequest{
+			Type:    models.OffCycleTypeTHR,
+			PayDate: payDate,
+			Employees: []models.OffCycleEmployeeRequest{
+				{UserID: 1, Lines: []models.OffCycleEarning{{Description: "THR", Amount: 5000000}, {Description: "Allowance", Amount: 250000.5}}},
+				{UserID: 2, Lines: []models.OffCycleEarning{{Description: "THR", Amount: 2500000}}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, run.Payslips, 2)
+		assert.Equal(t, 5250000.5, run.Payslips[0].TotalEarnings)
+		assert.Equal(t, run.ID, repo.payslips[1].OffCycleRunID)
+	})
+
+	t.Run("rejects duplicate employees and a second final settlement", func(t *testing.T) {
+		lines := []models.OffCycleEarning{{Description: "Bonus", Amount: 1000}}
+		_, err := service.CreateRun(ctx, &models.OffCycleRunRequest{
+			Type: models.OffCycleTypeBonus, PayDate: payDate,
+			Employees: []models.OffCycleEmployeeRequest{{UserID: 1, Lines: lines}, {UserID: 1, Lines: lines}},
+		})
+		assert.Error(t, err)
+		_, err = service.CreateRun(ctx, &models.OffCycleRunRequest{
+			Type: models.OffCycleTypeFinalSettlement, PayDate: payDate,
+			Employees: []models.OffCycleEmployeeRequest{{UserID: 3, Lines: lines}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("processed runs cannot be deleted", func(t *testing.T) {
+		repo.runs[1].IsProcessed = true
+		assert.ErrorIs(t, service.DeleteRun(1), services.ErrOffCycleProcessed)
+		assert.Contains(t, repo.runs, uint(1))
+	})
+}
@@ -0,0 +1,142 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	redismock "github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubProposalOvertimeRepo stores overtimes by ID and applies the conditional confirm like the database would.
+type stubProposalOvertimeRepo struct {
+	repositories.OvertimeRepository
+	overtimes map[uint]*models.Overtime
+}
+
+func (r *stubProposalOvertimeRepo) GetOvertimeByID(id uint) (*models.Overtime, error) {
+	if overtime, ok := r.overtimes[id]; ok {
+		copied := *overtime
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *stubProposalOvertimeRepo) GetOvertimeByUserAndDate(userID uint, date time.Time) (*models.Overtime, error) {
+	for _, overtime := range r.overtimes {
+		if overtime.UserID == userID && overtime.Date.Equal(date) {
+			copied := *overtime
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+func (r *stubProposalOvertimeRepo) CreateOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error) {
+	overtime.ID = uint(len(r.overtimes) + 1)
+	copied := *overtime
+	r.overtimes[overtime.ID] = &copied
+	return overtime, nil
+}
+func (r *stubProposalOvertimeRepo) UpdateOvertime(ctx context.Context, overtime *models.Overtime) (*models.Overtime, error) {
+	copied := *overtime
+	r.overtimes[overtime.ID] = &copied
+	return overtime, nil
+}
+func (r *stubProposalOvertimeRepo) ConfirmProposedOvertime(ctx context.Context, id uint) (bool, error) {
+	overtime, ok := r.overtimes[id]
+	if !ok || overtime.Status != models.OvertimeStatusProposed {
+		return false, nil
+	}
+	overtime.Status = models.OvertimeStatusSubmitted
+	return true, nil
+}
+func (r *stubProposalOvertimeRepo) GetSubmittedOvertimes(userID uint, startDate, endDate string) ([]*models.Overtime, error) {
+	var submitted []*models.Overtime
+	for _, overtime := range r.overtimes {
+		if overtime.UserID == userID && overtime.Status == models.OvertimeStatusSubmitted {
+			submitted = append(submitted, overtime)
+		}
+	}
+	return submitted, nil
+}
+
+func TestOvertimeProposeAndConfirm(t *testing.T) {
+	const userID uint = 1
+	ctx := context.Background()
+	date := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	userRepo := &stubUserRepo{users: map[uint]*models.User{userID: {Model: gorm.Model{ID: userID}, Role: models.Role{Name: models.RoleUser}}}}
+	attendance := &models.Attendance{BaseModel: models.BaseModel{Model: gorm.Model{ID: 7}}, UserID: userID, Date: date}
+	newService := func(repo *stubProposalOvertimeRepo, cfg config.OvertimeConfig) services.OvertimeService {
+		cache, mock := redismock.NewClientMock()
+		for i := 0; i < 4; i++ {
+			mock.ExpectKeys("overtime:*").SetVal(nil)
+		}
+		return services.NewOvertimeService(repo, nil, &stubPayrollPeriodRepo{}, userRepo, cache, cfg)
+	}
+
+	t.Run("derived overtime waits for confirmation", func(t *testing.T) {
+		repo := &stubProposalOvertimeRepo{overtimes: map[uint]*models.Overtime{}}
+		service := newService(repo, config.OvertimeConfig{DeriveMode: config.OvertimeDerivePropose})
+
+		proposed, err := service.DeriveOvertime(ctx, attendance, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, models.OvertimeStatusProposed, proposed.Status)
+		assert.Equal(t, models.OvertimeSourceAttendance, proposed.Source)
+
+		confirmed, err := service.ConfirmOvertime(ctx, userID, proposed)
+		assert.NoError(t, err)
+		assert.Equal(t, models.OvertimeStatusSubmitted, confirmed.Status)
+		assert.Equal(t, models.OvertimeStatusSubmitted, repo.overtimes[proposed.ID].Status)
+	})
+
+	t.Run("a stale proposed copy cannot be confirmed twice", func(t *testing.T) {
+		repo := &stubProposalOvertimeRepo{overtimes: map[uint]*models.Overtime{
+			1: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 1}}, UserID: userID, Date: date, Hours: 2, Status: models.OvertimeStatusProposed},
+		}}
+		service := newService(repo, config.OvertimeConfig{})
+		stale, _ := repo.GetOvertimeByID(1)
+
+		_, err := service.ConfirmOvertime(ctx, userID, stale)
+		assert.NoError(t, err)
+		assert.Equal(t, models.OvertimeStatusProposed, stale.Status)
+
+		_, err = service.ConfirmOvertime(ctx, userID, stale)
+		assert.EqualError(t, err, "only proposed overtime can be confirmed")
+	})
+
+	t.Run("confirmation is checked against the caps", func(t *testing.T) {
+		repo := &stubProposalOvertimeRepo{overtimes: map[uint]*models.Overtime{
+			1: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 1}}, UserID: userID, Date: date, Hours: 3, Status: models.OvertimeStatusSubmitted},
+			2: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 2}}, UserID: userID, Date: date.AddDate(0, 0, 1), Hours: 2, Status: models.OvertimeStatusProposed},
+		}}
+		service := newService(repo, config.OvertimeConfig{MaxWeeklyHours: 4})
+
+		_, err := service.ConfirmOvertime(ctx, userID, repo.overtimes[2])
+		assert.EqualError(t, err, "overtime exceeds the weekly cap of 4 hours, 1 hours left")
+		assert.Equal(t, models.OvertimeStatusProposed, repo.overtimes[2].Status)
+	})
+
+	t.Run("a re-derived proposal updates its hours, a confirmed one is kept", func(t *testing.T) {
+		repo := &stubProposalOvertimeRepo{overtimes: map[uint]*models.Overtime{}}
+		service := newService(repo, config.OvertimeConfig{DeriveMode: config.OvertimeDerivePropose})
+
+		proposed, err := service.DeriveOvertime(ctx, attendance, 2)
+		assert.NoError(t, err)
+		updated, err := service.DeriveOvertime(ctx, attendance, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, proposed.ID, updated.ID)
+		assert.Equal(t, 3, repo.overtimes[proposed.ID].Hours)
+
+		_, err = service.ConfirmOvertime(ctx, userID, updated)
+		assert.NoError(t, err)
+		kept, err := service.DeriveOvertime(ctx, attendance, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, kept.Hours)
+		assert.Equal(t, models.OvertimeStatusSubmitted, kept.Status)
+	})
+}
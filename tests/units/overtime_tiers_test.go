@@ -0,0 +1,109 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOvertimeTiers(t *testing.T) {
+	weekday, err := utils.ParseOvertimeTiers("1:1.5,0:2")
+	assert.NoError(t, err)
+	restDay, err := utils.ParseOvertimeTiers("8:2, 1:3, 0:4")
+	assert.NoError(t, err)
+
+	t.Run("weekday pays the first hour at 1.5x and the rest at 2x", func(t *testing.T) {
+		assert.Equal(t, 1.5, utils.WeightedOvertimeHours(1, weekday))
+		assert.Equal(t, 5.5, utils.WeightedOvertimeHours(3, weekday))
+	})
+
+	t.Run("rest day tiers", func(t *testing.T) {
+		assert.Equal(t, 8.0, utils.WeightedOvertimeHours(4, restDay))
+		assert.Equal(t, 23.0, utils.WeightedOvertimeHours(10, restDay))
+	})
+
+	t.Run("hours beyond the last tier use its multiplier", func(t *testing.T) {
+		tiers, err := utils.ParseOvertimeTiers("2:1.5")
+		assert.NoError(t, err)
+		assert.Equal(t, 6.0, utils.WeightedOvertimeHours(4, tiers))
+	})
+
+	t.Run("rejects malformed tiers", func(t *testing.T) {
+		_, err := utils.ParseOvertimeTiers("1.5")
+		assert.Error(t, err)
+		_, err = utils.ParseOvertimeTiers("1:0")
+		assert.Error(t, err)
+	})
+}
+
+type stubHolidayListRepo struct {
+	repositories.HolidayRepository
+	holidays []*models.Holiday
+}
+
+func (r *stubHolidayListRepo) GetHolidayList(startDate, endDate string) ([]*models.Holiday, error) {
+	return r.holidays, nil
+}
+
+type stubRosterRepo struct {
+	repositories.ShiftRepository
+	assignments []*models.ShiftAssignment
+}
+
+func (r *stubRosterRepo) GetAssignmentList(userID uint, startDate, endDate string) ([]*models.ShiftAssignment, error) {
+	return r.assignments, nil
+}
+
+func TestRestDayOvertime(t *testing.T) {
+	saturday := time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC)
+	sunday := saturday.AddDate(0, 0, 1)
+
+	t.Run("all the time worked on a rest day is overtime", func(t *testing.T) {
+		at := func(hour, minute int) *time.Time {
+			tm := time.Date(2025, 6, 7, hour, minute, 0, 0, time.UTC)
+			return &tm
+		}
+		att := &models.Attendance{
+			UserID: 1, Date: saturday, Timezone: "UTC", CheckIn: at(10, 0), CheckOut: at(14, 30),
+			Sessions: []models.AttendanceSession{{CheckIn: at(10, 0), CheckOut: at(14, 30)}},
+		}
+		service := services.NewAttendanceService(&stubAttendanceListRepo{attendances: []*models.Attendance{att}}, nil, nil, nil, nil, nil, nil, nil, config.AttendanceConfig{
+			DefaultShiftStart: "09:00", DefaultShiftEnd: "17:00",
+		})
+
+		attendances, _, err := service.GetAttendanceList(1, "2025-06-07", "2025-06-07")
+		assert.NoError(t, err)
+		assert.Equal(t, 270, attendances[0].OvertimeMinutes)
+		assert.Zero(t, attendances[0].LateMinutes)
+		assert.Zero(t, attendances[0].UndertimeMinutes)
+	})
+
+	t.Run("rest day overtime is paid at the rest day tiers", func(t *testing.T) {
+		overtimeRepo := &stubPayslipOvertimeRepo{overtimes: []*models.Overtime{
+			{UserID: 1, Date: saturday, Hours: 4},
+			{UserID: 1, Date: sunday, Hours: 2},
+		}}
+		// Sunday is rostered, so it is a work day
+		shiftRepo := &stubRosterRepo{assignments: []*models.ShiftAssignment{{UserID: 1, Date: sunday}}}
+		weekday, _ := utils.ParseOvertimeTiers("1:1.5,0:2")
+		restDay, _ := utils.ParseOvertimeTiers("8:2,1:3,0:4")
+		service := services.NewPayslipService(nil, &stubPayslipAttendanceRepo{attended: 21}, overtimeRepo,
+			&stubPayslipReimbursementRepo{}, nil, nil, shiftRepo, &stubHolidayListRepo{}, nil, &stubDueLoanRepo{}, &stubAllowanceRepo{},
+			config.PayrollConfig{OvertimeWeekdayTiers: weekday, OvertimeRestDayTiers: restDay, OvertimeHolidayTiers: restDay})
+
+		payslip, err := service.PreviewPayslip(1, &models.PayrollPeriod{
+			StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		}, 4200000)
+		assert.NoError(t, err)
+		assert.Equal(t, 6.0, payslip.OvertimeHours)
+		// 4 rest day hours weigh 8 and 2 work day hours 3.5, at 25,000 an hour
+		assert.Equal(t, 287500.0, payslip.OvertimeEarnings)
+	})
+}
@@ -0,0 +1,123 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	redismock "github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubAttendanceRepo struct {
+	repositories.AttendanceRepository
+	attendances map[uint]*models.Attendance
+}
+
+func (r *stubAttendanceRepo) GetAttendanceByID(id uint) (*models.Attendance, error) {
+	if attendance, ok := r.attendances[id]; ok {
+		return attendance, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestOwnershipEnforcement(t *testing.T) {
+	const owner, other, admin uint = 1, 2, 3
+	userRepo := &stubUserRepo{users: map[uint]*models.User{
+		owner: {Model: gorm.Model{ID: owner}, Role: models.Role{Name: models.RoleUser}},
+		other: {Model: gorm.Model{ID: other}, Role: models.Role{Name: models.RoleUser}},
+		admin: {Model: gorm.Model{ID: admin}, Role: models.Role{Name: models.RoleAdmin}},
+	}}
+	date := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	cache, mock := redismock.NewClientMock()
+	ctx := context.Background()
+
+	overtimeRepo := &stubOvertimeRepo{overtimes: map[uint]*models.Overtime{
+		10: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 10}}, UserID: owner, Date: date, Hours: 2, Status: models.OvertimeStatusProposed},
+	}}
+	overtimeService := services.NewOvertimeService(overtimeRepo, nil, &stubPayrollPeriodRepo{}, userRepo, cache, config.OvertimeConfig{})
+
+	reimbursementRepo := &stubReimbursementRepo{reimbursements: map[uint]*models.Reimbursement{
+		20: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 20}}, UserID: owner, Date: date, Amount: 50000},
+	}}
+	reimbursementService := services.NewReimbursementService(reimbursementRepo, nil, &stubPayrollPeriodRepo{}, userRepo, cache)
+
+	attendanceRepo := &stubAttendanceRepo{attendances: map[uint]*models.Attendance{
+		30: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 30}}, UserID: owner, Date: date},
+	}}
+	attendanceService := services.NewAttendanceService(attendanceRepo, nil, nil, userRepo, nil, nil, nil, cache, config.AttendanceConfig{})
+
+	t.Run("another user cannot read the records", func(t *testing.T) {
+		_, err := overtimeService.GetOvertimeByID(other, 10)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		_, err = reimbursementService.GetReimbursementByID(other, 20)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		_, err = attendanceService.GetAttendanceByID(other, 30)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("another user cannot change the records", func(t *testing.T) {
+		_, err := overtimeService.UpdateOvertime(ctx, other, &models.Overtime{BaseModel: models.BaseModel{Model: gorm.Model{ID: 10}}, UserID: other, Date: date, Hours: 1})
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		_, err = overtimeService.ConfirmOvertime(ctx, other, overtimeRepo.overtimes[10])
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		assert.ErrorIs(t, overtimeService.DeleteOvertime(other, 10), services.ErrForbidden)
+
+		_, err = reimbursementService.UpdateReimbursement(ctx, other, &models.Reimbursement{BaseModel: models.BaseModel{Model: gorm.Model{ID: 20}}, UserID: other, Date: date, Amount: 1})
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		assert.ErrorIs(t, reimbursementService.DeleteReimbursement(other, 20), services.ErrForbidden)
+
+		assert.Empty(t, overtimeRepo.deleted)
+		assert.Empty(t, reimbursementRepo.deleted)
+	})
+
+	t.Run("the owner can read their records", func(t *testing.T) {
+		overtime, err := overtimeService.GetOvertimeByID(owner, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, owner, overtime.UserID)
+		reimbursement, err := reimbursementService.GetReimbursementByID(owner, 20)
+		assert.NoError(t, err)
+		assert.Equal(t, owner, reimbursement.UserID)
+		attendance, err := attendanceService.GetAttendanceByID(owner, 30)
+		assert.NoError(t, err)
+		assert.Equal(t, owner, attendance.UserID)
+	})
+
+	t.Run("an admin can act on any record", func(t *testing.T) {
+		_, err := overtimeService.GetOvertimeByID(admin, 10)
+		assert.NoError(t, err)
+		_, err = attendanceService.GetAttendanceByID(admin, 30)
+		assert.NoError(t, err)
+		mock.ExpectKeys("overtime:*").SetVal(nil)
+		assert.NoError(t, overtimeService.DeleteOvertime(admin, 10))
+		assert.Equal(t, []uint{10}, overtimeRepo.deleted)
+	})
+
+	t.Run("lists are cached per user", func(t *testing.T) {
+		mock.ExpectGet("overtime:1:1:10").SetVal(`{"overtime":[{"user_id":1}],"total":1}`)
+		mock.ExpectGet("overtime:2:1:10").RedisNil()
+		pagination := utils.Pagination{Page: 1, Limit: 10}
+
+		cached, total, err := overtimeService.GetOvertimeList(owner, pagination)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, owner, cached[0].UserID)
+
+		others, total, err := overtimeService.GetOvertimeList(other, pagination)
+		assert.NoError(t, err)
+		assert.Zero(t, total, "another user's list is not served from the owner's cache")
+		assert.Empty(t, others)
+	})
+
+	t.Run("missing records are reported as not found", func(t *testing.T) {
+		_, err := overtimeService.GetOvertimeByID(owner, 99)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, reimbursementService.DeleteReimbursement(owner, 99), gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,111 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	redismock "github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCalendarPeriodRepo struct {
+	repositories.PayrollPeriodRepository
+	periods []*models.PayrollPeriod
+}
+
+func (r *stubCalendarPeriodRepo) FindLatest() (*models.PayrollPeriod, error) {
+	var latest *models.PayrollPeriod
+	for _, period := range r.periods {
+		if latest == nil || period.EndDate.After(latest.EndDate) {
+			latest = period
+		}
+	}
+	return latest, nil
+}
+func (r *stubCalendarPeriodRepo) HasOverlappingPeriod(startDate, endDate string, excludeID uint) (bool, error) {
+	for _, period := range r.periods {
+		if (excludeID == 0 || period.ID != excludeID) && period.StartDate.Format("2006-01-02") <= endDate && period.EndDate.Format("2006-01-02") >= startDate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (r *stubCalendarPeriodRepo) Create(ctx context.Context, period *models.PayrollPeriod) (*models.PayrollPeriod, error) {
+	r.periods = append(r.periods, period)
+	return period, nil
+}
+
+func periodDates(periods []*models.PayrollPeriod) []string {
+	dates := make([]string, 0, len(periods))
+	for _, period := range periods {
+		dates = append(dates, period.StartDate.Format("2006-01-02")+".."+period.EndDate.Format("2006-01-02"))
+	}
+	return dates
+}
+
+func TestGeneratePayrollPeriods(t *testing.T) {
+	now := time.Date(2025, 2, 10, 9, 0, 0, 0, time.UTC)
+	cache, _ := redismock.NewClientMock()
+	generate := func(repo *stubCalendarPeriodRepo, cfg config.PayrollCalendarConfig) []string {
+		periods, _ := services.NewPayrollCalendarService(repo, cache, cfg).GeneratePayrollPeriods(context.Background(), now)
+		return periodDates(periods)
+	}
+
+	t.Run("monthly with a cutoff day", func(t *testing.T) {
+		cfg := config.PayrollCalendarConfig{Frequency: config.PayrollFrequencyMonthly, CutoffDay: 25, PeriodsAhead: 1}
+		assert.Equal(t, []string{"2025-01-26..2025-02-25", "2025-02-26..2025-03-25"}, generate(&stubCalendarPeriodRepo{}, cfg))
+	})
+
+	t.Run("semi-monthly", func(t *testing.T) {
+		cfg := config.PayrollCalendarConfig{Frequency: config.PayrollFrequencySemiMonthly, PeriodsAhead: 2}
+		assert.Equal(t, []string{"2025-02-01..2025-02-15", "2025-02-16..2025-02-28", "2025-03-01..2025-03-15"}, generate(&stubCalendarPeriodRepo{}, cfg))
+	})
+
+	t.Run("bi-weekly from the anchor date", func(t *testing.T) {
+		cfg := config.PayrollCalendarConfig{Frequency: config.PayrollFrequencyBiWeekly, AnchorDate: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)}
+		assert.Equal(t, []string{"2025-02-03..2025-02-16"}, generate(&stubCalendarPeriodRepo{}, cfg))
+	})
+
+	t.Run("continues from the latest period without gaps", func(t *testing.T) {
+		repo := &stubCalendarPeriodRepo{periods: []*models.PayrollPeriod{{
+			StartDate: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+		}}}
+		cfg := config.PayrollCalendarConfig{Frequency: config.PayrollFrequencyMonthly, PeriodsAhead: 0, PeriodsBehind: 1}
+		assert.Equal(t, []string{"2025-01-21..2025-01-31", "2025-02-01..2025-02-28"}, generate(repo, cfg))
+		assert.Empty(t, generate(repo, cfg))
+	})
+
+	t.Run("limits the backfill after a long gap", func(t *testing.T) {
+		repo := &stubCalendarPeriodRepo{periods: []*models.PayrollPeriod{{
+			StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		}}}
+		cfg := config.PayrollCalendarConfig{Frequency: config.PayrollFrequencyMonthly, PeriodsAhead: 0, PeriodsBehind: 1}
+		assert.Equal(t, []string{"2025-01-01..2025-01-31", "2025-02-01..2025-02-28"}, generate(repo, cfg))
+	})
+}
+
+func TestPayrollPeriodValidation(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+	repo := &stubCalendarPeriodRepo{periods: []*models.PayrollPeriod{{StartDate: day(1, 1), EndDate: day(1, 31)}}}
+	service := services.NewPayrollPeriodService(repo, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	_, err := service.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: day(2, 10), EndDate: day(2, 1)})
+	assert.ErrorIs(t, err, services.ErrInvalidPeriodRange)
+
+	_, err = service.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: day(1, 25), EndDate: day(2, 28)})
+	assert.ErrorIs(t, err, services.ErrPeriodOverlap)
+
+	_, err = service.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: day(2, 5), EndDate: day(2, 28)})
+	assert.ErrorIs(t, err, services.ErrPeriodGap)
+
+	_, err = service.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: day(2, 1), EndDate: day(2, 28)})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,108 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubEmployeeRepo struct {
+	repositories.UserRepository
+	employees []*models.User
+}
+
+func (r *stubEmployeeRepo) GetAllEmployee(offset int, limit int) ([]*models.User, error) {
+	if offset >= len(r.employees) {
+		return nil, nil
+	}
+	return r.employees[offset:min(offset+limit, len(r.employees))], nil
+}
+
+type stubPreviewPeriodRepo struct {
+	repositories.PayrollPeriodRepository
+	period, previous *models.PayrollPeriod
+}
+
+func (r *stubPreviewPeriodRepo) FindByID(id uint) (*models.PayrollPeriod, error) {
+	return r.period, nil
+}
+func (r *stubPreviewPeriodRepo) FindPrevious(startDate string) (*models.PayrollPeriod, error) {
+	return r.previous, nil
+}
+func (r *stubPreviewPeriodRepo) MarkAsProcessed(id uint) error {
+	panic("preview must not process the period")
+}
+
+type stubPayslipService struct {
+	services.PayslipService
+	payslips map[uint]*models.Payslip
+	previous []models.PayslipSummaryItem
+}
+
+func (s *stubPayslipService) PreviewPayslip(userID uint, period *models.PayrollPeriod, monthlySalary float64) (*models.Payslip, error) {
+	return s.payslips[userID], nil
+}
+func (s *stubPayslipService) GeneratePayslip(ctx context.Context, userID uint, periodID uint, monthlySalary float64) error {
+	panic("preview must not save payslips")
+}
+func (s *stubPayslipService) GetSummary(periodID uint) (*models.PayslipSummary, float64, error) {
+	total := 0.0
+	for _, item := range s.previous {
+		total += item.TakeHomePay
+	}
+	return &models.PayslipSummary{Items: s.previous}, total, nil
+}
+
+func TestPreviewPayroll(t *testing.T) {
+	salary := 5000000.0
+	employee := func(id uint, salary *float64) *models.User {
+		return &models.User{Model: gorm.Model{ID: id}, Name: "Employee", MonthlySalary: salary}
+	}
+	periodRepo := &stubPreviewPeriodRepo{
+		period:   &models.PayrollPeriod{BaseModel: models.BaseModel{Model: gorm.Model{ID: 2}}, StartDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		previous: &models.PayrollPeriod{BaseModel: models.BaseModel{Model: gorm.Model{ID: 1}}},
+	}
+	userRepo := &stubEmployeeRepo{employees: []*models.User{
+		employee(1, &salary), employee(2, &salary), employee(3, &salary), employee(4, nil),
+	}}
+	payslipService := &stubPayslipService{
+		payslips: map[uint]*models.Payslip{
+			1: {UserID: 1, AttendanceDays: 20, TakeHomePay: 1050},
+			2: {UserID: 2, AttendanceDays: 20, TakeHomePay: 1500},
+			3: {UserID: 3, AttendanceDays: 0, TakeHomePay: 0},
+		},
+		previous: []models.PayslipSummaryItem{{UserID: 1, TakeHomePay: 1000}, {UserID: 2, TakeHomePay: 1000}},
+	}
+	service := services.NewPayrollPeriodService(periodRepo, userRepo, payslipService, nil, nil)
+
+	preview, err := service.PreviewPayroll(2, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), *preview.PreviousPayrollPeriodID)
+	assert.Equal(t, 4, preview.Employees)
+	assert.Equal(t, 2550.0, preview.TotalTakeHomePay)
+	assert.Equal(t, 2000.0, preview.PreviousTotalTakeHomePay)
+
+	assert.Equal(t, 5.0, *preview.Rows[0].TakeHomePayChangePct)
+	assert.Empty(t, preview.Rows[0].Anomalies)
+	assert.Equal(t, 500.0, *preview.Rows[1].TakeHomePayChange)
+	assert.Equal(t, []string{models.AnomalyTakeHomeSwing}, preview.Rows[1].Anomalies)
+	assert.Nil(t, preview.Rows[2].PreviousTakeHomePay)
+	assert.Equal(t, []string{models.AnomalyZeroAttendance}, preview.Rows[2].Anomalies)
+	assert.Nil(t, preview.Rows[3].Payslip)
+	assert.Equal(t, []string{models.AnomalyMissingSalary}, preview.Rows[3].Anomalies)
+	assert.Equal(t, map[string]int{
+		models.AnomalyTakeHomeSwing:  1,
+		models.AnomalyZeroAttendance: 1,
+		models.AnomalyMissingSalary:  1,
+	}, preview.Anomalies)
+
+	periodRepo.period.IsProcessed = true
+	_, err = service.PreviewPayroll(2, 20)
+	assert.Error(t, err)
+}
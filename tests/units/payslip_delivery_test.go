@@ -0,0 +1,291 @@
+package units
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/mailer"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubDeliveryRepo struct {
+	repositories.PayslipDeliveryRepository
+	deliveries []*models.PayslipDelivery
+}
+
+func (r *stubDeliveryRepo) ClaimDueDeliveries(now, claimUntil time.Time, limit int) ([]*models.PayslipDelivery, error) {
+	var due []*models.PayslipDelivery
+	for _, delivery := range r.deliveries {
+		claimable := delivery.Status == models.PayslipDeliveryPending || delivery.Status == models.PayslipDeliverySending
+		if claimable && (delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.After(now)) {
+			delivery.Status = models.PayslipDeliverySending
+			delivery.NextAttemptAt = &claimUntil
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+func (r *stubDeliveryRepo) HasDelivery(userID, periodID uint) (bool, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.UserID == userID && delivery.PayrollPeriodID == periodID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (r *stubDeliveryRepo) CreateDelivery(ctx context.Context, delivery *models.PayslipDelivery) (*models.PayslipDelivery, error) {
+	r.deliveries = append(r.deliveries, delivery)
+	return delivery, nil
+}
+func (r *stubDeliveryRepo) UpdateDelivery(ctx context.Context, delivery *models.PayslipDelivery) (*models.PayslipDelivery, error) {
+	return delivery, nil
+}
+
+type stubDeliveryPayslipRepo struct {
+	repositories.PayslipRepository
+	payslips map[uint]*models.Payslip
+}
+
+func (r *stubDeliveryPayslipRepo) GetByPeriod(periodID uint) ([]*models.Payslip, error) {
+	var payslips []*models.Payslip
+	for _, payslip := range r.payslips {
+		payslips = append(payslips, payslip)
+	}
+	return payslips, nil
+}
+func (r *stubDeliveryPayslipRepo) GetByUserAndPeriod(userID uint, periodID uint) (*models.Payslip, error) {
+	if payslip, ok := r.payslips[userID]; ok {
+		return payslip, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type stubMailer struct {
+	failures int
+	sent     []mailer.Message
+}
+
+func (m *stubMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("connection refused")
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// smtpSink accepts one connection and records the message, like MailHog would.
+func smtpSink(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	received := make(chan string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 sink ready")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+			case "DATA":
+				text.PrintfLine("354 end with <CR><LF>.<CR><LF>")
+				data, _ := text.ReadDotBytes()
+				received <- string(data)
+				text.PrintfLine("250 queued")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				return
+			default:
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestPayslipDelivery(t *testing.T) {
+	code := "EMP-001"
+	userRepo := &stubUserRepo{users: map[uint]*models.User{
+		1: {Model: gorm.Model{ID: 1}, Name: "Ayu", Email: "ayu@example.com", EmployeeCode: &code},
+		2: {Model: gorm.Model{ID: 2}, Name: "Budi", Email: "budi@example.com"},
+	}}
+	periodRepo := &stubReopenPeriodRepo{period: &models.PayrollPeriod{
+		StartDate: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC),
+	}}
+	payslipRepo := &stubDeliveryPayslipRepo{payslips: map[uint]*models.Payslip{
+		1: {UserID: 1, AttendanceDays: 20, AttendanceEarnings: 200000, LoanDeduction: 300000, TakeHomePay: 3700000,
+			Lines: []models.PayslipLine{{Type: models.PayslipLineAllowance, Description: "Transport", Amount: 500000}}},
+		2: {UserID: 2, AttendanceDays: 20, AttendanceEarnings: 200000, TakeHomePay: 4000000},
+	}}
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	cfg := config.PayslipEmailConfig{Enabled: true, MaxAttempts: 3, RetryInterval: 5 * time.Minute}
+
+	t.Run("retries with a doubling delay and fails after the last attempt", func(t *testing.T) {
+		delivery := &models.PayslipDelivery{UserID: 1, Recipient: "ayu@example.com", Status: models.PayslipDeliveryPending}
+		smtp := &stubMailer{failures: 3}
+		service := services.NewPayslipDeliveryService(&stubDeliveryRepo{deliveries: []*models.PayslipDelivery{delivery}},
+			payslipRepo, userRepo, periodRepo, smtp, cfg)
+
+		sent, err := service.DeliverDue(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Equal(t, models.PayslipDeliveryPending, delivery.Status)
+		assert.Equal(t, now.Add(5*time.Minute), *delivery.NextAttemptAt)
+
+		sent, _ = service.DeliverDue(context.Background(), now.Add(time.Minute))
+		assert.Equal(t, 0, sent)
+		assert.Equal(t, 1, delivery.Attempts)
+
+		_, _ = service.DeliverDue(context.Background(), now.Add(5*time.Minute))
+		assert.Equal(t, now.Add(15*time.Minute), *delivery.NextAttemptAt)
+
+		_, _ = service.DeliverDue(context.Background(), now.Add(15*time.Minute))
+		assert.Equal(t, models.PayslipDeliveryFailed, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.Equal(t, "connection refused", *delivery.LastError)
+		assert.Empty(t, smtp.sent)
+	})
+
+	t.Run("leaves deliveries another run has claimed alone until the claim expires", func(t *testing.T) {
+		claimedUntil := now.Add(10 * time.Minute)
+		delivery := &models.PayslipDelivery{UserID: 1, Recipient: "ayu@example.com", Status: models.PayslipDeliverySending, NextAttemptAt: &claimedUntil}
+		smtp := &stubMailer{}
+		service := services.NewPayslipDeliveryService(&stubDeliveryRepo{deliveries: []*models.PayslipDelivery{delivery}},
+			payslipRepo, userRepo, periodRepo, smtp, cfg)
+
+		sent, err := service.DeliverDue(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Empty(t, smtp.sent)
+
+		sent, _ = service.DeliverDue(context.Background(), claimedUntil)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, models.PayslipDeliverySent, delivery.Status)
+	})
+
+	t.Run("records the send and protects the PDF with the employee's password", func(t *testing.T) {
+		ayu := &models.PayslipDelivery{UserID: 1, Recipient: "ayu@example.com", Status: models.PayslipDeliveryPending}
+		budi := &models.PayslipDelivery{UserID: 2, Recipient: "budi@example.com", Status: models.PayslipDeliveryPending}
+		voided := &models.PayslipDelivery{UserID: 3, Recipient: "citra@example.com", Status: models.PayslipDeliveryPending}
+		smtp := &stubMailer{}
+		cfg := cfg
+		cfg.AttachPDF, cfg.ProtectPDF, cfg.PDFSecret = true, true, "payslip-secret"
+		service := services.NewPayslipDeliveryService(&stubDeliveryRepo{deliveries: []*models.PayslipDelivery{ayu, budi, voided}},
+			payslipRepo, userRepo, periodRepo, smtp, cfg)
+
+		sent, err := service.DeliverDue(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		assert.Equal(t, models.PayslipDeliverySent, ayu.Status)
+		assert.Equal(t, now, *ayu.SentAt)
+		assert.Equal(t, models.PayslipDeliveryFailed, voided.Status, "a missing payslip is not retried")
+
+		assert.Equal(t, "Your payslip for 01 May 2025 - 31 May 2025", smtp.sent[0].Subject)
+		assert.Len(t, smtp.sent[0].Attachments, 1)
+		assert.Contains(t, string(smtp.sent[0].Attachments[0].Data), "/Encrypt")
+		assert.NotContains(t, string(smtp.sent[0].Attachments[0].Data), "Take-home pay")
+		assert.NotContains(t, smtp.sent[0].Body, code)
+		assert.Len(t, smtp.sent[1].Attachments, 1, "employees without a code get a protected PDF too")
+
+		password, err := service.PDFPassword(1)
+		assert.NoError(t, err)
+		assert.Equal(t, utils.PayslipPDFPassword("payslip-secret", 1), password)
+		assert.NotEqual(t, code, password)
+		other, _ := service.PDFPassword(2)
+		assert.NotEqual(t, password, other)
+	})
+
+	t.Run("asks employees to log in when no PDF secret is configured", func(t *testing.T) {
+		smtp := &stubMailer{}
+		delivery := &models.PayslipDelivery{UserID: 1, Recipient: "ayu@example.com", Status: models.PayslipDeliveryPending}
+		cfg := cfg
+		cfg.AttachPDF, cfg.ProtectPDF = true, true
+		service := services.NewPayslipDeliveryService(&stubDeliveryRepo{deliveries: []*models.PayslipDelivery{delivery}},
+			payslipRepo, userRepo, periodRepo, smtp, cfg)
+
+		_, err := service.DeliverDue(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Empty(t, smtp.sent[0].Attachments)
+		assert.Contains(t, smtp.sent[0].Body, "Please log in to view it")
+		_, err = service.PDFPassword(1)
+		assert.ErrorIs(t, err, services.ErrPDFProtectionOff)
+	})
+
+	t.Run("does not queue a payslip that was already emailed", func(t *testing.T) {
+		sentAt := now
+		repo := &stubDeliveryRepo{deliveries: []*models.PayslipDelivery{
+			{UserID: 1, PayrollPeriodID: 7, Recipient: "ayu@example.com", Status: models.PayslipDeliverySent, SentAt: &sentAt},
+		}}
+		payslipRepo := &stubDeliveryPayslipRepo{payslips: map[uint]*models.Payslip{
+			1: {UserID: 1, PayrollPeriodID: 7, User: models.User{Email: "ayu@example.com"}},
+			2: {UserID: 2, PayrollPeriodID: 7, User: models.User{Email: "budi@example.com"}},
+		}}
+		service := services.NewPayslipDeliveryService(repo, payslipRepo, userRepo, periodRepo, &stubMailer{}, cfg)
+
+		queued, err := service.QueuePeriod(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, queued)
+		assert.Len(t, repo.deliveries, 2)
+		assert.Equal(t, uint(2), repo.deliveries[1].UserID)
+
+		queued, _ = service.QueuePeriod(context.Background(), 7)
+		assert.Equal(t, 0, queued, "a rerun leaves queued payslips alone")
+	})
+
+	t.Run("puts the payslip in the body when PDFs are off", func(t *testing.T) {
+		smtp := &stubMailer{}
+		delivery := &models.PayslipDelivery{UserID: 1, Recipient: "ayu@example.com", Status: models.PayslipDeliveryPending}
+		service := services.NewPayslipDeliveryService(&stubDeliveryRepo{deliveries: []*models.PayslipDelivery{delivery}},
+			payslipRepo, userRepo, periodRepo, smtp, cfg)
+
+		_, err := service.DeliverDue(context.Background(), now)
+		assert.NoError(t, err)
+		body := smtp.sent[0].Body
+		assert.Contains(t, body, "Attendance (20 days x 200,000.00)")
+		assert.Contains(t, body, "Transport")
+		assert.Contains(t, body, "Loan installments")
+		assert.Regexp(t, `Take-home pay\s+3,700,000.00`, body)
+	})
+
+	t.Run("talks plain SMTP to a local sink", func(t *testing.T) {
+		addr, received := smtpSink(t)
+		host, port, _ := net.SplitHostPort(addr)
+		smtp := mailer.NewSMTPMailer(config.SMTPConfig{Host: host, Port: port, From: "payroll@localhost", Timeout: 5 * time.Second})
+
+		err := smtp.Send(context.Background(), mailer.Message{
+			To:          "ayu@example.com",
+			Subject:     "Your payslip",
+			Body:        "Hello",
+			Attachments: []mailer.Attachment{{Filename: "payslip.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4")}},
+		})
+		assert.NoError(t, err)
+		message := <-received
+		assert.Contains(t, message, "To: ayu@example.com")
+		assert.Contains(t, message, "Subject: Your payslip")
+		assert.Contains(t, message, `Content-Disposition: attachment; filename=payslip.pdf`)
+		reader := bufio.NewReader(strings.NewReader(message))
+		header, err := textproto.NewReader(reader).ReadMIMEHeader()
+		assert.NoError(t, err)
+		assert.Contains(t, header.Get("Content-Type"), "multipart/mixed")
+	})
+}
@@ -0,0 +1,104 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubHistoryPayslipRepo struct {
+	repositories.PayslipRepository
+	payslips []*models.Payslip // earliest period first
+}
+
+func (r *stubHistoryPayslipRepo) GetHistoryByUser(userID uint, pagination utils.Pagination) ([]*models.Payslip, int64, error) {
+	var history []*models.Payslip
+	for i := len(r.payslips) - 1; i >= 0; i-- {
+		history = append(history, r.payslips[i])
+	}
+	return history, int64(len(history)), nil
+}
+func (r *stubHistoryPayslipRepo) GetByUserBetween(userID uint, startDate, endDate string) ([]*models.Payslip, error) {
+	var payslips []*models.Payslip
+	for _, payslip := range r.payslips {
+		end := payslip.PayrollPeriod.EndDate.Format("2006-01-02")
+		if end >= startDate && end <= endDate {
+			payslips = append(payslips, payslip)
+		}
+	}
+	return payslips, nil
+}
+
+type stubStatementOffCycleRepo struct {
+	repositories.OffCycleRepository
+	payslips []*models.OffCyclePayslip
+}
+
+func (r *stubStatementOffCycleRepo) GetProcessedPayslipsBetween(userID uint, startDate, endDate string) ([]*models.OffCyclePayslip, error) {
+	var payslips []*models.OffCyclePayslip
+	for _, payslip := range r.payslips {
+		paid := payslip.Run.PayDate.Format("2006-01-02")
+		if paid >= startDate && paid <= endDate {
+			payslips = append(payslips, payslip)
+		}
+	}
+	return payslips, nil
+}
+
+func historyPayslip(periodID uint, end time.Time, days int, deduction, reimbursement float64) *models.Payslip {
+	payslip := &models.Payslip{
+		PayrollPeriodID:    periodID,
+		PayrollPeriod:      &models.PayrollPeriod{EndDate: end},
+		AttendanceDays:     days,
+		AttendanceEarnings: 100000,
+		TotalReimbursement: reimbursement,
+		LateDeduction:      deduction,
+	}
+	payslip.TakeHomePay = payslip.GrossPay() - payslip.Deductions()
+	return payslip
+}
+
+func TestPayslipHistory(t *testing.T) {
+	payslipRepo := &stubHistoryPayslipRepo{payslips: []*models.Payslip{
+		historyPayslip(1, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), 20, 0, 0),
+		historyPayslip(2, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), 20, 50000, 0),
+		historyPayslip(3, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), 18, 0, 250000),
+		historyPayslip(4, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), 21, 10000, 0),
+	}}
+	offCycleRepo := &stubStatementOffCycleRepo{payslips: []*models.OffCyclePayslip{{
+		OffCycleRunID: 7,
+		TotalEarnings: 1500000,
+		Run:           &models.OffCycleRun{Type: models.OffCycleTypeTHR, PayDate: time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)},
+	}}}
+	userRepo := &stubUserRepo{users: map[uint]*models.User{1: {Name: "Budi"}}}
+	service := services.NewPayslipService(payslipRepo, nil, nil, nil, nil, userRepo, nil, nil, offCycleRepo, nil, nil, config.PayrollConfig{})
+
+	t.Run("year-to-date totals restart each year and include off-cycle pay", func(t *testing.T) {
+		payslips, total, err := service.GetPayslipHistory(1, utils.Pagination{Page: 1, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Equal(t, models.PayslipTotals{Gross: 7650000, Deductions: 60000, Net: 7590000}, *payslips[0].YearToDate)
+		assert.Equal(t, models.PayslipTotals{Gross: 4050000, Deductions: 50000, Net: 4000000}, *payslips[1].YearToDate)
+		assert.Equal(t, models.PayslipTotals{Gross: 2000000, Deductions: 50000, Net: 1950000}, *payslips[2].YearToDate)
+		assert.Equal(t, models.PayslipTotals{Gross: 2000000, Net: 2000000}, *payslips[3].YearToDate)
+	})
+
+	t.Run("earnings statement lists the year's payslips in pay date order", func(t *testing.T) {
+		statement, err := service.GetEarningsStatement(1, 2025)
+		assert.NoError(t, err)
+		assert.Equal(t, "Budi", statement.Name)
+		assert.Equal(t, 5900000.0, statement.AttendanceEarnings)
+		assert.Equal(t, 250000.0, statement.Reimbursements)
+		assert.Equal(t, 1500000.0, statement.OffCycleEarnings)
+		assert.Equal(t, models.PayslipTotals{Gross: 7650000, Deductions: 60000, Net: 7590000}, statement.Totals)
+		assert.Len(t, statement.Lines, 4)
+		assert.Equal(t, models.OffCycleTypeTHR, statement.Lines[2].Type)
+		assert.Equal(t, "payroll", statement.Lines[3].Type)
+	})
+}
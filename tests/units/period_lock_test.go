@@ -0,0 +1,95 @@
+package units
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/handlers"
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/gin-gonic/gin"
+	redismock "github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPeriodLock(t *testing.T) {
+	const userID uint = 1
+	gin.SetMode(gin.TestMode)
+	date := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	userRepo := &stubUserRepo{users: map[uint]*models.User{userID: {Model: gorm.Model{ID: userID}, Role: models.Role{Name: models.RoleUser}}}}
+
+	type fixture struct {
+		router            *gin.Engine
+		attendanceRepo    *stubSessionAttendanceRepo
+		overtimeRepo      *stubOvertimeRepo
+		reimbursementRepo *stubReimbursementRepo
+		cache             redismock.ClientMock
+	}
+	setup := func(locked bool) fixture {
+		cache, mock := redismock.NewClientMock()
+		periodRepo := &stubPayrollPeriodRepo{locked: locked}
+		f := fixture{
+			cache:          mock,
+			attendanceRepo: &stubSessionAttendanceRepo{},
+			overtimeRepo: &stubOvertimeRepo{overtimes: map[uint]*models.Overtime{
+				10: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 10}}, UserID: userID, Date: date, Hours: 2},
+			}},
+			reimbursementRepo: &stubReimbursementRepo{reimbursements: map[uint]*models.Reimbursement{
+				20: {BaseModel: models.BaseModel{Model: gorm.Model{ID: 20}}, UserID: userID, Date: date, Amount: 50000},
+			}},
+		}
+		attendanceHandler := handlers.NewAttendanceHandler(services.NewAttendanceService(f.attendanceRepo, &stubNoSiteRepo{}, &stubAllDayShiftRepo{}, userRepo, periodRepo, nil, nil, cache, config.AttendanceConfig{}), nil)
+		overtimeHandler := handlers.NewOvertimeHandler(services.NewOvertimeService(f.overtimeRepo, nil, periodRepo, userRepo, cache, config.OvertimeConfig{}), nil)
+		reimbursementHandler := handlers.NewReimbursementHandler(services.NewReimbursementService(f.reimbursementRepo, nil, periodRepo, userRepo, cache), nil)
+
+		f.router = gin.New()
+		f.router.Use(func(ctx *gin.Context) { ctx.Set("user_id", userID) })
+		f.router.POST("/attendances/check-in", attendanceHandler.CheckIn)
+		f.router.DELETE("/overtimes/:id", overtimeHandler.DeleteOvertime)
+		f.router.DELETE("/reimbursements/:id", reimbursementHandler.DeleteReimbursement)
+		return f
+	}
+	serve := func(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	t.Run("attendance", func(t *testing.T) {
+		f := setup(true)
+		assert.Equal(t, http.StatusConflict, serve(f.router, http.MethodPost, "/attendances/check-in").Code)
+		assert.Nil(t, f.attendanceRepo.attendance)
+
+		f = setup(false)
+		assert.Equal(t, http.StatusOK, serve(f.router, http.MethodPost, "/attendances/check-in").Code)
+		assert.NotNil(t, f.attendanceRepo.attendance)
+	})
+
+	t.Run("overtime", func(t *testing.T) {
+		f := setup(true)
+		response := serve(f.router, http.MethodDelete, "/overtimes/10")
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.Contains(t, response.Body.String(), "(2025-06-02)")
+		assert.Empty(t, f.overtimeRepo.deleted)
+
+		f = setup(false)
+		f.cache.ExpectKeys("overtime:*").SetVal(nil)
+		assert.Equal(t, http.StatusNoContent, serve(f.router, http.MethodDelete, "/overtimes/10").Code)
+		assert.Equal(t, []uint{10}, f.overtimeRepo.deleted)
+	})
+
+	t.Run("reimbursement", func(t *testing.T) {
+		f := setup(true)
+		assert.Equal(t, http.StatusConflict, serve(f.router, http.MethodDelete, "/reimbursements/20").Code)
+		assert.Empty(t, f.reimbursementRepo.deleted)
+
+		f = setup(false)
+		f.cache.ExpectKeys("reimbursement:*").SetVal(nil)
+		assert.Equal(t, http.StatusNoContent, serve(f.router, http.MethodDelete, "/reimbursements/20").Code)
+		assert.Equal(t, []uint{20}, f.reimbursementRepo.deleted)
+	})
+}
@@ -0,0 +1,129 @@
+package units
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubPunchAttendanceRepo records the writes made outside a transaction and can fail creating
+// sessions, as a lost database connection would.
+type stubPunchAttendanceRepo struct {
+	stubSessionAttendanceRepo
+	failSessions  bool
+	inTransaction bool
+	deleted       int
+	outside       []string
+}
+
+func (r *stubPunchAttendanceRepo) Transaction(fn func(repo repositories.AttendanceRepository) error) error {
+	r.inTransaction = true
+	defer func() { r.inTransaction = false }()
+	return fn(r)
+}
+func (r *stubPunchAttendanceRepo) write(name string) {
+	if !r.inTransaction {
+		r.outside = append(r.outside, name)
+	}
+}
+func (r *stubPunchAttendanceRepo) DeleteDeviceSessions(attendanceID uint) error {
+	r.write("DeleteDeviceSessions")
+	r.deleted++
+	return nil
+}
+func (r *stubPunchAttendanceRepo) CreateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error) {
+	r.write("CreateSession")
+	if r.failSessions {
+		return nil, errors.New("connection lost")
+	}
+	return r.stubSessionAttendanceRepo.CreateSession(ctx, session)
+}
+func (r *stubPunchAttendanceRepo) UpdateAttendance(ctx context.Context, att *models.Attendance) (*models.Attendance, error) {
+	r.write("UpdateAttendance")
+	return r.stubSessionAttendanceRepo.UpdateAttendance(ctx, att)
+}
+
+func TestPairPunches(t *testing.T) {
+	at := func(hour, minute, second int) time.Time {
+		return time.Date(2025, 6, 10, hour, minute, second, 0, time.UTC)
+	}
+
+	t.Run("Pairs punches in time order", func(t *testing.T) {
+		// The lunch punches arrive after the end of day punch
+		pairs := utils.PairPunches([]time.Time{at(8, 55, 0), at(17, 2, 0), at(12, 0, 0), at(13, 1, 0)}, time.Minute)
+		assert.Len(t, pairs, 2)
+		assert.Equal(t, at(8, 55, 0), pairs[0].In)
+		assert.Equal(t, at(12, 0, 0), *pairs[0].Out)
+		assert.Equal(t, at(13, 1, 0), pairs[1].In)
+		assert.Equal(t, at(17, 2, 0), *pairs[1].Out)
+	})
+
+	t.Run("Ignores repeated taps", func(t *testing.T) {
+		pairs := utils.PairPunches([]time.Time{at(8, 55, 0), at(8, 55, 20), at(17, 0, 0)}, time.Minute)
+		assert.Len(t, pairs, 1)
+		assert.Equal(t, at(8, 55, 0), pairs[0].In)
+		assert.Equal(t, at(17, 0, 0), *pairs[0].Out)
+	})
+
+	t.Run("Odd punch count leaves the last pair open", func(t *testing.T) {
+		pairs := utils.PairPunches([]time.Time{at(8, 55, 0), at(12, 0, 0), at(13, 0, 0)}, time.Minute)
+		assert.Len(t, pairs, 2)
+		assert.Nil(t, pairs[1].Out)
+	})
+}
+
+func TestApplyPunches(t *testing.T) {
+	at := func(hour int) *time.Time {
+		tm := time.Date(2025, 6, 2, hour, 0, 0, 0, time.UTC)
+		return &tm
+	}
+	deviceID := uint(5)
+	user := &models.User{Model: gorm.Model{ID: 1}}
+	date := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	punches := []*models.Punch{{DeviceID: deviceID, PunchedAt: *at(8)}, {DeviceID: deviceID, PunchedAt: *at(17)}}
+	setup := func() (services.AttendanceService, *stubPunchAttendanceRepo) {
+		cache, _ := redismock.NewClientMock()
+		repo := &stubPunchAttendanceRepo{stubSessionAttendanceRepo: stubSessionAttendanceRepo{attendance: &models.Attendance{
+			BaseModel: models.BaseModel{Model: gorm.Model{ID: 1}},
+			UserID:    1,
+			Date:      date,
+			Sessions: []models.AttendanceSession{
+				{CheckIn: at(12), CheckOut: at(13)},
+				{CheckIn: at(9), CheckOut: at(10), DeviceID: &deviceID},
+			},
+		}}}
+		service := services.NewAttendanceService(repo, &stubNoSiteRepo{}, &stubAllDayShiftRepo{}, nil, &stubPayrollPeriodRepo{}, nil, nil, cache,
+			config.AttendanceConfig{PunchDebounce: time.Minute})
+		return service, repo
+	}
+
+	t.Run("rebuilds the device sessions and keeps the app ones", func(t *testing.T) {
+		service, repo := setup()
+		att, err := service.ApplyPunches(context.Background(), user, date, punches)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, repo.deleted)
+		assert.Len(t, att.Sessions, 2)
+		assert.Nil(t, att.Sessions[1].DeviceID)
+		assert.Equal(t, *at(8), *att.CheckIn)
+		assert.Empty(t, repo.outside)
+	})
+
+	t.Run("a failed session insert is rolled back with the delete", func(t *testing.T) {
+		service, repo := setup()
+		repo.failSessions = true
+		_, err := service.ApplyPunches(context.Background(), user, date, punches)
+		assert.EqualError(t, err, "connection lost")
+		assert.Equal(t, 1, repo.deleted)
+		assert.Empty(t, repo.outside, "the delete ran in the transaction the failure rolls back")
+	})
+}
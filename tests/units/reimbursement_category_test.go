@@ -0,0 +1,218 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubCategoryUsageRepo struct {
+	repositories.ReimbursementRepository
+	claims []*models.Reimbursement
+}
+
+func (r *stubCategoryUsageRepo) SumCategoryUsage(userID, categoryID uint, startDate, endDate string, excludeID uint) (float64, error) {
+	var total float64
+	for _, claim := range r.claims {
+		date := claim.Date.Format("2006-01-02")
+		if claim.UserID == userID && *claim.CategoryID == categoryID && claim.ID != excludeID &&
+			date >= startDate && date <= endDate {
+			total += claim.Amount
+		}
+	}
+	return total, nil
+}
+func (r *stubCategoryUsageRepo) CreateReimbursement(ctx context.Context, reimbursement *models.Reimbursement, check repositories.BudgetCheck) (*models.Reimbursement, error) {
+	if err := check(r); err != nil {
+		return nil, err
+	}
+	reimbursement.ID = uint(len(r.claims) + 10)
+	r.claims = append(r.claims, reimbursement)
+	return reimbursement, nil
+}
+func (r *stubCategoryUsageRepo) UpdateReimbursement(ctx context.Context, reimbursement *models.Reimbursement, check repositories.BudgetCheck) (*models.Reimbursement, error) {
+	if err := check(r); err != nil {
+		return nil, err
+	}
+	return reimbursement, nil
+}
+func (r *stubCategoryUsageRepo) GetReimbursementByID(id uint) (*models.Reimbursement, error) {
+	for _, claim := range r.claims {
+		if claim.ID == id {
+			return claim, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type stubReimbursementCategoryRepo struct {
+	repositories.ReimbursementCategoryRepository
+	categories []*models.ReimbursementCategory
+}
+
+func (r *stubReimbursementCategoryRepo) GetCategoryList(activeOnly bool) ([]*models.ReimbursementCategory, error) {
+	var categories []*models.ReimbursementCategory
+	for _, category := range r.categories {
+		if category.IsActive || !activeOnly {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+func (r *stubReimbursementCategoryRepo) GetCategoryByID(id uint) (*models.ReimbursementCategory, error) {
+	for _, category := range r.categories {
+		if category.ID == id {
+			return category, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestReimbursementCategoryBudgets(t *testing.T) {
+	const employee, manager uint = 1, 2
+	grade := "M1"
+	employeeID := employee
+	userRepo := &stubUserRepo{users: map[uint]*models.User{
+		employee: {Model: gorm.Model{ID: employee}, Role: models.Role{Name: models.RoleUser}},
+		manager:  {Model: gorm.Model{ID: manager}, Grade: &grade, Role: models.Role{Name: models.RoleUser}},
+	}}
+	medicalID, travelID, mealsID := uint(1), uint(2), uint(3)
+	medical := &models.ReimbursementCategory{
+		BaseModel: models.BaseModel{Model: gorm.Model{ID: medicalID}}, Name: "Medical", IsActive: true,
+		MonthlyCap: floatPtr(1000000), YearlyCap: floatPtr(5000000),
+		Limits: []models.ReimbursementLimit{
+			{Grade: &grade, MonthlyCap: floatPtr(2000000)},
+		},
+	}
+	travel := &models.ReimbursementCategory{
+		BaseModel: models.BaseModel{Model: gorm.Model{ID: travelID}}, Name: "Travel", IsActive: true,
+		MonthlyCap: floatPtr(500000),
+		Limits: []models.ReimbursementLimit{
+			{Grade: &grade, MonthlyCap: floatPtr(800000)},
+			{UserID: &employeeID, MonthlyCap: floatPtr(700000)},
+		},
+	}
+	meals := &models.ReimbursementCategory{
+		BaseModel: models.BaseModel{Model: gorm.Model{ID: mealsID}}, Name: "Meals", IsActive: false,
+	}
+	june := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	reimbursementRepo := &stubCategoryUsageRepo{claims: []*models.Reimbursement{
+		{BaseModel: models.BaseModel{Model: gorm.Model{ID: 10}}, UserID: employee, CategoryID: &medicalID, Date: june, Amount: 600000},
+		{BaseModel: models.BaseModel{Model: gorm.Model{ID: 11}}, UserID: employee, CategoryID: &medicalID, Date: june, Amount: 150000},
+		{BaseModel: models.BaseModel{Model: gorm.Model{ID: 12}}, UserID: employee, CategoryID: &medicalID, Date: june.AddDate(0, -2, 0), Amount: 900000},
+	}}
+	cache, mock := redismock.NewClientMock()
+	service := services.NewReimbursementService(reimbursementRepo,
+		&stubReimbursementCategoryRepo{categories: []*models.ReimbursementCategory{medical, travel, meals}},
+		&stubPayrollPeriodRepo{}, userRepo, cache)
+
+	t.Run("an employee's own limit wins over their grade's and the category's", func(t *testing.T) {
+		monthly, yearly := travel.CapsFor(userRepo.users[employee])
+		assert.Equal(t, 700000.0, *monthly)
+		assert.Nil(t, yearly)
+		monthly, _ = travel.CapsFor(userRepo.users[manager])
+		assert.Equal(t, 800000.0, *monthly)
+		monthly, yearly = medical.CapsFor(userRepo.users[employee])
+		assert.Equal(t, 1000000.0, *monthly)
+		assert.Equal(t, 5000000.0, *yearly)
+	})
+
+	t.Run("reports usage of every active category", func(t *testing.T) {
+		budgets, err := service.GetBudgetUsage(employee, employee, june)
+		assert.NoError(t, err)
+		assert.Len(t, budgets, 2)
+		assert.Equal(t, "Medical", budgets[0].Category)
+		assert.Equal(t, 750000.0, budgets[0].MonthlyUsed)
+		assert.Equal(t, 250000.0, *budgets[0].MonthlyRemaining)
+		assert.Equal(t, 1650000.0, budgets[0].YearlyUsed)
+		assert.Equal(t, 3350000.0, *budgets[0].YearlyRemaining)
+		assert.Nil(t, budgets[1].YearlyRemaining)
+
+		_, err = service.GetBudgetUsage(manager, employee, june)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("rejects claims over the remaining budget", func(t *testing.T) {
+		_, err := service.SubmitReimbursement(context.Background(), &models.Reimbursement{
+			UserID: employee, CategoryID: &medicalID, Date: june, Amount: 300000,
+		})
+		assert.ErrorIs(t, err, services.ErrReimbursementBudgetExceeded)
+		assert.Len(t, reimbursementRepo.claims, 3)
+	})
+
+	t.Run("a saved claim counts against the next one", func(t *testing.T) {
+		mock.ExpectKeys("reimbursement:*").SetVal(nil)
+		mock.ExpectKeys("report:reimbursement:*").SetVal(nil)
+		_, err := service.SubmitReimbursement(context.Background(), &models.Reimbursement{
+			UserID: employee, CategoryID: &medicalID, Date: june, Amount: 200000,
+		})
+		assert.NoError(t, err)
+		_, err = service.SubmitReimbursement(context.Background(), &models.Reimbursement{
+			UserID: employee, CategoryID: &medicalID, Date: june, Amount: 100000,
+		})
+		assert.ErrorIs(t, err, services.ErrReimbursementBudgetExceeded)
+		assert.Contains(t, err.Error(), "Medical has 50000.00 left for June 2025")
+		reimbursementRepo.claims = reimbursementRepo.claims[:3]
+	})
+
+	t.Run("does not count an updated claim against itself", func(t *testing.T) {
+		_, err := service.UpdateReimbursement(context.Background(), employee, &models.Reimbursement{
+			BaseModel: models.BaseModel{Model: gorm.Model{ID: 11}}, CategoryID: &medicalID, Date: june, Amount: 450000,
+		})
+		assert.ErrorIs(t, err, services.ErrReimbursementBudgetExceeded)
+		assert.Contains(t, err.Error(), "Medical has 400000.00 left for June 2025")
+	})
+
+	t.Run("rejects claims without an active category", func(t *testing.T) {
+		_, err := service.SubmitReimbursement(context.Background(), &models.Reimbursement{
+			UserID: employee, CategoryID: &mealsID, Date: june, Amount: 50000,
+		})
+		assert.ErrorIs(t, err, services.ErrReimbursementCategoryInvalid)
+		_, err = service.SubmitReimbursement(context.Background(), &models.Reimbursement{
+			UserID: employee, Date: june, Amount: 50000,
+		})
+		assert.ErrorIs(t, err, services.ErrReimbursementCategoryInvalid)
+	})
+}
+
+func TestReimbursementLimitUniqueness(t *testing.T) {
+	grade := "G5"
+	userID := uint(1)
+	category := &models.ReimbursementCategory{
+		BaseModel: models.BaseModel{Model: gorm.Model{ID: 1}}, Name: "Medical", IsActive: true,
+		Limits: []models.ReimbursementLimit{{Grade: &grade}, {UserID: &userID}},
+	}
+	userRepo := &stubUserRepo{users: map[uint]*models.User{userID: {Model: gorm.Model{ID: userID}}}}
+	service := services.NewReimbursementCategoryService(&stubReimbursementCategoryRepo{categories: []*models.ReimbursementCategory{category}}, userRepo)
+
+	_, err := service.CreateLimit(context.Background(), 1, &models.ReimbursementLimitRequest{Grade: &grade, MonthlyCap: floatPtr(1000)})
+	assert.EqualError(t, err, "grade G5 already has a limit in this category")
+	_, err = service.CreateLimit(context.Background(), 1, &models.ReimbursementLimitRequest{UserID: &userID, MonthlyCap: floatPtr(1000)})
+	assert.EqualError(t, err, "user 1 already has a limit in this category")
+}
+
+func TestSetUserGrade(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserService(&stubUserRepo{users: map[uint]*models.User{1: {Model: gorm.Model{ID: 1}}}})
+	grade, empty := "G5", ""
+
+	user, err := service.SetGrade(ctx, 1, &grade)
+	assert.NoError(t, err)
+	assert.Equal(t, "G5", *user.Grade)
+
+	user, err = service.SetGrade(ctx, 1, &empty)
+	assert.NoError(t, err)
+	assert.Nil(t, user.Grade)
+
+	_, err = service.SetGrade(ctx, 2, &grade)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
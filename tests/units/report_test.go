@@ -0,0 +1,104 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	redismock "github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubReportRepo struct {
+	counts        []*models.EmployeeAttendanceRate
+	weekendShifts map[uint]int
+}
+
+func (r *stubReportRepo) GetAttendanceCounts(startDate, endDate string) ([]*models.EmployeeAttendanceRate, error) {
+	return r.counts, nil
+}
+func (r *stubReportRepo) GetWeekendShiftCounts(startDate, endDate string) (map[uint]int, error) {
+	return r.weekendShifts, nil
+}
+func (r *stubReportRepo) GetTopOvertimeUsers(startDate, endDate string, limit int) ([]*models.OvertimeUserReport, error) {
+	return nil, nil
+}
+func (r *stubReportRepo) GetPayrollCostByPeriod(startDate, endDate string) ([]*models.PayrollPeriodCost, error) {
+	return []*models.PayrollPeriodCost{{PayrollPeriodID: 1, OvertimeEarnings: 200}, {PayrollPeriodID: 2, OvertimeEarnings: 250}}, nil
+}
+func (r *stubReportRepo) GetReimbursementSpendByMonth(startDate, endDate string) ([]*models.MonthlySpend, error) {
+	return nil, nil
+}
+
+type stubLeaveRepo struct {
+	leaves []*models.Leave
+}
+
+func (r *stubLeaveRepo) GetLeaveList(userID uint, startDate, endDate string) ([]*models.Leave, error) {
+	return r.leaves, nil
+}
+func (r *stubLeaveRepo) GetLeaveByID(id uint) (*models.Leave, error) { return nil, nil }
+func (r *stubLeaveRepo) HasOverlappingLeave(userID uint, startDate, endDate string) (bool, error) {
+	return false, nil
+}
+func (r *stubLeaveRepo) CreateLeave(ctx context.Context, leave *models.Leave) (*models.Leave, error) {
+	return leave, nil
+}
+func (r *stubLeaveRepo) DeleteLeave(id uint) error { return nil }
+
+func TestReports(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	managerID := uint(9)
+	repo := &stubReportRepo{
+		counts: []*models.EmployeeAttendanceRate{
+			{UserID: 1, AttendedDays: 5},
+			{UserID: 2, AttendedDays: 3},
+			{UserID: 3, ManagerID: &managerID, AttendedDays: 2},
+		},
+		weekendShifts: map[uint]int{3: 1},
+	}
+	leaveRepo := &stubLeaveRepo{leaves: []*models.Leave{{UserID: 2, StartDate: day(5), EndDate: day(9)}}}
+	cache, _ := redismock.NewClientMock()
+	service := services.NewReportService(repo, leaveRepo, cache)
+
+	t.Run("attendance rate per employee and team", func(t *testing.T) {
+		report, err := service.GetAttendanceRateReport(day(2), day(6))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, report.Employees[0].ExpectedDays)
+		assert.Equal(t, 2, report.Employees[1].LeaveDays)
+		assert.Equal(t, 100.0, report.Employees[1].AttendanceRate)
+		assert.Equal(t, 6, report.Employees[2].ExpectedDays)
+		assert.Equal(t, 33.33, report.Employees[2].AttendanceRate)
+		assert.Len(t, report.Teams, 2)
+		assert.Equal(t, 100.0, report.Teams[0].AttendanceRate)
+		assert.Equal(t, 71.43, report.AttendanceRate)
+	})
+
+	t.Run("overtime cost trend compares with the previous period", func(t *testing.T) {
+		trend, err := service.GetOvertimeCostTrend(day(1), day(30))
+		assert.NoError(t, err)
+		assert.Nil(t, trend[0].Change)
+		assert.Equal(t, 25.0, *trend[1].Change)
+	})
+
+	t.Run("a range reaching into the future is cached for today only", func(t *testing.T) {
+		cache, mock := redismock.NewClientMock()
+		today := utils.DateIn(time.Now(), utils.CompanyLocation())
+		start, end := today.AddDate(0, 0, -7), today.AddDate(0, 0, 7)
+		key := utils.BuildKey("report:attendance", start.Format("2006-01-02"), end.Format("2006-01-02"), today.Format("2006-01-02"))
+		mock.ExpectGet(key).SetVal(`{"attendance_rate":42}`)
+
+		report, err := services.NewReportService(repo, leaveRepo, cache).GetAttendanceRateReport(start, end)
+		assert.NoError(t, err)
+		assert.Equal(t, 42.0, report.AttendanceRate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects a reversed range", func(t *testing.T) {
+		_, err := service.GetPayrollCostByPeriod(day(6), day(2))
+		assert.ErrorIs(t, err, services.ErrInvalidReportRange)
+	})
+}
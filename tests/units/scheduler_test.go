@@ -0,0 +1,29 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/pkg/jobs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEverySkipsNonPositiveInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ran := make(chan struct{}, 1)
+	assert.NotPanics(t, func() {
+		jobs.Every(ctx, "test", 0, func(ctx context.Context) error {
+			ran <- struct{}{}
+			return nil
+		})
+	})
+
+	select {
+	case <-ran:
+		t.Fatal("job with a zero interval should not run")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
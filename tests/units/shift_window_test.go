@@ -0,0 +1,35 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShiftWindow(t *testing.T) {
+	date := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Day shift", func(t *testing.T) {
+		shift := models.Shift{StartTime: "09:00", EndTime: "17:00"}
+		start, end, err := shift.Window(date, time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2025, 6, 10, 17, 0, 0, 0, time.UTC), end)
+	})
+
+	t.Run("Overnight shift ends the next day", func(t *testing.T) {
+		shift := models.Shift{StartTime: "22:00", EndTime: "06:00", CrossesMidnight: true}
+		start, end, err := shift.Window(date, time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 6, 10, 22, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2025, 6, 11, 6, 0, 0, 0, time.UTC), end)
+	})
+
+	t.Run("Invalid clock time", func(t *testing.T) {
+		shift := models.Shift{StartTime: "25:00", EndTime: "06:00"}
+		_, _, err := shift.Window(date, time.UTC)
+		assert.Error(t, err)
+	})
+}
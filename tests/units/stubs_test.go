@@ -0,0 +1,248 @@
+package units
+
+import (
+	"context"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// Stubs shared by several tests live here, so a repository interface change touches one file.
+// They embed their interface, so only the methods the tests reach need a body.
+
+type stubUserRepo struct {
+	repositories.UserRepository
+	users map[uint]*models.User
+}
+
+func (r *stubUserRepo) FindByEmail(email string) (*models.User, error) { return nil, nil }
+func (r *stubUserRepo) FindByID(id uint) (*models.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *stubUserRepo) FindByEmployeeCode(code string) (*models.User, error) {
+	return nil, nil
+}
+func (r *stubUserRepo) GetAllEmployee(offset int, limit int) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *stubUserRepo) UpdateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	r.users[user.ID] = user
+	return user, nil
+}
+
+// stubPayrollPeriodRepo locks every date when locked is set, and lockedDate on its own, as if
+// a processed period covered them.
+type stubPayrollPeriodRepo struct {
+	repositories.PayrollPeriodRepository
+	locked     bool
+	lockedDate string
+}
+
+func (r *stubPayrollPeriodRepo) IsDateLocked(date string) (bool, error) {
+	return r.locked || date == r.lockedDate, nil
+}
+
+type stubReopenPeriodRepo struct {
+	repositories.PayrollPeriodRepository
+	period   *models.PayrollPeriod
+	reopened []uint
+}
+
+func (r *stubReopenPeriodRepo) FindByID(id uint) (*models.PayrollPeriod, error) {
+	return r.period, nil
+}
+func (r *stubReopenPeriodRepo) Reopen(id uint) error {
+	r.period.IsProcessed = false
+	r.reopened = append(r.reopened, id)
+	return nil
+}
+
+// stubSessionAttendanceRepo keeps a single user's attendance day in memory.
+type stubSessionAttendanceRepo struct {
+	repositories.AttendanceRepository
+	attendance *models.Attendance
+	created    []models.AttendanceSession
+	updated    []models.AttendanceSession
+}
+
+func (r *stubSessionAttendanceRepo) GetAttendanceByID(id uint) (*models.Attendance, error) {
+	if r.attendance == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.attendance, nil
+}
+func (r *stubSessionAttendanceRepo) GetAttendanceByUserAndDate(userID uint, date string) (*models.Attendance, error) {
+	if r.attendance == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.attendance, nil
+}
+func (r *stubSessionAttendanceRepo) CreateAttendance(ctx context.Context, att *models.Attendance) (*models.Attendance, error) {
+	att.ID = 1
+	r.attendance = att
+	return att, nil
+}
+func (r *stubSessionAttendanceRepo) UpdateAttendance(ctx context.Context, att *models.Attendance) (*models.Attendance, error) {
+	r.attendance = att
+	return att, nil
+}
+func (r *stubSessionAttendanceRepo) CreateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error) {
+	r.created = append(r.created, *session)
+	return session, nil
+}
+func (r *stubSessionAttendanceRepo) UpdateSession(ctx context.Context, session *models.AttendanceSession) (*models.AttendanceSession, error) {
+	r.updated = append(r.updated, *session)
+	return session, nil
+}
+
+type stubAttendanceListRepo struct {
+	repositories.AttendanceRepository
+	attendances []*models.Attendance
+}
+
+func (r *stubAttendanceListRepo) GetAttendanceList(userID uint, startDate, endDate string) ([]*models.Attendance, error) {
+	return r.attendances, nil
+}
+
+// stubAllDayShiftRepo rosters every day on a shift spanning the whole day, so check-ins are
+// accepted whenever the test runs, weekends included.
+type stubAllDayShiftRepo struct {
+	repositories.ShiftRepository
+}
+
+func (r *stubAllDayShiftRepo) GetAssignmentByUserAndDate(userID uint, date string) (*models.ShiftAssignment, error) {
+	return &models.ShiftAssignment{ShiftID: 1, Shift: models.Shift{Name: "All day", StartTime: "00:00", EndTime: "23:59"}}, nil
+}
+
+type stubNoSiteRepo struct {
+	repositories.SiteRepository
+}
+
+func (r *stubNoSiteRepo) GetActiveSites() ([]*models.Site, error) { return nil, nil }
+
+type stubPayslipAttendanceRepo struct {
+	repositories.AttendanceRepository
+	attended  int64
+	penalties models.AttendancePenaltySummary
+}
+
+func (r *stubPayslipAttendanceRepo) CountWorkingDays(userID uint, startDate, endDate string) (int64, error) {
+	return r.attended, nil
+}
+func (r *stubPayslipAttendanceRepo) SumPenalties(userID uint, startDate, endDate string) (*models.AttendancePenaltySummary, error) {
+	penalties := r.penalties
+	return &penalties, nil
+}
+
+type stubPayslipOvertimeRepo struct {
+	repositories.OvertimeRepository
+	overtimes []*models.Overtime
+}
+
+func (r *stubPayslipOvertimeRepo) GetSubmittedOvertimes(userID uint, startDate, endDate string) ([]*models.Overtime, error) {
+	return r.overtimes, nil
+}
+
+type stubPayslipReimbursementRepo struct {
+	repositories.ReimbursementRepository
+}
+
+func (r *stubPayslipReimbursementRepo) SumReimbursement(userID uint, startDate, endDate string) (float64, error) {
+	return 0, nil
+}
+
+type stubDueLoanRepo struct {
+	repositories.LoanRepository
+	due []*models.LoanInstallment
+}
+
+func (r *stubDueLoanRepo) GetDueInstallments(userID uint, dueBy string) ([]*models.LoanInstallment, error) {
+	return r.due, nil
+}
+
+type stubAllowanceRepo struct {
+	repositories.AllowanceRepository
+	assignments []*models.AllowanceAssignment
+	adjustments []*models.PayrollAdjustment
+}
+
+func (r *stubAllowanceRepo) GetActiveAssignments(userID uint, startDate, endDate string) ([]*models.AllowanceAssignment, error) {
+	var active []*models.AllowanceAssignment
+	for _, assignment := range r.assignments {
+		if assignment.StartDate.Format("2006-01-02") <= endDate &&
+			(assignment.EndDate == nil || assignment.EndDate.Format("2006-01-02") >= startDate) {
+			active = append(active, assignment)
+		}
+	}
+	return active, nil
+}
+func (r *stubAllowanceRepo) GetAdjustmentList(periodID uint, userID uint) ([]*models.PayrollAdjustment, error) {
+	var adjustments []*models.PayrollAdjustment
+	for _, adjustment := range r.adjustments {
+		if adjustment.PayrollPeriodID == periodID && adjustment.UserID == userID {
+			adjustments = append(adjustments, adjustment)
+		}
+	}
+	return adjustments, nil
+}
+
+type stubOvertimeRepo struct {
+	repositories.OvertimeRepository
+	overtimes map[uint]*models.Overtime
+	deleted   []uint
+}
+
+func (r *stubOvertimeRepo) GetOvertimeByID(id uint) (*models.Overtime, error) {
+	if overtime, ok := r.overtimes[id]; ok {
+		copied := *overtime
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *stubOvertimeRepo) GetOvertimeList(userID uint, pagination utils.Pagination) ([]*models.Overtime, int64, error) {
+	var overtimes []*models.Overtime
+	for _, overtime := range r.overtimes {
+		if overtime.UserID == userID {
+			overtimes = append(overtimes, overtime)
+		}
+	}
+	return overtimes, int64(len(overtimes)), nil
+}
+func (r *stubOvertimeRepo) DeleteOvertime(id uint) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type stubReimbursementRepo struct {
+	repositories.ReimbursementRepository
+	reimbursements map[uint]*models.Reimbursement
+	deleted        []uint
+}
+
+func (r *stubReimbursementRepo) GetReimbursementByID(id uint) (*models.Reimbursement, error) {
+	if reimbursement, ok := r.reimbursements[id]; ok {
+		copied := *reimbursement
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *stubReimbursementRepo) DeleteReimbursement(id uint) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type stubNotificationService struct {
+	services.NotificationService
+	notified []uint
+}
+
+func (s *stubNotificationService) Notify(ctx context.Context, userID uint, title, message string) (*models.Notification, error) {
+	s.notified = append(s.notified, userID)
+	return &models.Notification{}, nil
+}
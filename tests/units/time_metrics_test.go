@@ -0,0 +1,104 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAttendanceTimeMetrics(t *testing.T) {
+	shift := &models.Shift{StartTime: "09:00", EndTime: "17:00", GracePeriodMinutes: 10}
+	at := func(hour, minute, second int) *time.Time {
+		tm := time.Date(2025, 6, 2, hour, minute, second, 0, time.UTC)
+		return &tm
+	}
+	session := func(in, out *time.Time) models.AttendanceSession {
+		return models.AttendanceSession{CheckIn: in, CheckOut: out}
+	}
+
+	tests := []struct {
+		name       string
+		sessions   []models.AttendanceSession
+		worked     float64
+		late       int
+		earlyLeave int
+		undertime  int
+		overtime   int
+	}{
+		{name: "on time", sessions: []models.AttendanceSession{session(at(9, 0, 0), at(17, 0, 0))}, worked: 8},
+		{name: "within grace period", sessions: []models.AttendanceSession{session(at(9, 10, 0), at(17, 0, 0))}, worked: 7.83, undertime: 10},
+		{name: "late counts from shift start", sessions: []models.AttendanceSession{session(at(9, 11, 0), at(17, 0, 0))}, worked: 7.82, late: 11, undertime: 11},
+		{name: "partial minutes are dropped from lateness and rounded in undertime", sessions: []models.AttendanceSession{session(at(9, 15, 30), at(17, 0, 0))}, worked: 7.74, late: 15, undertime: 16},
+		{name: "early leave", sessions: []models.AttendanceSession{session(at(9, 0, 0), at(16, 20, 0))}, worked: 7.33, earlyLeave: 40, undertime: 40},
+		{name: "break between sessions is undertime", sessions: []models.AttendanceSession{session(at(9, 0, 0), at(12, 0, 0)), session(at(13, 0, 0), at(17, 0, 0))}, worked: 7, undertime: 60},
+		{name: "worked past the shift", sessions: []models.AttendanceSession{session(at(9, 0, 0), at(18, 30, 0))}, worked: 9.5, overtime: 90},
+		{name: "open day has no early leave or undertime yet", sessions: []models.AttendanceSession{session(at(9, 30, 0), nil)}, late: 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			att := &models.Attendance{
+				Date:     time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+				Timezone: "UTC",
+				Shift:    shift,
+				Sessions: tt.sessions,
+			}
+			att.CheckIn = tt.sessions[0].CheckIn
+			att.CheckOut = tt.sessions[len(tt.sessions)-1].CheckOut
+			service := services.NewAttendanceService(&stubAttendanceListRepo{attendances: []*models.Attendance{att}}, nil, nil, nil, nil, nil, nil, nil, config.AttendanceConfig{})
+
+			attendances, _, err := service.GetAttendanceList(1, "2025-06-02", "2025-06-02")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.worked, attendances[0].WorkedHours)
+			assert.Equal(t, tt.late, attendances[0].LateMinutes)
+			assert.Equal(t, tt.earlyLeave, attendances[0].EarlyLeaveMinutes)
+			assert.Equal(t, tt.undertime, attendances[0].UndertimeMinutes)
+			assert.Equal(t, tt.overtime, attendances[0].OvertimeMinutes)
+		})
+	}
+}
+
+func TestPayslipPenaltyDeductions(t *testing.T) {
+	// February 2025 has 20 working days
+	period := &models.PayrollPeriod{
+		BaseModel: models.BaseModel{Model: gorm.Model{ID: 1}},
+		StartDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+	}
+	penalties := models.AttendancePenaltySummary{LateDays: 2, LateMinutes: 25, UndertimeMinutes: 90}
+
+	tests := []struct {
+		name      string
+		salary    float64
+		penalties models.AttendancePenaltySummary
+		cfg       config.PayrollConfig
+		late      float64
+		undertime float64
+	}{
+		{name: "no penalties configured", salary: 4000000, penalties: penalties},
+		{name: "late per minute and per occurrence", salary: 4000000, penalties: penalties,
+			cfg: config.PayrollConfig{LatePenaltyPerMinute: 1000, LatePenaltyPerOccurrence: 5000}, late: 35000},
+		{name: "undertime at the hourly rate", salary: 4000000, penalties: penalties,
+			cfg: config.PayrollConfig{DeductUndertime: true}, undertime: 37500},
+		{name: "deductions are rounded to cents", salary: 3333333, penalties: models.AttendancePenaltySummary{LateDays: 1, LateMinutes: 7, UndertimeMinutes: 7},
+			cfg: config.PayrollConfig{LatePenaltyPerMinute: 333.333, DeductUndertime: true}, late: 2333.33, undertime: 2430.56},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attendanceRepo := &stubPayslipAttendanceRepo{attended: 20, penalties: tt.penalties}
+			service := services.NewPayslipService(nil, attendanceRepo, &stubPayslipOvertimeRepo{}, &stubPayslipReimbursementRepo{},
+				nil, nil, nil, nil, nil, &stubDueLoanRepo{}, &stubAllowanceRepo{}, tt.cfg)
+
+			payslip, err := service.PreviewPayslip(1, period, tt.salary)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.late, payslip.LateDeduction)
+			assert.Equal(t, tt.undertime, payslip.UndertimeDeduction)
+			assert.Equal(t, int(tt.penalties.LateMinutes), payslip.LateMinutes)
+			assert.InDelta(t, 20*payslip.AttendanceEarnings-tt.late-tt.undertime, payslip.TakeHomePay, 0.01)
+		})
+	}
+}
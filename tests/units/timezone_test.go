@@ -0,0 +1,72 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/galiherlangga/go-attendance/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDateIn(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	t.Run("Early morning check-in stays on the local date", func(t *testing.T) {
+		// 06:30 in Jakarta is still the previous day in UTC
+		checkIn := time.Date(2025, 6, 11, 6, 30, 0, 0, jakarta)
+		assert.Equal(t, time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC), utils.DateIn(checkIn, jakarta))
+		assert.Equal(t, time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), utils.DateIn(checkIn, time.UTC))
+	})
+
+	t.Run("Civil date keeps the date as written", func(t *testing.T) {
+		date := time.Date(2025, 6, 30, 23, 59, 59, 0, jakarta)
+		assert.Equal(t, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), utils.CivilDate(date))
+	})
+}
+
+func TestLocationOrDefault(t *testing.T) {
+	empty, invalid, jakarta := "", "Mars/Olympus", "Asia/Jakarta"
+
+	assert.Equal(t, utils.CompanyLocation(), utils.LocationOrDefault(nil, &empty))
+	assert.Equal(t, utils.CompanyLocation(), utils.LocationOrDefault(&invalid))
+	assert.Equal(t, "Asia/Jakarta", utils.LocationOrDefault(nil, &jakarta).String())
+}
+
+func TestAppLocation(t *testing.T) {
+	location, err := config.AppConfig{}.Location()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Local, location, "an unset timezone is the server's own")
+
+	location, err = config.AppConfig{Timezone: "Asia/Jakarta"}.Location()
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Jakarta", location.String())
+
+	_, err = config.AppConfig{Timezone: "Mars/Olympus"}.Location()
+	assert.Error(t, err)
+}
+
+func TestSetUserTimezone(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserService(&stubUserRepo{users: map[uint]*models.User{1: {Model: gorm.Model{ID: 1}}}})
+	timezone, invalid, empty := "Asia/Jakarta", "Mars/Olympus", ""
+
+	user, err := service.SetTimezone(ctx, 1, &timezone)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Jakarta", *user.Timezone)
+
+	_, err = service.SetTimezone(ctx, 1, &invalid)
+	assert.EqualError(t, err, "invalid timezone")
+	assert.Equal(t, "Asia/Jakarta", *user.Timezone, "an invalid timezone leaves the user alone")
+
+	user, err = service.SetTimezone(ctx, 1, &empty)
+	assert.NoError(t, err)
+	assert.Nil(t, user.Timezone)
+
+	_, err = service.SetTimezone(ctx, 2, &timezone)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
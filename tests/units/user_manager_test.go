@@ -0,0 +1,161 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galiherlangga/go-attendance/app/models"
+	"github.com/galiherlangga/go-attendance/app/repositories"
+	"github.com/galiherlangga/go-attendance/app/services"
+	"github.com/galiherlangga/go-attendance/config"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubCorrectionRepo applies reviews to attendanceRepo and only saves the ones that applied,
+// as the transaction would.
+type stubCorrectionRepo struct {
+	repositories.AttendanceCorrectionRepository
+	attendanceRepo repositories.AttendanceRepository
+	saved          []models.AttendanceCorrection
+}
+
+func (r *stubCorrectionRepo) ReviewCorrection(ctx context.Context, correction *models.AttendanceCorrection, apply func(repo repositories.AttendanceRepository) error) (*models.AttendanceCorrection, error) {
+	if apply != nil {
+		if err := apply(r.attendanceRepo); err != nil {
+			return nil, err
+		}
+	}
+	r.saved = append(r.saved, *correction)
+	return correction, nil
+}
+
+func TestCorrectionReviewer(t *testing.T) {
+	const admin, manager, otherManager, employee, colleague uint = 1, 2, 3, 4, 5
+	managerID := manager
+	userRepo := &stubUserRepo{users: map[uint]*models.User{
+		admin:        {Model: gorm.Model{ID: admin}, Role: models.Role{Name: models.RoleAdmin}},
+		manager:      {Model: gorm.Model{ID: manager}, Role: models.Role{Name: models.RoleManager}},
+		otherManager: {Model: gorm.Model{ID: otherManager}, Role: models.Role{Name: models.RoleManager}},
+		employee:     {Model: gorm.Model{ID: employee}, Role: models.Role{Name: models.RoleUser}, ManagerID: &managerID},
+		colleague:    {Model: gorm.Model{ID: colleague}, Role: models.Role{Name: models.RoleUser}, ManagerID: &managerID},
+	}}
+	service := services.NewAttendanceCorrectionService(&stubCorrectionRepo{}, nil, userRepo, nil, nil, &stubNotificationService{})
+
+	tests := []struct {
+		name     string
+		reviewer uint
+		employee uint
+		allowed  bool
+	}{
+		{name: "admin reviews anyone", reviewer: admin, employee: employee, allowed: true},
+		{name: "manager reviews a direct report", reviewer: manager, employee: employee, allowed: true},
+		{name: "manager cannot review another team", reviewer: otherManager, employee: employee},
+		{name: "manager cannot review their own correction", reviewer: manager, employee: manager},
+		{name: "employee cannot review a colleague", reviewer: colleague, employee: employee},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			correction := &models.AttendanceCorrection{UserID: tt.employee, Status: models.CorrectionStatusPending}
+			reviewed, err := service.RejectCorrection(context.Background(), correction, tt.reviewer, nil)
+			if tt.allowed {
+				assert.NoError(t, err)
+				assert.Equal(t, models.CorrectionStatusRejected, reviewed.Status)
+				return
+			}
+			assert.ErrorIs(t, err, services.ErrForbidden)
+		})
+	}
+}
+
+func TestApproveCorrection(t *testing.T) {
+	const admin, employee uint = 1, 2
+	at := func(hour int) *time.Time {
+		tm := time.Date(2025, 6, 2, hour, 0, 0, 0, time.UTC)
+		return &tm
+	}
+	userRepo := &stubUserRepo{users: map[uint]*models.User{
+		admin:    {Model: gorm.Model{ID: admin}, Role: models.Role{Name: models.RoleAdmin}},
+		employee: {Model: gorm.Model{ID: employee}, Role: models.Role{Name: models.RoleUser}},
+	}}
+	setup := func() (services.AttendanceCorrectionService, *stubSessionAttendanceRepo, *stubCorrectionRepo) {
+		cache, _ := redismock.NewClientMock()
+		attendanceRepo := &stubSessionAttendanceRepo{attendance: &models.Attendance{
+			BaseModel: models.BaseModel{Model: gorm.Model{ID: 1}},
+			UserID:    employee,
+			Date:      time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+			CheckIn:   at(9),
+			CheckOut:  at(17),
+		}}
+		correctionRepo := &stubCorrectionRepo{attendanceRepo: attendanceRepo}
+		periodRepo := &stubPayrollPeriodRepo{}
+		attendanceService := services.NewAttendanceService(attendanceRepo, &stubNoSiteRepo{}, &stubAllDayShiftRepo{}, userRepo, periodRepo, nil, nil, cache, config.AttendanceConfig{})
+		return services.NewAttendanceCorrectionService(correctionRepo, attendanceRepo, userRepo, periodRepo, attendanceService, &stubNotificationService{}),
+			attendanceRepo, correctionRepo
+	}
+	pending := func(checkIn *time.Time) *models.AttendanceCorrection {
+		return &models.AttendanceCorrection{AttendanceID: 1, UserID: employee, CheckIn: checkIn, Status: models.CorrectionStatusPending}
+	}
+
+	t.Run("an earlier check-in is applied with the approval", func(t *testing.T) {
+		service, attendanceRepo, correctionRepo := setup()
+		_, err := service.ApproveCorrection(context.Background(), pending(at(8)), admin, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, *at(8), *attendanceRepo.attendance.CheckIn)
+		assert.Len(t, correctionRepo.saved, 1)
+		assert.Equal(t, models.CorrectionStatusApproved, correctionRepo.saved[0].Status)
+	})
+
+	t.Run("a check-in after the existing check-out is rejected without approving", func(t *testing.T) {
+		service, attendanceRepo, correctionRepo := setup()
+		_, err := service.ApproveCorrection(context.Background(), pending(at(18)), admin, nil)
+		assert.EqualError(t, err, "check-out must be after check-in")
+		assert.Equal(t, *at(9), *attendanceRepo.attendance.CheckIn)
+		assert.Empty(t, correctionRepo.saved)
+	})
+}
+
+func TestUserManagerAssignment(t *testing.T) {
+	ctx := context.Background()
+	newService := func() services.UserService {
+		return services.NewUserService(&stubUserRepo{users: map[uint]*models.User{
+			1: {Model: gorm.Model{ID: 1}, Name: "Manager", Role: models.Role{Name: models.RoleManager}},
+			2: {Model: gorm.Model{ID: 2}, Name: "Employee", Role: models.Role{Name: models.RoleUser}},
+			3: {Model: gorm.Model{ID: 3}, Name: "Colleague", Role: models.Role{Name: models.RoleUser}},
+		}})
+	}
+	id := func(id uint) *uint { return &id }
+
+	t.Run("a user reports to an existing manager", func(t *testing.T) {
+		service := newService()
+		user, err := service.AssignManager(ctx, 2, id(1))
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), *user.ManagerID)
+
+		user, err = service.AssignManager(ctx, 2, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, user.ManagerID)
+	})
+
+	t.Run("an unknown manager is rejected", func(t *testing.T) {
+		_, err := newService().AssignManager(ctx, 2, id(99))
+		assert.EqualError(t, err, "manager not found")
+	})
+
+	t.Run("the manager must have the manager role", func(t *testing.T) {
+		_, err := newService().AssignManager(ctx, 2, id(3))
+		assert.EqualError(t, err, "manager must have the manager role")
+	})
+
+	t.Run("a user cannot manage themselves", func(t *testing.T) {
+		_, err := newService().AssignManager(ctx, 1, id(1))
+		assert.EqualError(t, err, "a user cannot be their own manager")
+	})
+
+	t.Run("an unknown user is not found", func(t *testing.T) {
+		_, err := newService().AssignManager(ctx, 99, id(1))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}